@@ -88,3 +88,5 @@ func (col *Column) SetInt64(field NumberField, value int64) { col.Set(field, val
 func (col *Column) SetString(field StringField, value string) { col.Set(field, value) }
 
 func (col *Column) SetTime(field TimeField, value time.Time) { col.Set(field, value) }
+
+func (col *Column) SetUUID(field UUIDField, value [16]byte) { col.Set(field, field.uuidValue(value)) }
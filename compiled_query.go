@@ -0,0 +1,390 @@
+package sq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CompiledFetch is a query that has already been built into an SQL string,
+// together with the rowmapper used to scan its results. Its values can be
+// changed on every call by using named parameters i.e. Param("name", value)
+// and passing in the new values as Params. A CompiledFetch may optionally be
+// prepared, in which case it holds on to an *sql.Stmt until it is closed.
+type CompiledFetch[T any] struct {
+	dialect   string
+	env       map[string]interface{}
+	queryType string
+	query     string
+	args      []interface{}
+	params    map[string][]int
+	rowmapper func(*Row) T
+	stmt      *sql.Stmt
+}
+
+// CompiledExec is the CompiledFetch counterpart for Exec.
+type CompiledExec struct {
+	dialect   string
+	env       map[string]interface{}
+	queryType string
+	query     string
+	args      []interface{}
+	params    map[string][]int
+	stmt      *sql.Stmt
+	tableMod  [2]string
+}
+
+func CompileFetch[T any](q Query, rowmapper func(*Row) T) (cf CompiledFetch[T], err error) {
+	if q == nil {
+		return cf, errors.New("sq: query is nil")
+	}
+	if rowmapper == nil {
+		return cf, errors.New("sq: cannot call CompileFetch without a rowmapper")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case error:
+				err = r
+			default:
+				panic(r)
+			}
+		}
+	}()
+	cf.dialect = q.GetDialect()
+	cf.env, cf.queryType = queryEnvAndType(q)
+	cf.rowmapper = rowmapper
+	r := &Row{}
+	rowmapper(r)
+	fields, _ := RowResult(r)
+	if len(fields) == 0 {
+		return cf, errors.New("sq: rowmapper did not fetch any fields")
+	}
+	q, err = q.SetFetchableFields(fields)
+	if err != nil {
+		return cf, err
+	}
+	cf.query, cf.args, cf.params, err = ToSQL(cf.dialect, q)
+	if err != nil {
+		return cf, err
+	}
+	return cf, nil
+}
+
+func CompileExec(q Query) (ce CompiledExec, err error) {
+	if q == nil {
+		return ce, errors.New("sq: query is nil")
+	}
+	ce.dialect = q.GetDialect()
+	ce.env, ce.queryType = queryEnvAndType(q)
+	switch q := q.(type) {
+	case InsertQuery:
+		if table := q.IntoTable; table != nil {
+			ce.tableMod = [2]string{table.GetSchema(), table.GetName()}
+		}
+	case UpdateQuery:
+		if table := q.UpdateTable; table != nil {
+			ce.tableMod = [2]string{table.GetSchema(), table.GetName()}
+		}
+	case DeleteQuery:
+		if len(q.FromTables) > 0 && q.FromTables[0] != nil {
+			ce.tableMod = [2]string{q.FromTables[0].GetSchema(), q.FromTables[0].GetName()}
+		}
+	}
+	ce.query, ce.args, ce.params, err = ToSQL(ce.dialect, q)
+	if err != nil {
+		return ce, err
+	}
+	return ce, nil
+}
+
+// GetSQL returns the compiled query string, its default args and the index
+// of each named parameter in args.
+func (cf CompiledFetch[T]) GetSQL() (query string, args []interface{}, params map[string][]int) {
+	return cf.query, cf.args, cf.params
+}
+
+// Prepare returns a copy of the CompiledFetch that runs as a prepared
+// statement. The prepared statement must be released with Close.
+func (cf CompiledFetch[T]) Prepare(db DB) (CompiledFetch[T], error) {
+	return cf.PrepareContext(context.Background(), db)
+}
+
+func (cf CompiledFetch[T]) PrepareContext(ctx context.Context, db DB) (CompiledFetch[T], error) {
+	var err error
+	if db == nil {
+		return cf, errors.New("sq: db is nil")
+	}
+	cf.stmt, err = db.PrepareContext(ctx, cf.query)
+	if err != nil {
+		return cf, err
+	}
+	return cf, nil
+}
+
+// Close releases the prepared statement, if any.
+func (cf CompiledFetch[T]) Close() error {
+	if cf.stmt == nil {
+		return nil
+	}
+	return cf.stmt.Close()
+}
+
+func (cf CompiledFetch[T]) FetchCursor(ctx context.Context, db DB, params Params) (*Cursor[T], error) {
+	return cf.fetchCursor(ctx, db, params, 1)
+}
+
+func (cf CompiledFetch[T]) fetchCursor(ctx context.Context, db DB, params Params, skip int) (*Cursor[T], error) {
+	if db == nil {
+		return nil, errors.New("sq: db is nil")
+	}
+	if cf.rowmapper == nil {
+		return nil, errors.New("sq: CompiledFetch was not created with CompileFetch")
+	}
+	args, err := bindParams(cf.dialect, cf.args, cf.params, params)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := newCursor(ctx, db, cf.rowmapper, skip+1)
+	if err != nil {
+		return nil, err
+	}
+	cursor.stats.Dialect = cf.dialect
+	cursor.stats.Env = cf.env
+	cursor.stats.QueryType = cf.queryType
+	cursor.stats.Query = cf.query
+	cursor.stats.Args = args
+	err = cursor.open(func() (*sql.Rows, error) {
+		if cf.stmt != nil {
+			return txStmt(ctx, db, cf.stmt).QueryContext(ctx, args...)
+		}
+		return db.QueryContext(ctx, cf.query, args...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+// FetchOne returns the first row of the query, or sql.ErrNoRows if there are
+// no rows. Unlike the top level FetchOne, LIMIT 1 is not applied since the
+// query has already been compiled.
+func (cf CompiledFetch[T]) FetchOne(db DB, params Params) (T, error) {
+	return cf.fetchOne(context.Background(), db, params, 1)
+}
+
+func (cf CompiledFetch[T]) FetchOneContext(ctx context.Context, db DB, params Params) (T, error) {
+	return cf.fetchOne(ctx, db, params, 1)
+}
+
+func (cf CompiledFetch[T]) fetchOne(ctx context.Context, db DB, params Params, skip int) (result T, err error) {
+	cursor, err := cf.fetchCursor(ctx, db, params, skip+1)
+	if err != nil {
+		return result, err
+	}
+	defer cursor.Close()
+	if !cursor.Next() {
+		if err = cursor.Err(); err != nil {
+			return result, err
+		}
+		return result, sql.ErrNoRows
+	}
+	result, err = cursor.Result()
+	if err != nil {
+		return result, err
+	}
+	return result, cursor.Close()
+}
+
+func (cf CompiledFetch[T]) FetchSlice(db DB, params Params) ([]T, error) {
+	return cf.fetchSlice(context.Background(), db, params, 1)
+}
+
+func (cf CompiledFetch[T]) FetchSliceContext(ctx context.Context, db DB, params Params) ([]T, error) {
+	return cf.fetchSlice(ctx, db, params, 1)
+}
+
+func (cf CompiledFetch[T]) fetchSlice(ctx context.Context, db DB, params Params, skip int) (results []T, err error) {
+	cursor, err := cf.fetchCursor(ctx, db, params, skip+1)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		result, err := cursor.Result()
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	if err = cursor.Err(); err != nil {
+		return results, err
+	}
+	return results, cursor.Close()
+}
+
+// GetSQL returns the compiled query string, its default args and the index
+// of each named parameter in args.
+func (ce CompiledExec) GetSQL() (query string, args []interface{}, params map[string][]int) {
+	return ce.query, ce.args, ce.params
+}
+
+// Prepare returns a copy of the CompiledExec that runs as a prepared
+// statement. The prepared statement must be released with Close.
+func (ce CompiledExec) Prepare(db DB) (CompiledExec, error) {
+	return ce.PrepareContext(context.Background(), db)
+}
+
+func (ce CompiledExec) PrepareContext(ctx context.Context, db DB) (CompiledExec, error) {
+	var err error
+	if db == nil {
+		return ce, errors.New("sq: db is nil")
+	}
+	ce.stmt, err = db.PrepareContext(ctx, ce.query)
+	if err != nil {
+		return ce, err
+	}
+	return ce, nil
+}
+
+// Close releases the prepared statement, if any.
+func (ce CompiledExec) Close() error {
+	if ce.stmt == nil {
+		return nil
+	}
+	return ce.stmt.Close()
+}
+
+func (ce CompiledExec) Exec(db DB, params Params) (rowsAffected, lastInsertID int64, err error) {
+	return ce.exec(context.Background(), db, params, 1)
+}
+
+func (ce CompiledExec) ExecContext(ctx context.Context, db DB, params Params) (rowsAffected, lastInsertID int64, err error) {
+	return ce.exec(ctx, db, params, 1)
+}
+
+func (ce CompiledExec) exec(ctx context.Context, db DB, params Params, skip int) (rowsAffected, lastInsertID int64, err error) {
+	if db == nil {
+		return 0, 0, errors.New("sq: db is nil")
+	}
+	var stats QueryStats
+	var logQueryStats func(ctx context.Context, stats QueryStats)
+	var logSettings LogSettings
+	if loggerDB, ok := db.(LoggerDB); ok {
+		logSettings, err = loggerDB.GetLogSettings()
+		if err != nil {
+			if !errors.Is(err, ErrLoggerUnsupported) {
+				return 0, 0, err
+			}
+		} else {
+			logQueryStats = loggerDB.LogQueryStats
+		}
+	}
+	if logQueryStats != nil && logSettings.GetCallerInfo {
+		stats.CallerFile, stats.CallerLine, stats.CallerFunction = caller(skip)
+	}
+	stats.Dialect = ce.dialect
+	stats.Env = ce.env
+	stats.QueryType = ce.queryType
+	stats.TableModified = ce.tableMod
+	stats.Query = ce.query
+	defer func() {
+		if logQueryStats == nil {
+			return
+		}
+		stats.Error = err
+		if logSettings.AsyncLogging {
+			go logQueryStats(ctx, stats)
+		} else {
+			logQueryStats(ctx, stats)
+		}
+	}()
+	stats.Args, err = bindParams(ce.dialect, ce.args, ce.params, params)
+	if err != nil {
+		return 0, 0, err
+	}
+	var start time.Time
+	if logSettings.TimeQuery {
+		start = time.Now()
+	}
+	var result sql.Result
+	if ce.stmt != nil {
+		result, err = txStmt(ctx, db, ce.stmt).ExecContext(ctx, stats.Args...)
+	} else {
+		result, err = db.ExecContext(ctx, stats.Query, stats.Args...)
+	}
+	if logSettings.TimeQuery {
+		stats.TimeTaken = time.Since(start)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+	stats.RowsAffected.Valid = true
+	stats.RowsAffected.Int64 = rowsAffected
+	if stats.Dialect == DialectSQLite || stats.Dialect == DialectMySQL {
+		lastInsertID, err = result.LastInsertId()
+		if err != nil {
+			return 0, 0, err
+		}
+		stats.LastInsertID.Valid = true
+		stats.LastInsertID.Int64 = lastInsertID
+	}
+	return rowsAffected, lastInsertID, nil
+}
+
+// bindParams returns a copy of args with the named parameters replaced by
+// their values in params. Every name in params must correspond to a named
+// parameter in the compiled query. UUID values are converted for the dialect
+// the same way they are at compile time, but slices cannot be bound because
+// the number of placeholders was fixed when the query was compiled.
+func bindParams(dialect string, args []interface{}, paramIndex map[string][]int, params Params) ([]interface{}, error) {
+	if len(params) == 0 {
+		return args, nil
+	}
+	boundArgs := make([]interface{}, len(args))
+	copy(boundArgs, args)
+	for name, value := range params {
+		indices, ok := paramIndex[name]
+		if !ok {
+			availableParams := make([]string, 0, len(paramIndex))
+			for name := range paramIndex {
+				availableParams = append(availableParams, name)
+			}
+			sort.Strings(availableParams)
+			return nil, fmt.Errorf("sq: named parameter %s not found (available params: %s)", name, strings.Join(availableParams, ", "))
+		}
+		if isExplodableSlice(value) {
+			return nil, fmt.Errorf("sq: named parameter %s cannot be bound to a slice (%T) in a compiled query", name, value)
+		}
+		value = uuidArg(dialect, value)
+		for _, i := range indices {
+			if namedArg, ok := boundArgs[i].(sql.NamedArg); ok {
+				boundArgs[i] = sql.Named(namedArg.Name, value)
+			} else {
+				boundArgs[i] = value
+			}
+		}
+	}
+	return boundArgs, nil
+}
+
+// txStmt returns a transaction-specific version of stmt if db is a
+// transaction, so that a statement prepared on an *sql.DB can also be used
+// inside a transaction.
+func txStmt(ctx context.Context, db DB, stmt *sql.Stmt) *sql.Stmt {
+	if ldb, ok := db.(loggerDB); ok {
+		db = ldb.DB
+	}
+	if tx, ok := db.(*sql.Tx); ok {
+		return tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
@@ -0,0 +1,181 @@
+package sq
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_CompileFetch(t *testing.T) {
+	t.Parallel()
+	ACTOR := xNEW_ACTOR("")
+	cf, err := CompileFetch(SQLite.
+		From(ACTOR).
+		Where(
+			Eq(ACTOR.ACTOR_ID, Param("actor_id", 0)),
+			Eq(ACTOR.FIRST_NAME, Param("first_name", "")),
+		),
+		func(row *Row) string {
+			return row.String(ACTOR.LAST_NAME)
+		},
+	)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	gotQuery, gotArgs, gotParams := cf.GetSQL()
+	wantQuery := "SELECT actor.last_name FROM actor WHERE actor.actor_id = $1 AND actor.first_name = $2"
+	if diff := testutil.Diff(gotQuery, wantQuery); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	if diff := testutil.Diff(gotParams, map[string][]int{"actor_id": {0}, "first_name": {1}}); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	boundArgs, err := bindParams(DialectSQLite, gotArgs, gotParams, Params{"first_name": "PENELOPE"})
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(boundArgs, []interface{}{0, "PENELOPE"}); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	// the compiled args must not be modified by binding
+	if diff := testutil.Diff(gotArgs, []interface{}{0, ""}); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	_, err = bindParams(DialectSQLite, gotArgs, gotParams, Params{"last_name": "GUINESS"})
+	if err == nil {
+		t.Error(testutil.Callers(), "expected error for unknown param but got nil")
+	}
+}
+
+func Test_bindParams(t *testing.T) {
+	type TT struct {
+		description string
+		dialect     string
+		args        []interface{}
+		paramIndex  map[string][]int
+		params      Params
+		wantArgs    []interface{}
+	}
+
+	uuid := [16]byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}
+	tests := []TT{
+		{
+			description: "no params",
+			args:        []interface{}{1, 2},
+			wantArgs:    []interface{}{1, 2},
+		},
+		{
+			description: "repeated param",
+			args:        []interface{}{1, 2, 1},
+			paramIndex:  map[string][]int{"a": {0, 2}, "b": {1}},
+			params:      Params{"a": 7},
+			wantArgs:    []interface{}{7, 2, 7},
+		},
+		{
+			description: "sql.NamedArg is preserved",
+			args:        []interface{}{sql.Named("a", 1)},
+			paramIndex:  map[string][]int{"a": {0}},
+			params:      Params{"a": 7},
+			wantArgs:    []interface{}{sql.Named("a", 7)},
+		},
+		{
+			description: "mysql UUIDValue",
+			dialect:     DialectMySQL,
+			args:        []interface{}{nil},
+			paramIndex:  map[string][]int{"a": {0}},
+			params:      Params{"a": UUIDValue{UUID: uuid}},
+			wantArgs:    []interface{}{uuid[:]},
+		},
+		{
+			description: "sqlite UUIDValue",
+			dialect:     DialectSQLite,
+			args:        []interface{}{nil, nil},
+			paramIndex:  map[string][]int{"a": {0}, "b": {1}},
+			params:      Params{"a": UUIDValue{UUID: uuid}, "b": UUIDValue{UUID: uuid, SQLiteBlob: true}},
+			wantArgs:    []interface{}{UUIDString(uuid), uuid[:]},
+		},
+		{
+			description: "postgres NullUUID",
+			dialect:     DialectPostgres,
+			args:        []interface{}{nil, nil},
+			paramIndex:  map[string][]int{"a": {0}, "b": {1}},
+			params:      Params{"a": NullUUID{UUID: uuid, Valid: true}, "b": NullUUID{}},
+			wantArgs:    []interface{}{UUIDString(uuid), nil},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.description, func(t *testing.T) {
+			t.Parallel()
+			gotArgs, err := bindParams(tt.dialect, tt.args, tt.paramIndex, tt.params)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		})
+	}
+}
+
+func Test_bindParamsSlice(t *testing.T) {
+	t.Parallel()
+	_, err := bindParams(DialectPostgres, []interface{}{1, 2}, map[string][]int{"ids": {0, 1}}, Params{"ids": []int{1, 2, 3}})
+	if err == nil {
+		t.Error(testutil.Callers(), "expected error for slice param but got nil")
+	}
+}
+
+func TestSQLiteSakilaCompiled(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+
+	t.Run("CompiledFetch", func(t *testing.T) {
+		t.Parallel()
+		wantAnswer := sakilaAnswer4()
+		ACTOR := xNEW_ACTOR("")
+		cf, err := CompileFetch(SQLite.
+			From(ACTOR).
+			Where(Predicatef("{} LIKE {}", ACTOR.LAST_NAME, Param("pattern", ""))).
+			OrderBy(ACTOR.ACTOR_ID),
+			func(row *Row) Actor {
+				return Actor{
+					ActorID:    row.Int(ACTOR.ACTOR_ID),
+					FirstName:  row.String(ACTOR.FIRST_NAME),
+					LastName:   row.String(ACTOR.LAST_NAME),
+					LastUpdate: row.Time(ACTOR.LAST_UPDATE),
+				}
+			},
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		cf, err = cf.Prepare(sqliteDB)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		defer cf.Close()
+		gotAnswer, err := cf.FetchSlice(Log(sqliteDB), Params{"pattern": "%GEN%"})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotAnswer, wantAnswer); diff != "" {
+			t.Fatal(testutil.Callers(), diff)
+		}
+		gotActor, err := cf.FetchOne(Log(sqliteDB), Params{"pattern": "%GEN%"})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotActor, wantAnswer[0]); diff != "" {
+			t.Fatal(testutil.Callers(), diff)
+		}
+		_, err = cf.FetchOne(Log(sqliteDB), Params{"pattern": "%does not exist%"})
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf(testutil.Callers()+"expected sql.ErrNoRows, got %v", err)
+		}
+	})
+}
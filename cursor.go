@@ -0,0 +1,277 @@
+package sq
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Cursor iterates over the results of a query one row at a time. Unlike
+// Fetch, the caller controls when the next row is read so a Cursor can be
+// paused, handed across goroutines or returned from a function. A Cursor must
+// always be closed.
+type Cursor[T any] struct {
+	ctx           context.Context
+	row           *Row
+	rowmapper     func(*Row) T
+	rows          *sql.Rows
+	fields        []Field
+	dest          []interface{}
+	stats         QueryStats
+	logSettings   LogSettings
+	logQueryStats func(ctx context.Context, stats QueryStats)
+	resultsBuf    *bytes.Buffer
+	fieldNames    []string
+	rowCount      int64
+	hasRow        bool
+	err           error
+	closed        bool
+}
+
+func FetchCursor[T any](ctx context.Context, db DB, q Query, rowmapper func(*Row) T) (*Cursor[T], error) {
+	return fetchCursor(ctx, db, q, rowmapper, 1)
+}
+
+func fetchCursor[T any](ctx context.Context, db DB, q Query, rowmapper func(*Row) T, skip int) (*Cursor[T], error) {
+	if db == nil {
+		return nil, errors.New("sq: db is nil")
+	}
+	if q == nil {
+		return nil, errors.New("sq: query is nil")
+	}
+	if rowmapper == nil {
+		return nil, errors.New("sq: cannot call FetchCursor without a rowmapper")
+	}
+	cursor, err := newCursor(ctx, db, rowmapper, skip+1)
+	if err != nil {
+		return nil, err
+	}
+	cursor.stats.Env, cursor.stats.QueryType = queryEnvAndType(q)
+	cursor.stats.Dialect = q.GetDialect()
+	q, err = q.SetFetchableFields(cursor.fields)
+	if err != nil {
+		return nil, err
+	}
+	buf := bufpool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufpool.Put(buf)
+	}()
+	err = q.AppendSQL(cursor.stats.Dialect, buf, &cursor.stats.Args, make(map[string][]int), nil)
+	if err != nil {
+		cursor.stats.Query = buf.String() + "%!(error=" + err.Error() + ")"
+		return nil, cursor.abort(err)
+	}
+	cursor.stats.Query = buf.String()
+	err = cursor.open(func() (*sql.Rows, error) {
+		return db.QueryContext(ctx, cursor.stats.Query, cursor.stats.Args...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+func queryEnvAndType(q Query) (env map[string]interface{}, queryType string) {
+	switch q := q.(type) {
+	case SelectQuery:
+		return q.Env, "SELECT"
+	case InsertQuery:
+		return q.Env, "INSERT"
+	case UpdateQuery:
+		return q.Env, "UPDATE"
+	case DeleteQuery:
+		return q.Env, "DELETE"
+	}
+	return nil, ""
+}
+
+// newCursor sets up a cursor's logger and runs the rowmapper in the passive
+// phase so that the cursor knows which fields to fetch.
+func newCursor[T any](ctx context.Context, db DB, rowmapper func(*Row) T, skip int) (cursor *Cursor[T], err error) {
+	cursor = &Cursor[T]{
+		ctx:       ctx,
+		row:       &Row{},
+		rowmapper: rowmapper,
+	}
+	if loggerDB, ok := db.(LoggerDB); ok {
+		cursor.logSettings, err = loggerDB.GetLogSettings()
+		if err != nil {
+			if !errors.Is(err, ErrLoggerUnsupported) {
+				return nil, err
+			}
+		} else {
+			cursor.logQueryStats = loggerDB.LogQueryStats
+		}
+	}
+	if cursor.logQueryStats != nil && cursor.logSettings.GetCallerInfo {
+		cursor.stats.CallerFile, cursor.stats.CallerLine, cursor.stats.CallerFunction = caller(skip)
+	}
+	err = cursor.mapPassiveRow()
+	if err != nil {
+		return nil, err
+	}
+	cursor.fields, cursor.dest = RowResult(cursor.row)
+	return cursor, nil
+}
+
+// open runs the query and activates the cursor's row. cursor.stats.Query and
+// cursor.stats.Args must already be populated.
+func (cursor *Cursor[T]) open(query func() (*sql.Rows, error)) (err error) {
+	var start time.Time
+	if cursor.logSettings.TimeQuery {
+		start = time.Now()
+	}
+	cursor.rows, err = query()
+	if cursor.logSettings.TimeQuery {
+		cursor.stats.TimeTaken = time.Since(start)
+	}
+	if err != nil {
+		return cursor.abort(err)
+	}
+	if cursor.logQueryStats != nil && cursor.logSettings.ResultsLimit > 0 {
+		cursor.resultsBuf = bufpool.Get().(*bytes.Buffer)
+	}
+	RowActivate(cursor.row)
+	return nil
+}
+
+// abort logs the query stats of a cursor that could not be opened. Such a
+// cursor is never returned to the caller and will never be closed, so its
+// query stats have to be logged here instead.
+func (cursor *Cursor[T]) abort(err error) error {
+	if cursor.logQueryStats != nil {
+		cursor.stats.Error = err
+		cursor.log()
+	}
+	return err
+}
+
+// mapPassiveRow calls the rowmapper on the inactive row in order to collect
+// the fields to be fetched, converting any panicked errors into a returned
+// error.
+func (cursor *Cursor[T]) mapPassiveRow() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case error:
+				err = r
+			default:
+				panic(r)
+			}
+		}
+	}()
+	cursor.rowmapper(cursor.row)
+	return nil
+}
+
+// Next advances the cursor to the next row, returning false if there are no
+// more rows or if an error occurred.
+func (cursor *Cursor[T]) Next() bool {
+	cursor.hasRow = false
+	if cursor.closed || cursor.err != nil || len(cursor.dest) == 0 || RowClosed(cursor.row) {
+		return false
+	}
+	if !cursor.rows.Next() {
+		cursor.err = cursor.rows.Err()
+		return false
+	}
+	cursor.rowCount++
+	// Because dest and row.dest share the same backing array, scanning into
+	// dest is enough to make the values visible to the rowmapper.
+	err := cursor.rows.Scan(cursor.dest...)
+	if err != nil {
+		cursor.err = decorateScanError(cursor.stats.Dialect, cursor.fields, cursor.dest, err)
+		return false
+	}
+	if cursor.resultsBuf != nil && cursor.rowCount <= int64(cursor.logSettings.ResultsLimit) {
+		if len(cursor.fieldNames) == 0 {
+			cursor.fieldNames = computeFieldNames(cursor.stats.Dialect, cursor.fields)
+		}
+		accumulateResults(cursor.stats.Dialect, cursor.resultsBuf, cursor.fieldNames, cursor.dest, cursor.rowCount)
+	}
+	cursor.hasRow = true
+	return true
+}
+
+// Result runs the rowmapper on the current row and returns its result. It
+// returns an error if there is no current row, i.e. if Next has not been
+// called yet or its last call returned false.
+func (cursor *Cursor[T]) Result() (result T, err error) {
+	if cursor.closed {
+		return result, errors.New("sq: cursor is closed")
+	}
+	if cursor.rowCount == 0 {
+		return result, errors.New("sq: Result called before Next")
+	}
+	if !cursor.hasRow {
+		return result, errors.New("sq: Result called after Next returned false")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case error:
+				err = r
+			default:
+				panic(r)
+			}
+		}
+		if err != nil && cursor.err == nil {
+			cursor.err = err
+		}
+	}()
+	RowReset(cursor.row)
+	result = cursor.rowmapper(cursor.row)
+	err = RowProcessingError(cursor.row)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// RowCount returns the number of rows that the cursor has advanced through so
+// far.
+func (cursor *Cursor[T]) RowCount() int64 { return cursor.rowCount }
+
+// Err returns the first error encountered by the cursor, if any.
+func (cursor *Cursor[T]) Err() error { return cursor.err }
+
+// Close closes the cursor and logs its query stats. It is safe to call Close
+// multiple times.
+func (cursor *Cursor[T]) Close() error {
+	if cursor.closed {
+		return nil
+	}
+	cursor.closed = true
+	err := cursor.rows.Close()
+	if err != nil && cursor.err == nil {
+		cursor.err = err
+	}
+	if cursor.logQueryStats != nil {
+		if cursor.resultsBuf != nil {
+			if cursor.rowCount > int64(cursor.logSettings.ResultsLimit) {
+				cursor.resultsBuf.WriteString("\n...\n(" + strconv.FormatInt(cursor.rowCount-int64(cursor.logSettings.ResultsLimit), 10) + " more rows)")
+			}
+			cursor.stats.QueryResults = cursor.resultsBuf.String()
+			cursor.resultsBuf.Reset()
+			bufpool.Put(cursor.resultsBuf)
+			cursor.resultsBuf = nil
+		}
+		cursor.stats.Error = cursor.err
+		cursor.stats.RowCount.Valid = true
+		cursor.stats.RowCount.Int64 = cursor.rowCount
+		cursor.log()
+	}
+	return err
+}
+
+func (cursor *Cursor[T]) log() {
+	if cursor.logSettings.AsyncLogging {
+		go cursor.logQueryStats(cursor.ctx, cursor.stats)
+	} else {
+		cursor.logQueryStats(cursor.ctx, cursor.stats)
+	}
+}
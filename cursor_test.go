@@ -0,0 +1,59 @@
+package sq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func TestCursor(t *testing.T) {
+	ACTOR := xNEW_ACTOR("")
+	db := newActorDB(t, "DAVIS", "ALLEN", "BERRY")
+	cursor, err := FetchCursor(context.Background(), Log(db), SQLite.From(ACTOR).OrderBy(ACTOR.LAST_NAME), func(row *Row) string {
+		return row.String(ACTOR.LAST_NAME)
+	})
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer cursor.Close()
+	_, err = cursor.Result()
+	if err == nil {
+		t.Error(testutil.Callers(), "expected an error for Result before Next")
+	}
+	var gotNames []string
+	for cursor.Next() {
+		name, err := cursor.Result()
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		gotNames = append(gotNames, name)
+	}
+	if err := cursor.Err(); err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(gotNames, []string{"ALLEN", "BERRY", "DAVIS"}); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	if cursor.RowCount() != 3 {
+		t.Errorf(testutil.Callers()+" expected row count 3, got %d", cursor.RowCount())
+	}
+	// the last row must not be mapped again once Next has returned false
+	name, err := cursor.Result()
+	if err == nil || name != "" {
+		t.Errorf(testutil.Callers()+" expected an error and the zero value for Result after Next returned false, got %q, %v", name, err)
+	}
+	if err := cursor.Close(); err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if err := cursor.Close(); err != nil {
+		t.Error(testutil.Callers(), "second Close returned", err)
+	}
+	if cursor.Next() {
+		t.Error(testutil.Callers(), "Next returned true after Close")
+	}
+	_, err = cursor.Result()
+	if err == nil {
+		t.Error(testutil.Callers(), "expected an error for Result after Close")
+	}
+}
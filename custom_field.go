@@ -2,7 +2,9 @@ package sq
 
 import (
 	"bytes"
+	"database/sql"
 	"fmt"
+	"strconv"
 )
 
 type CustomField struct {
@@ -201,3 +203,73 @@ func (fs AliasFields) AppendSQLExclude(dialect string, buf *bytes.Buffer, args *
 	}
 	return nil
 }
+
+// appendOutputFields writes the fields of an sqlserver OUTPUT clause. Columns
+// belonging to the modified table must be qualified with INSERTED or DELETED
+// (the prefix) instead of the table name, so every field that renders as a
+// bare column name once the table qualifier is excluded gets the prefix.
+func appendOutputFields(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, prefix string, fs AliasFields, excludedTableQualifiers []string) error {
+	tmpbuf := bufpool.Get().(*bytes.Buffer)
+	defer func() {
+		tmpbuf.Reset()
+		bufpool.Put(tmpbuf)
+	}()
+	var alias string
+	var err error
+	for i, f := range fs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if f == nil {
+			return fmt.Errorf("field #%d is nil", i+1)
+		}
+		tmpbuf.Reset()
+		err = f.AppendSQLExclude(dialect, tmpbuf, args, params, nil, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("field #%d: %w", i+1, err)
+		}
+		if name := f.GetName(); name != "" && tmpbuf.String() == QuoteIdentifier(dialect, name) {
+			buf.WriteString(prefix + ".")
+		}
+		buf.Write(tmpbuf.Bytes())
+		if alias = f.GetAlias(); alias != "" {
+			buf.WriteString(" AS " + QuoteIdentifier(dialect, alias))
+		}
+	}
+	return nil
+}
+
+// appendReturningInto writes an oracle RETURNING ... INTO clause. The
+// returned values are written into dests, each of which is bound as an
+// sql.Out argument (unless it already is one).
+func appendReturningInto(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, fs AliasFields, dests []interface{}, excludedTableQualifiers []string) error {
+	if len(dests) != len(fs) {
+		return fmt.Errorf("oracle RETURNING has %d fields but %d INTO destinations", len(fs), len(dests))
+	}
+	var err error
+	buf.WriteString(" RETURNING ")
+	for i, f := range fs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if f == nil {
+			return fmt.Errorf("field #%d is nil", i+1)
+		}
+		err = f.AppendSQLExclude(dialect, buf, args, params, nil, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("field #%d: %w", i+1, err)
+		}
+	}
+	buf.WriteString(" INTO ")
+	for i, dest := range dests {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if _, ok := dest.(sql.Out); !ok {
+			dest = sql.Out{Dest: dest}
+		}
+		buf.WriteString(":" + strconv.Itoa(len(*args)+1))
+		*args = append(*args, dest)
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ type Column struct {
 	CollationName            string `json:",omitempty"`
 	ColumnDefault            string `json:",omitempty"`
 	ColumnComment            string `json:",omitempty"`
+	RenamedFrom              string `json:",omitempty"`
 	Ignore                   bool   `json:",omitempty"`
 }
 
@@ -262,3 +263,26 @@ func (cmd DropColumnCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *
 	}
 	return nil
 }
+
+// RenameColumnCommand renames a column. Unlike the other column commands, it is
+// a complete ALTER TABLE statement because postgres does not allow RENAME
+// COLUMN to be combined with other ALTER TABLE actions.
+type RenameColumnCommand struct {
+	TableSchema  string
+	TableName    string
+	ColumnName   string
+	RenameToName string
+	Ignore       bool
+}
+
+func (cmd RenameColumnCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	buf.WriteString("ALTER TABLE ")
+	if cmd.TableSchema != "" {
+		buf.WriteString(sq.QuoteIdentifier(dialect, cmd.TableSchema) + ".")
+	}
+	buf.WriteString(sq.QuoteIdentifier(dialect, cmd.TableName) + " RENAME COLUMN " + sq.QuoteIdentifier(dialect, cmd.ColumnName) + " TO " + sq.QuoteIdentifier(dialect, cmd.RenameToName))
+	return nil
+}
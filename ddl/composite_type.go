@@ -0,0 +1,164 @@
+package ddl
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/bokwoon95/sq"
+)
+
+// CompositeType is a postgres composite type, created with CREATE TYPE ... AS
+// (...).
+type CompositeType struct {
+	TypeSchema string                   `json:",omitempty"`
+	TypeName   string                   `json:",omitempty"`
+	Attributes []CompositeTypeAttribute `json:",omitempty"`
+	Ignore     bool                     `json:",omitempty"`
+}
+
+type CompositeTypeAttribute struct {
+	AttributeName string `json:",omitempty"`
+	AttributeType string `json:",omitempty"`
+	CollationName string `json:",omitempty"`
+}
+
+func writeCompositeTypeAttribute(dialect string, buf *bytes.Buffer, attribute CompositeTypeAttribute) {
+	buf.WriteString(sq.QuoteIdentifier(dialect, attribute.AttributeName) + " " + attribute.AttributeType)
+	if attribute.CollationName != "" {
+		buf.WriteString(` COLLATE "` + sq.EscapeQuote(attribute.CollationName, '"') + `"`)
+	}
+}
+
+type CreateCompositeTypeCommand struct {
+	CompositeType CompositeType
+	Ignore        bool
+}
+
+func (cmd CreateCompositeTypeCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=composite types", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("CREATE TYPE ")
+	writeQualifiedName(dialect, buf, cmd.CompositeType.TypeSchema, cmd.CompositeType.TypeName)
+	buf.WriteString(" AS (")
+	for i, attribute := range cmd.CompositeType.Attributes {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeCompositeTypeAttribute(dialect, buf, attribute)
+	}
+	buf.WriteString(")")
+	return nil
+}
+
+type AlterCompositeTypeCommand struct {
+	TypeSchema          string
+	TypeName            string
+	AddAttributes       []CompositeTypeAttribute
+	AlterAttributeTypes []CompositeTypeAttribute
+	DropAttributes      []string
+	Ignore              bool
+}
+
+func (cmd AlterCompositeTypeCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=composite types", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("ALTER TYPE ")
+	writeQualifiedName(dialect, buf, cmd.TypeSchema, cmd.TypeName)
+	var written bool
+	writeAction := func(action string) {
+		if written {
+			buf.WriteString("\n    ,")
+		} else {
+			buf.WriteString("\n    ")
+		}
+		written = true
+		buf.WriteString(action)
+	}
+	for _, attribute := range cmd.AddAttributes {
+		attributeBuf := &bytes.Buffer{}
+		writeCompositeTypeAttribute(dialect, attributeBuf, attribute)
+		writeAction("ADD ATTRIBUTE " + attributeBuf.String())
+	}
+	for _, attribute := range cmd.AlterAttributeTypes {
+		writeAction("ALTER ATTRIBUTE " + sq.QuoteIdentifier(dialect, attribute.AttributeName) + " SET DATA TYPE " + attribute.AttributeType)
+	}
+	for _, attributeName := range cmd.DropAttributes {
+		writeAction("DROP ATTRIBUTE IF EXISTS " + sq.QuoteIdentifier(dialect, attributeName))
+	}
+	if !written {
+		return fmt.Errorf("ALTER TYPE %s: no changes", cmd.TypeName)
+	}
+	return nil
+}
+
+type DropCompositeTypeCommand struct {
+	DropIfExists bool
+	TypeSchemas  []string
+	TypeNames    []string
+	DropCascade  bool
+	Ignore       bool
+}
+
+func (cmd DropCompositeTypeCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=composite types", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("DROP TYPE ")
+	if cmd.DropIfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	for i, typeName := range cmd.TypeNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeQualifiedName(dialect, buf, cmd.TypeSchemas[i], typeName)
+	}
+	if cmd.DropCascade {
+		buf.WriteString(" CASCADE")
+	}
+	return nil
+}
+
+// diffCompositeType returns the AlterCompositeTypeCommand that turns gotType
+// into wantType. Attributes missing from wantType are only dropped if
+// dropExtraneous is true.
+func diffCompositeType(gotType, wantType CompositeType, dropExtraneous bool) (alterCompositeTypeCmd AlterCompositeTypeCommand, isDifferent bool) {
+	alterCompositeTypeCmd.TypeSchema = wantType.TypeSchema
+	alterCompositeTypeCmd.TypeName = wantType.TypeName
+	gotAttributes := make(map[string]CompositeTypeAttribute)
+	for _, attribute := range gotType.Attributes {
+		gotAttributes[attribute.AttributeName] = attribute
+	}
+	wantAttributes := make(map[string]bool)
+	for _, attribute := range wantType.Attributes {
+		wantAttributes[attribute.AttributeName] = true
+		gotAttribute, ok := gotAttributes[attribute.AttributeName]
+		if !ok {
+			isDifferent = true
+			alterCompositeTypeCmd.AddAttributes = append(alterCompositeTypeCmd.AddAttributes, attribute)
+		} else if !sameDataType(sq.DialectPostgres, gotAttribute.AttributeType, attribute.AttributeType) {
+			isDifferent = true
+			alterCompositeTypeCmd.AlterAttributeTypes = append(alterCompositeTypeCmd.AlterAttributeTypes, attribute)
+		}
+	}
+	if dropExtraneous {
+		for _, attribute := range gotType.Attributes {
+			if !wantAttributes[attribute.AttributeName] {
+				isDifferent = true
+				alterCompositeTypeCmd.DropAttributes = append(alterCompositeTypeCmd.DropAttributes, attribute.AttributeName)
+			}
+		}
+	}
+	return alterCompositeTypeCmd, isDifferent
+}
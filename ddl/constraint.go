@@ -235,3 +235,26 @@ func (cmd DropConstraintCommand) AppendSQL(dialect string, buf *bytes.Buffer, ar
 	}
 	return nil
 }
+
+type RenameConstraintCommand struct {
+	TableSchema    string
+	TableName      string
+	ConstraintName string
+	RenameToName   string
+	Ignore         bool
+}
+
+func (cmd RenameConstraintCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%s does not support RENAME CONSTRAINT", dialect)
+	}
+	buf.WriteString("ALTER TABLE ")
+	if cmd.TableSchema != "" {
+		buf.WriteString(sq.QuoteIdentifier(dialect, cmd.TableSchema) + ".")
+	}
+	buf.WriteString(sq.QuoteIdentifier(dialect, cmd.TableName) + " RENAME CONSTRAINT " + sq.QuoteIdentifier(dialect, cmd.ConstraintName) + " TO " + sq.QuoteIdentifier(dialect, cmd.RenameToName))
+	return nil
+}
@@ -3,7 +3,7 @@ package ddl
 import (
 	"context"
 	"fmt"
-	"io"
+	"strconv"
 	"strings"
 
 	"github.com/bokwoon95/sq"
@@ -122,6 +122,13 @@ func (dbm *DatabaseMetadata) loadTable(table sq.SchemaTable) error {
 	if err != nil {
 		return err
 	}
+	for _, enum := range tbl.enums {
+		err = schema.loadEnum(enum)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tableName, err)
+		}
+	}
+	tbl.enums = nil
 	if n2 >= 0 {
 		schema.Tables[n2] = tbl
 	} else {
@@ -177,10 +184,39 @@ func (dbm *DatabaseMetadata) loadFunction(function Function) error {
 		schema = Schema{SchemaName: function.FunctionSchema}
 		defer func() { dbm.AppendSchema(schema) }()
 	}
-	schema.Functions = append(schema.Functions, function)
+	schema.AppendFunction(function)
 	return nil
 }
 
+// loadSchemaObject runs appendObject on the schema named schemaName, creating
+// the schema if it does not exist.
+func (dbm *DatabaseMetadata) loadSchemaObject(schemaName string, appendObject func(schema *Schema)) {
+	n := dbm.CachedSchemaPosition(schemaName)
+	if n < 0 {
+		n = dbm.AppendSchema(Schema{SchemaName: schemaName})
+	}
+	appendObject(&dbm.Schemas[n])
+}
+
+// nameDomainChecks names the unnamed CHECK constraints of a domain the way
+// postgres does, so that they can be matched against the introspected ones.
+func nameDomainChecks(domain Domain) Domain {
+	var checks []DomainCheck
+	var unnamed int
+	for _, check := range domain.Checks {
+		if check.ConstraintName == "" {
+			check.ConstraintName = generateName(CHECK, domain.DomainName)
+			if unnamed > 0 {
+				check.ConstraintName += strconv.Itoa(unnamed)
+			}
+			unnamed++
+		}
+		checks = append(checks, check)
+	}
+	domain.Checks = checks
+	return domain
+}
+
 type DatabaseMetadataOption func(*DatabaseMetadata) error
 
 func NewDatabaseMetadata(dialect string, opts ...DatabaseMetadataOption) (DatabaseMetadata, error) {
@@ -307,6 +343,45 @@ func WithDB(db sq.DB, defaultFilter *Filter) DatabaseMetadataOption {
 			}
 		}
 		if dbi.dialect == sq.DialectPostgres {
+			enums, err := dbi.GetEnums(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("GetEnums: %w", err)
+			}
+			for _, enum := range enums {
+				n1 := dbm.CachedSchemaPosition(enum.EnumSchema)
+				if n1 < 0 {
+					n1 = dbm.AppendSchema(Schema{SchemaName: enum.EnumSchema})
+				}
+				dbm.Schemas[n1].AppendEnum(enum)
+			}
+		}
+		if dbi.dialect == sq.DialectPostgres {
+			sequences, err := dbi.GetSequences(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("GetSequences: %w", err)
+			}
+			for _, sequence := range sequences {
+				sequence := sequence
+				dbm.loadSchemaObject(sequence.SequenceSchema, func(schema *Schema) { schema.AppendSequence(sequence) })
+			}
+			domains, err := dbi.GetDomains(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("GetDomains: %w", err)
+			}
+			for _, domain := range domains {
+				domain := domain
+				dbm.loadSchemaObject(domain.DomainSchema, func(schema *Schema) { schema.AppendDomain(domain) })
+			}
+			compositeTypes, err := dbi.GetCompositeTypes(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("GetCompositeTypes: %w", err)
+			}
+			for _, compositeType := range compositeTypes {
+				compositeType := compositeType
+				dbm.loadSchemaObject(compositeType.TypeSchema, func(schema *Schema) { schema.AppendCompositeType(compositeType) })
+			}
+		}
+		if dbi.dialect == sq.DialectPostgres || dbi.dialect == sq.DialectMySQL {
 			functions, err := dbi.GetFunctions(ctx, nil)
 			if err != nil {
 				return fmt.Errorf("GetFunctions: %w", err)
@@ -384,6 +459,47 @@ func WithFunctions(functions ...Function) DatabaseMetadataOption {
 	}
 }
 
-func (dbm *DatabaseMetadata) WriteStructs(w io.Writer) error {
-	return nil
+func WithSequences(sequences ...Sequence) DatabaseMetadataOption {
+	return func(dbm *DatabaseMetadata) error {
+		for i, sequence := range sequences {
+			if sequence.SequenceName == "" {
+				return fmt.Errorf("WithSequences sequence #%d: sequence name cannot be empty", i+1)
+			}
+			sequence := sequence
+			dbm.loadSchemaObject(sequence.SequenceSchema, func(schema *Schema) { schema.AppendSequence(sequence) })
+		}
+		return nil
+	}
+}
+
+func WithDomains(domains ...Domain) DatabaseMetadataOption {
+	return func(dbm *DatabaseMetadata) error {
+		for i, domain := range domains {
+			if domain.DomainName == "" {
+				return fmt.Errorf("WithDomains domain #%d: domain name cannot be empty", i+1)
+			}
+			if domain.UnderlyingType == "" {
+				return fmt.Errorf("WithDomains domain #%d: domain %s has no underlying type", i+1, domain.DomainName)
+			}
+			domain := nameDomainChecks(domain)
+			dbm.loadSchemaObject(domain.DomainSchema, func(schema *Schema) { schema.AppendDomain(domain) })
+		}
+		return nil
+	}
+}
+
+func WithCompositeTypes(compositeTypes ...CompositeType) DatabaseMetadataOption {
+	return func(dbm *DatabaseMetadata) error {
+		for i, compositeType := range compositeTypes {
+			if compositeType.TypeName == "" {
+				return fmt.Errorf("WithCompositeTypes type #%d: type name cannot be empty", i+1)
+			}
+			if len(compositeType.Attributes) == 0 {
+				return fmt.Errorf("WithCompositeTypes type #%d: type %s has no attributes", i+1, compositeType.TypeName)
+			}
+			compositeType := compositeType
+			dbm.loadSchemaObject(compositeType.TypeSchema, func(schema *Schema) { schema.AppendCompositeType(compositeType) })
+		}
+		return nil
+	}
 }
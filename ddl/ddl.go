@@ -74,6 +74,13 @@ func generateName(nameType string, tableName string, columnNames ...string) stri
 	return buf.String() + suffix
 }
 
+func writeQualifiedName(dialect string, buf *bytes.Buffer, schemaName, name string) {
+	if schemaName != "" {
+		buf.WriteString(sq.QuoteIdentifier(dialect, schemaName) + ".")
+	}
+	buf.WriteString(sq.QuoteIdentifier(dialect, name))
+}
+
 func defaultColumnType(dialect string, field sq.Field) (columnType string) {
 	switch field.(type) {
 	case sq.BinaryField:
@@ -0,0 +1,345 @@
+package ddl
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DiagramOptions configures the diagrams written by WriteDOT and
+// WriteMermaid.
+type DiagramOptions struct {
+	// WithSchemas, if non-empty, limits the diagram to the tables in these
+	// schemas.
+	WithSchemas []string
+	// WithoutSchemas excludes the tables in these schemas from the diagram.
+	WithoutSchemas []string
+	// Highlight, if non-nil, highlights the tables created or altered by the
+	// migration.
+	Highlight *Migration
+}
+
+// diagramTable is a table to be drawn in a diagram.
+type diagramTable struct {
+	id          string
+	tbl         Table
+	highlighted bool
+	// keys maps the column names to their keys e.g. ["PK", "FK"].
+	keys map[string][]string
+}
+
+// diagramEdge is a foreign key drawn between two tables in a diagram.
+type diagramEdge struct {
+	from       *diagramTable
+	to         *diagramTable
+	constraint Constraint
+}
+
+// WriteDOT writes the tables of the DatabaseMetadata to w as a Graphviz DOT
+// graph, with an edge for every foreign key.
+func (dbm *DatabaseMetadata) WriteDOT(w io.Writer, opts DiagramOptions) error {
+	tables, edges := dbm.diagram(opts)
+	bufw := bufio.NewWriter(w)
+	bufw.WriteString("digraph {\n")
+	bufw.WriteString("    graph [rankdir=LR];\n")
+	bufw.WriteString("    node [shape=plaintext];\n")
+	for _, table := range tables {
+		headerColor := "#e0e0e0"
+		if table.highlighted {
+			headerColor = "#ffd966"
+		}
+		bufw.WriteString("\n    " + strconv.Quote(table.id) + " [label=<\n")
+		bufw.WriteString(`        <table border="0" cellborder="1" cellspacing="0">` + "\n")
+		bufw.WriteString(`            <tr><td bgcolor="` + headerColor + `" colspan="3"><b>` + html.EscapeString(table.id) + "</b></td></tr>\n")
+		for _, column := range table.tbl.Columns {
+			if column.Ignore {
+				continue
+			}
+			columnType := column.ColumnType
+			if column.IsNotNull {
+				columnType += " NOT NULL"
+			}
+			bufw.WriteString(`            <tr><td port="` + html.EscapeString(column.ColumnName) + `" align="left">` + html.EscapeString(column.ColumnName) + "</td>" +
+				`<td align="left">` + html.EscapeString(columnType) + "</td>" +
+				"<td>" + strings.Join(table.keys[column.ColumnName], ", ") + "</td></tr>\n")
+		}
+		bufw.WriteString("        </table>\n    >];\n")
+	}
+	if len(edges) > 0 {
+		bufw.WriteString("\n")
+	}
+	for _, edge := range edges {
+		from, to := strconv.Quote(edge.from.id), strconv.Quote(edge.to.id)
+		if len(edge.constraint.Columns) == 1 && len(edge.constraint.ReferencesColumns) == 1 {
+			from += ":" + strconv.Quote(edge.constraint.Columns[0])
+			to += ":" + strconv.Quote(edge.constraint.ReferencesColumns[0])
+		}
+		bufw.WriteString("    " + from + " -> " + to + " [label=" + strconv.Quote(edge.constraint.ConstraintName) + "];\n")
+	}
+	bufw.WriteString("}\n")
+	return bufw.Flush()
+}
+
+// WriteMermaid writes the tables of the DatabaseMetadata to w as a Mermaid
+// erDiagram, with a relationship for every foreign key.
+func (dbm *DatabaseMetadata) WriteMermaid(w io.Writer, opts DiagramOptions) error {
+	tables, edges := dbm.diagram(opts)
+	bufw := bufio.NewWriter(w)
+	bufw.WriteString("erDiagram\n")
+	var highlighted []string
+	for _, table := range tables {
+		entity := mermaidName(table.id)
+		if table.highlighted {
+			highlighted = append(highlighted, entity)
+		}
+		bufw.WriteString("    " + entity + " {\n")
+		for _, column := range table.tbl.Columns {
+			if column.Ignore {
+				continue
+			}
+			bufw.WriteString("        " + mermaidName(column.ColumnType) + " " + mermaidName(column.ColumnName))
+			if keys := table.keys[column.ColumnName]; len(keys) > 0 {
+				bufw.WriteString(" " + strings.Join(keys, ", "))
+			}
+			if column.IsNotNull {
+				bufw.WriteString(` "NOT NULL"`)
+			}
+			bufw.WriteString("\n")
+		}
+		bufw.WriteString("    }\n")
+	}
+	for _, edge := range edges {
+		// The child side is "zero or more", or "zero or one" if the foreign
+		// key columns are unique. The parent side is "exactly one", or "zero
+		// or one" if any foreign key column is nullable.
+		childSide, parentSide := "}o", "||"
+		if isUniqueKey(edge.from.tbl, edge.constraint.Columns) {
+			childSide = "|o"
+		}
+		for _, columnName := range edge.constraint.Columns {
+			if n := edge.from.tbl.CachedColumnPosition(columnName); n >= 0 && !edge.from.tbl.Columns[n].IsNotNull {
+				parentSide = "o|"
+				break
+			}
+		}
+		bufw.WriteString("    " + mermaidName(edge.from.id) + " " + childSide + "--" + parentSide + " " + mermaidName(edge.to.id) + " : " + strconv.Quote(edge.constraint.ConstraintName) + "\n")
+	}
+	if len(highlighted) > 0 {
+		bufw.WriteString("    classDef highlighted fill:#ffd966\n")
+		bufw.WriteString("    class " + strings.Join(highlighted, ",") + " highlighted\n")
+	}
+	return bufw.Flush()
+}
+
+// diagram returns the tables and foreign keys to be drawn for the given
+// options. Tables in the current schema are identified by their bare name,
+// tables in other schemas by their schema-qualified name. Foreign keys that
+// reference a table not in the diagram are left out.
+func (dbm *DatabaseMetadata) diagram(opts DiagramOptions) ([]*diagramTable, []diagramEdge) {
+	currentSchema := dbm.CurrentSchema
+	if currentSchema == "" && opts.Highlight != nil {
+		currentSchema = opts.Highlight.CurrentSchema
+	}
+	tableKey := func(tableSchema, tableName string) [2]string {
+		if tableSchema == "" {
+			tableSchema = currentSchema
+		}
+		return [2]string{tableSchema, tableName}
+	}
+	var highlighted map[[2]string]bool
+	if opts.Highlight != nil {
+		highlighted = opts.Highlight.touchedTables(tableKey)
+	}
+	var tables []*diagramTable
+	tablesByKey := make(map[[2]string]*diagramTable)
+	for _, schema := range dbm.Schemas {
+		if schema.Ignore {
+			continue
+		}
+		schemaName := schema.SchemaName
+		if schemaName == "" {
+			schemaName = currentSchema
+		}
+		if len(opts.WithSchemas) > 0 && !containsString(opts.WithSchemas, schemaName) {
+			continue
+		}
+		if containsString(opts.WithoutSchemas, schemaName) {
+			continue
+		}
+		for _, tbl := range schema.Tables {
+			if tbl.Ignore {
+				continue
+			}
+			tableSchema := tbl.TableSchema
+			if tableSchema == "" {
+				tableSchema = schemaName
+			}
+			key := tableKey(tableSchema, tbl.TableName)
+			table := &diagramTable{
+				id:          tbl.TableName,
+				tbl:         tbl,
+				highlighted: highlighted[key],
+				keys:        make(map[string][]string),
+			}
+			if key[0] != currentSchema {
+				table.id = qualifiedName(key[0], tbl.TableName)
+			}
+			for _, keyType := range []string{PRIMARY_KEY, FOREIGN_KEY, UNIQUE} {
+				for _, constraint := range tbl.Constraints {
+					if constraint.Ignore || constraint.ConstraintType != keyType {
+						continue
+					}
+					table.addKey(diagramKeyNames[keyType], constraint.Columns)
+				}
+			}
+			// mysql unique constraints are unique indexes.
+			for _, index := range tbl.Indexes {
+				if !index.Ignore && index.IsUnique && index.Predicate == "" {
+					table.addKey("UK", index.Columns)
+				}
+			}
+			tables = append(tables, table)
+			tablesByKey[key] = table
+		}
+	}
+	var edges []diagramEdge
+	for _, table := range tables {
+		for _, constraint := range table.tbl.Constraints {
+			if constraint.Ignore || constraint.ConstraintType != FOREIGN_KEY {
+				continue
+			}
+			referencesSchema := constraint.ReferencesSchema
+			if referencesSchema == "" {
+				referencesSchema = table.tbl.TableSchema
+			}
+			referencesTable, ok := tablesByKey[tableKey(referencesSchema, constraint.ReferencesTable)]
+			if !ok {
+				continue
+			}
+			if len(constraint.ReferencesColumns) == 0 {
+				constraint.ReferencesColumns = constraint.Columns
+			}
+			edges = append(edges, diagramEdge{
+				from:       table,
+				to:         referencesTable,
+				constraint: constraint,
+			})
+		}
+	}
+	return tables, edges
+}
+
+func (table *diagramTable) addKey(keyName string, columnNames []string) {
+	for _, columnName := range columnNames {
+		if columnName == "" {
+			continue
+		}
+		if keys := table.keys[columnName]; !containsString(keys, keyName) {
+			table.keys[columnName] = append(keys, keyName)
+		}
+	}
+}
+
+var diagramKeyNames = map[string]string{
+	PRIMARY_KEY: "PK",
+	FOREIGN_KEY: "FK",
+	UNIQUE:      "UK",
+}
+
+// touchedTables returns the keys of the tables created, rebuilt, altered or
+// renamed by the migration, or whose indexes or triggers it changes.
+func (m *Migration) touchedTables(tableKey func(tableSchema, tableName string) [2]string) map[[2]string]bool {
+	touched := make(map[[2]string]bool)
+	for _, cmd := range m.CreateTableCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.Table.TableSchema, cmd.Table.TableName)] = true
+		}
+	}
+	for _, cmd := range m.RebuildTableCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.Table.TableSchema, cmd.Table.TableName)] = true
+		}
+	}
+	for _, cmd := range m.RenameTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, renameToName := range cmd.RenameToNames {
+			var renameToSchema string
+			if i < len(cmd.RenameToSchemas) {
+				renameToSchema = cmd.RenameToSchemas[i]
+			}
+			touched[tableKey(renameToSchema, renameToName)] = true
+		}
+	}
+	for _, cmd := range m.RenameColumnCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.TableSchema, cmd.TableName)] = true
+		}
+	}
+	for _, cmd := range m.RenameConstraintCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.TableSchema, cmd.TableName)] = true
+		}
+	}
+	for _, cmd := range m.RenameIndexCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.TableSchema, cmd.TableName)] = true
+		}
+	}
+	for _, cmds := range [][]AlterTableCommand{m.AlterTableCmds, m.AddForeignKeyCmds, m.AlterTableDropCmds} {
+		for _, cmd := range cmds {
+			if !cmd.Ignore {
+				touched[tableKey(cmd.TableSchema, cmd.TableName)] = true
+			}
+		}
+	}
+	for _, cmd := range m.CreateIndexCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.Index.TableSchema, cmd.Index.TableName)] = true
+		}
+	}
+	for _, cmd := range m.DropIndexCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.TableSchema, cmd.TableName)] = true
+		}
+	}
+	for _, cmd := range m.CreateTriggerCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.Trigger.TableSchema, cmd.Trigger.TableName)] = true
+		}
+	}
+	for _, cmd := range m.DropTriggerCmds {
+		if !cmd.Ignore {
+			touched[tableKey(cmd.TableSchema, cmd.TableName)] = true
+		}
+	}
+	return touched
+}
+
+// mermaidName replaces the characters that Mermaid does not allow in entity
+// names, attribute names and attribute types with underscores e.g. "DOUBLE
+// PRECISION" becomes "DOUBLE_PRECISION" and "NUMERIC(6,2)" becomes
+// "NUMERIC(6_2)".
+func mermaidName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '_' || r == '-' || r == '(' || r == ')' || r == '[' || r == ']':
+			return r
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
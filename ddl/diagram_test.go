@@ -0,0 +1,146 @@
+package ddl
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_DatabaseMetadataDiagram(t *testing.T) {
+	fsys := fstest.MapFS{"schema.sql": &fstest.MapFile{Data: []byte(`
+CREATE SCHEMA audit;
+CREATE TABLE author (
+    author_id INT PRIMARY KEY
+    ,email TEXT NOT NULL UNIQUE
+);
+CREATE TABLE book (
+    book_id INT PRIMARY KEY
+    ,author_id INT NOT NULL REFERENCES author
+    ,editor_id INT REFERENCES author (author_id)
+    ,price NUMERIC(6,2)
+);
+CREATE TABLE audit.book_log (
+    book_id INT UNIQUE REFERENCES public.book
+    ,logged_at TIMESTAMP WITH TIME ZONE
+);
+`)}}
+	wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithSQLFiles(fsys, "schema.sql"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	gotDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithSQLFiles(fstest.MapFS{"schema.sql": &fstest.MapFile{Data: []byte(`
+CREATE TABLE public.author (
+    author_id INT PRIMARY KEY
+    ,email TEXT NOT NULL UNIQUE
+);
+`)}}, "schema.sql"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	gotDBMetadata.CurrentSchema = "public"
+	m, err := Migrate(CreateMissing, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+
+	t.Run("dot", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		err := wantDBMetadata.WriteDOT(buf, DiagramOptions{Highlight: m})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantOutput := `digraph {
+    graph [rankdir=LR];
+    node [shape=plaintext];
+
+    "audit.book_log" [label=<
+        <table border="0" cellborder="1" cellspacing="0">
+            <tr><td bgcolor="#ffd966" colspan="3"><b>audit.book_log</b></td></tr>
+            <tr><td port="book_id" align="left">book_id</td><td align="left">INT</td><td>FK, UK</td></tr>
+            <tr><td port="logged_at" align="left">logged_at</td><td align="left">TIMESTAMP WITH TIME ZONE</td><td></td></tr>
+        </table>
+    >];
+
+    "author" [label=<
+        <table border="0" cellborder="1" cellspacing="0">
+            <tr><td bgcolor="#e0e0e0" colspan="3"><b>author</b></td></tr>
+            <tr><td port="author_id" align="left">author_id</td><td align="left">INT</td><td>PK</td></tr>
+            <tr><td port="email" align="left">email</td><td align="left">TEXT NOT NULL</td><td>UK</td></tr>
+        </table>
+    >];
+
+    "book" [label=<
+        <table border="0" cellborder="1" cellspacing="0">
+            <tr><td bgcolor="#ffd966" colspan="3"><b>book</b></td></tr>
+            <tr><td port="book_id" align="left">book_id</td><td align="left">INT</td><td>PK</td></tr>
+            <tr><td port="author_id" align="left">author_id</td><td align="left">INT NOT NULL</td><td>FK</td></tr>
+            <tr><td port="editor_id" align="left">editor_id</td><td align="left">INT</td><td>FK</td></tr>
+            <tr><td port="price" align="left">price</td><td align="left">NUMERIC(6,2)</td><td></td></tr>
+        </table>
+    >];
+
+    "audit.book_log":"book_id" -> "book":"book_id" [label="book_log_book_id_fkey"];
+    "book":"author_id" -> "author":"author_id" [label="book_author_id_fkey"];
+    "book":"editor_id" -> "author":"author_id" [label="book_editor_id_fkey"];
+}
+`
+		if diff := testutil.Diff(buf.String(), wantOutput); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		err := wantDBMetadata.WriteMermaid(buf, DiagramOptions{Highlight: m})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantOutput := `erDiagram
+    audit_book_log {
+        INT book_id FK, UK
+        TIMESTAMP_WITH_TIME_ZONE logged_at
+    }
+    author {
+        INT author_id PK
+        TEXT email UK "NOT NULL"
+    }
+    book {
+        INT book_id PK
+        INT author_id FK "NOT NULL"
+        INT editor_id FK
+        NUMERIC(6_2) price
+    }
+    audit_book_log |o--o| book : "book_log_book_id_fkey"
+    book }o--|| author : "book_author_id_fkey"
+    book }o--o| author : "book_editor_id_fkey"
+    classDef highlighted fill:#ffd966
+    class audit_book_log,book highlighted
+`
+		if diff := testutil.Diff(buf.String(), wantOutput); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("mermaid WithSchemas", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		err := wantDBMetadata.WriteMermaid(buf, DiagramOptions{WithSchemas: []string{"audit"}})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantOutput := `erDiagram
+    audit_book_log {
+        INT book_id FK, UK
+        TIMESTAMP_WITH_TIME_ZONE logged_at
+    }
+`
+		if diff := testutil.Diff(buf.String(), wantOutput); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+}
@@ -0,0 +1,409 @@
+package ddl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DocsOptions configures the documentation written by WriteDocs.
+type DocsOptions struct {
+	// WithSchemas, if non-empty, limits the documentation to the tables and
+	// views in these schemas.
+	WithSchemas []string
+	// WithoutSchemas excludes the tables and views in these schemas from the
+	// documentation.
+	WithoutSchemas []string
+	// RenderHTML, if non-nil, is used to render every Markdown page into an
+	// HTML page written next to it. Links between the HTML pages point to the
+	// other HTML pages.
+	RenderHTML func(w io.Writer, title string, markdown []byte) error
+}
+
+// docsPage is a single page of the documentation. The name is the
+// slash-separated path of the page relative to the docs directory, without an
+// extension. The markdown function returns the contents of the page with links
+// to the other pages ending in ext.
+type docsPage struct {
+	name     string
+	title    string
+	markdown func(ext string) []byte
+}
+
+// WriteDocs writes Markdown documentation of the DatabaseMetadata into dir:
+// an index.md listing every table and view, plus a page per table in the
+// tables subdirectory (columns, constraints, indexes, triggers and the tables
+// that reference it) and per view in the views subdirectory. Keeping the
+// pages in subdirectories means a table called index cannot overwrite
+// index.md. Since it only needs the DatabaseMetadata it can be run against a JSON
+// snapshot, without a live database.
+func (dbm *DatabaseMetadata) WriteDocs(dir string, opts *DocsOptions) error {
+	if opts == nil {
+		opts = &DocsOptions{}
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, page := range dbm.docsPages(opts) {
+		name := filepath.Join(dir, filepath.FromSlash(page.name))
+		err = os.MkdirAll(filepath.Dir(name), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(name+".md", page.markdown(".md"), 0644)
+		if err != nil {
+			return err
+		}
+		if opts.RenderHTML == nil {
+			continue
+		}
+		buf := &bytes.Buffer{}
+		err = opts.RenderHTML(buf, page.title, page.markdown(".html"))
+		if err != nil {
+			return fmt.Errorf("rendering %s: %w", page.name, err)
+		}
+		err = os.WriteFile(name+".html", buf.Bytes(), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// docsReference is a foreign key that references a documented table.
+type docsReference struct {
+	page       string
+	constraint Constraint
+}
+
+func (dbm *DatabaseMetadata) docsPages(opts *DocsOptions) []docsPage {
+	currentSchema := dbm.CurrentSchema
+	tableKey := func(tableSchema, tableName string) [2]string {
+		if tableSchema == "" {
+			tableSchema = currentSchema
+		}
+		return [2]string{tableSchema, tableName}
+	}
+	pageName := func(key [2]string) string {
+		name := key[1]
+		if key[0] != currentSchema {
+			name = key[0] + "." + name
+		}
+		return strings.NewReplacer("/", "_", `\`, "_").Replace(name)
+	}
+	var tables []Table
+	var views []View
+	for _, schema := range dbm.Schemas {
+		if schema.Ignore {
+			continue
+		}
+		schemaName := schema.SchemaName
+		if schemaName == "" {
+			schemaName = currentSchema
+		}
+		if len(opts.WithSchemas) > 0 && !containsString(opts.WithSchemas, schemaName) {
+			continue
+		}
+		if containsString(opts.WithoutSchemas, schemaName) {
+			continue
+		}
+		for _, tbl := range schema.Tables {
+			if tbl.Ignore {
+				continue
+			}
+			if tbl.TableSchema == "" {
+				tbl.TableSchema = schema.SchemaName
+			}
+			tables = append(tables, tbl)
+		}
+		for _, view := range schema.Views {
+			if view.Ignore {
+				continue
+			}
+			if view.ViewSchema == "" {
+				view.ViewSchema = schema.SchemaName
+			}
+			views = append(views, view)
+		}
+	}
+	tablePages := make(map[[2]string]string)
+	for _, tbl := range tables {
+		key := tableKey(tbl.TableSchema, tbl.TableName)
+		tablePages[key] = pageName(key)
+	}
+	referencedBy := make(map[[2]string][]docsReference)
+	for _, tbl := range tables {
+		for _, constraint := range tbl.Constraints {
+			if constraint.Ignore || constraint.ConstraintType != FOREIGN_KEY {
+				continue
+			}
+			referencesSchema := constraint.ReferencesSchema
+			if referencesSchema == "" {
+				referencesSchema = tbl.TableSchema
+			}
+			key := tableKey(referencesSchema, constraint.ReferencesTable)
+			referencedBy[key] = append(referencedBy[key], docsReference{
+				page:       tablePages[tableKey(tbl.TableSchema, tbl.TableName)],
+				constraint: constraint,
+			})
+		}
+	}
+	// link returns a Markdown link from one table page to the page of another
+	// table, or just the table name if that table is not documented.
+	link := func(tableSchema, tableName, ext string) string {
+		key := tableKey(tableSchema, tableName)
+		page, ok := tablePages[key]
+		if !ok {
+			return docsCode(qualifiedName(tableSchema, tableName))
+		}
+		return "[" + docsCell(page) + "](" + url.PathEscape(page) + ext + ")"
+	}
+
+	title := "Database schema"
+	if dbm.DatabaseName != "" {
+		title = dbm.DatabaseName
+	}
+	pages := []docsPage{{
+		name:  "index",
+		title: title,
+		markdown: func(ext string) []byte {
+			buf := &bytes.Buffer{}
+			buf.WriteString("# " + docsCell(title) + "\n")
+			if dbm.Comment != "" {
+				buf.WriteString("\n" + docsText(dbm.Comment) + "\n")
+			}
+			if len(tables) > 0 {
+				buf.WriteString("\n## Tables\n\n| Table | Comment |\n| --- | --- |\n")
+				for _, tbl := range tables {
+					page := tablePages[tableKey(tbl.TableSchema, tbl.TableName)]
+					buf.WriteString("| [" + docsCell(page) + "](tables/" + url.PathEscape(page) + ext + ") | " + docsCell(tbl.Comment) + " |\n")
+				}
+			}
+			if len(views) > 0 {
+				buf.WriteString("\n## Views\n\n| View | Comment |\n| --- | --- |\n")
+				for _, view := range views {
+					page := pageName(tableKey(view.ViewSchema, view.ViewName))
+					buf.WriteString("| [" + docsCell(page) + "](views/" + url.PathEscape(page) + ext + ") | " + docsCell(view.Comment) + " |\n")
+				}
+			}
+			return buf.Bytes()
+		},
+	}}
+	for _, tbl := range tables {
+		tbl := tbl
+		key := tableKey(tbl.TableSchema, tbl.TableName)
+		page := tablePages[key]
+		pages = append(pages, docsPage{
+			name:  "tables/" + page,
+			title: page,
+			markdown: func(ext string) []byte {
+				buf := &bytes.Buffer{}
+				buf.WriteString("# " + docsCell(page) + "\n\n[Back to index](../index" + ext + ")\n")
+				if tbl.Comment != "" {
+					buf.WriteString("\n" + docsText(tbl.Comment) + "\n")
+				}
+				// primary key columns are implicitly NOT NULL.
+				primaryKeyColumns := make(map[string]bool)
+				for _, constraint := range tbl.Constraints {
+					if !constraint.Ignore && constraint.ConstraintType == PRIMARY_KEY {
+						for _, columnName := range constraint.Columns {
+							primaryKeyColumns[columnName] = true
+						}
+					}
+				}
+				buf.WriteString("\n## Columns\n\n| Column | Type | Nullable | Default | Comment |\n| --- | --- | --- | --- | --- |\n")
+				for _, column := range tbl.Columns {
+					if column.Ignore {
+						continue
+					}
+					nullable := "YES"
+					if column.IsNotNull || column.IsPrimaryKey || primaryKeyColumns[column.ColumnName] {
+						nullable = "NO"
+					}
+					buf.WriteString("| " + docsCode(column.ColumnName) + " | " + docsCode(column.ColumnType) + " | " + nullable + " | " + docsCode(columnDefault(column)) + " | " + docsCell(column.ColumnComment) + " |\n")
+				}
+				var hasConstraints bool
+				for _, constraint := range tbl.Constraints {
+					if constraint.Ignore {
+						continue
+					}
+					if !hasConstraints {
+						hasConstraints = true
+						buf.WriteString("\n## Constraints\n\n| Name | Type | Definition |\n| --- | --- | --- |\n")
+					}
+					definition := "(" + strings.Join(constraint.Columns, ", ") + ")"
+					switch constraint.ConstraintType {
+					case FOREIGN_KEY:
+						referencesSchema := constraint.ReferencesSchema
+						if referencesSchema == "" {
+							referencesSchema = tbl.TableSchema
+						}
+						definition = docsCell(definition) + " REFERENCES " + link(referencesSchema, constraint.ReferencesTable, ext)
+						if len(constraint.ReferencesColumns) > 0 {
+							definition += " (" + docsCell(strings.Join(constraint.ReferencesColumns, ", ")) + ")"
+						}
+						if constraint.UpdateRule != "" {
+							definition += " ON UPDATE " + constraint.UpdateRule
+						}
+						if constraint.DeleteRule != "" {
+							definition += " ON DELETE " + constraint.DeleteRule
+						}
+					case CHECK, EXCLUDE:
+						defBuf := &bytes.Buffer{}
+						if err := writeConstraintDefinition(dbm.Dialect, defBuf, constraint); err != nil {
+							definition = docsCell(err.Error())
+						} else {
+							definition = docsCode(defBuf.String())
+						}
+					default:
+						definition = docsCode(definition)
+					}
+					buf.WriteString("| " + docsCode(constraint.ConstraintName) + " | " + constraint.ConstraintType + " | " + definition + " |\n")
+				}
+				writeDocsIndexes(buf, tbl.Indexes)
+				writeDocsTriggers(buf, tbl.Triggers)
+				if references := referencedBy[key]; len(references) > 0 {
+					buf.WriteString("\n## Referenced by\n\n| Table | Constraint | Columns |\n| --- | --- | --- |\n")
+					for _, reference := range references {
+						buf.WriteString("| [" + docsCell(reference.page) + "](" + url.PathEscape(reference.page) + ext + ") | " + docsCode(reference.constraint.ConstraintName) + " | " + docsCell(strings.Join(reference.constraint.Columns, ", ")) + " |\n")
+					}
+				}
+				return buf.Bytes()
+			},
+		})
+	}
+	for _, view := range views {
+		view := view
+		page := pageName(tableKey(view.ViewSchema, view.ViewName))
+		pages = append(pages, docsPage{
+			name:  "views/" + page,
+			title: page,
+			markdown: func(ext string) []byte {
+				buf := &bytes.Buffer{}
+				buf.WriteString("# " + docsCell(page) + "\n\n[Back to index](../index" + ext + ")\n")
+				if view.IsMaterialized {
+					buf.WriteString("\nMaterialized view.\n")
+				}
+				if view.Comment != "" {
+					buf.WriteString("\n" + docsText(view.Comment) + "\n")
+				}
+				buf.WriteString("\n## Definition\n\n```sql\n" + strings.TrimSpace(view.SQL) + "\n```\n")
+				writeDocsIndexes(buf, view.Indexes)
+				writeDocsTriggers(buf, view.Triggers)
+				return buf.Bytes()
+			},
+		})
+	}
+	return pages
+}
+
+func writeDocsIndexes(buf *bytes.Buffer, indexes []Index) {
+	var hasIndexes bool
+	for _, index := range indexes {
+		if index.Ignore {
+			continue
+		}
+		if !hasIndexes {
+			hasIndexes = true
+			buf.WriteString("\n## Indexes\n\n| Name | Unique | Definition | Comment |\n| --- | --- | --- | --- |\n")
+		}
+		unique := "NO"
+		if index.IsUnique {
+			unique = "YES"
+		}
+		definition := &strings.Builder{}
+		if index.IndexType != "" {
+			definition.WriteString(index.IndexType + " ")
+		}
+		definition.WriteString("(")
+		for i, columnName := range index.Columns {
+			if i > 0 {
+				definition.WriteString(", ")
+			}
+			if columnName != "" {
+				definition.WriteString(columnName)
+			} else if i < len(index.Exprs) {
+				definition.WriteString("(" + index.Exprs[i] + ")")
+			}
+		}
+		definition.WriteString(")")
+		if len(index.IncludeColumns) > 0 {
+			definition.WriteString(" INCLUDE (" + strings.Join(index.IncludeColumns, ", ") + ")")
+		}
+		if index.Predicate != "" {
+			definition.WriteString(" WHERE " + index.Predicate)
+		}
+		buf.WriteString("| " + docsCode(index.IndexName) + " | " + unique + " | " + docsCode(definition.String()) + " | " + docsCell(index.Comment) + " |\n")
+	}
+}
+
+func writeDocsTriggers(buf *bytes.Buffer, triggers []Trigger) {
+	var hasTriggers bool
+	for _, trigger := range triggers {
+		if trigger.Ignore {
+			continue
+		}
+		if !hasTriggers {
+			hasTriggers = true
+			buf.WriteString("\n## Triggers\n")
+		}
+		buf.WriteString("\n### " + docsCell(trigger.TriggerName) + "\n")
+		if trigger.Comment != "" {
+			buf.WriteString("\n" + docsText(trigger.Comment) + "\n")
+		}
+		buf.WriteString("\n```sql\n" + strings.TrimSpace(trigger.SQL) + "\n```\n")
+	}
+}
+
+// columnDefault describes how the value of a column is filled in when it is
+// not provided: its default, identity, autoincrement or generated expression.
+func columnDefault(column Column) string {
+	switch {
+	case column.GeneratedExpr != "":
+		return "GENERATED ALWAYS AS (" + column.GeneratedExpr + ")"
+	case column.Identity != "":
+		return column.Identity
+	case column.IsAutoincrement:
+		return "AUTOINCREMENT"
+	}
+	return column.ColumnDefault
+}
+
+// docsHTMLEscaper escapes the characters that would otherwise let text from
+// the database (such as comments) through as raw HTML, since the HTML pages
+// are rendered with raw HTML enabled.
+var docsHTMLEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// docsText escapes s for use as a Markdown paragraph.
+func docsText(s string) string {
+	return docsHTMLEscaper.Replace(s)
+}
+
+// docsCell escapes s for use inside a Markdown table cell.
+func docsCell(s string) string {
+	return docsText(docsLine(s))
+}
+
+// docsLine joins s into a single line and escapes the pipes that would end a
+// Markdown table cell.
+func docsLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// docsCode formats s as inline code inside a Markdown table cell. Code spans
+// are escaped by the Markdown renderer, so s must not be HTML escaped.
+func docsCode(s string) string {
+	if s = docsLine(s); s == "" {
+		return ""
+	}
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,233 @@
+package ddl
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_DatabaseMetadataDocs(t *testing.T) {
+	fsys := fstest.MapFS{"schema.sql": &fstest.MapFile{Data: []byte(`
+CREATE TABLE author (
+    author_id INT AUTO_INCREMENT PRIMARY KEY
+    ,name VARCHAR(255) NOT NULL COMMENT 'full name | pen name'
+    ,created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE book (
+    book_id INT PRIMARY KEY
+    ,author_id INT NOT NULL
+    ,title VARCHAR(255) NOT NULL
+    ,price DECIMAL(6,2) CHECK (price > 0)
+    ,FOREIGN KEY (author_id) REFERENCES author (author_id) ON DELETE CASCADE
+    ,INDEX book_title_idx (title)
+);
+CREATE TRIGGER book_before_insert_trg BEFORE INSERT ON book FOR EACH ROW SET NEW.title = TRIM(NEW.title);
+CREATE VIEW book_author AS SELECT book.title, author.name FROM book JOIN author USING (author_id);
+`)}}
+	dbMetadata, err := NewDatabaseMetadata(sq.DialectMySQL, WithSQLFiles(fsys, "schema.sql"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	dbMetadata.Schemas[0].Views[0].Comment = "Books and their authors."
+
+	t.Run("pages", func(t *testing.T) {
+		t.Parallel()
+		gotPages := make(map[string]string)
+		for _, page := range dbMetadata.docsPages(&DocsOptions{}) {
+			gotPages[page.name] = string(page.markdown(".md"))
+		}
+		wantPages := map[string]string{
+			"index": `# Database schema
+
+## Tables
+
+| Table | Comment |
+| --- | --- |
+| [author](tables/author.md) |  |
+| [book](tables/book.md) |  |
+
+## Views
+
+| View | Comment |
+| --- | --- |
+| [book_author](views/book_author.md) | Books and their authors. |
+`,
+			"tables/author": `# author
+
+[Back to index](../index.md)
+
+## Columns
+
+| Column | Type | Nullable | Default | Comment |
+| --- | --- | --- | --- | --- |
+| ` + "`author_id` | `INT` | NO | `AUTOINCREMENT`" + ` |  |
+| ` + "`name` | `VARCHAR(255)`" + ` | NO |  | full name \| pen name |
+| ` + "`created_at` | `DATETIME` | YES | `CURRENT_TIMESTAMP`" + ` |  |
+
+## Constraints
+
+| Name | Type | Definition |
+| --- | --- | --- |
+| ` + "`author_author_id_pkey` | PRIMARY KEY | `(author_id)`" + ` |
+
+## Referenced by
+
+| Table | Constraint | Columns |
+| --- | --- | --- |
+| [book](book.md) | ` + "`book_author_id_fkey`" + ` | author_id |
+`,
+			"tables/book": `# book
+
+[Back to index](../index.md)
+
+## Columns
+
+| Column | Type | Nullable | Default | Comment |
+| --- | --- | --- | --- | --- |
+| ` + "`book_id` | `INT`" + ` | NO |  |  |
+| ` + "`author_id` | `INT`" + ` | NO |  |  |
+| ` + "`title` | `VARCHAR(255)`" + ` | NO |  |  |
+| ` + "`price` | `DECIMAL(6,2)`" + ` | YES |  |  |
+
+## Constraints
+
+| Name | Type | Definition |
+| --- | --- | --- |
+| ` + "`book_book_id_pkey` | PRIMARY KEY | `(book_id)`" + ` |
+| ` + "`book_price_check` | CHECK | `CHECK (price > 0)`" + ` |
+| ` + "`book_author_id_fkey`" + ` | FOREIGN KEY | (author_id) REFERENCES [author](author.md) (author_id) ON DELETE CASCADE |
+
+## Indexes
+
+| Name | Unique | Definition | Comment |
+| --- | --- | --- | --- |
+| ` + "`book_title_idx` | NO | `(title)`" + ` |  |
+
+## Triggers
+
+### book_before_insert_trg
+
+` + "```sql" + `
+CREATE TRIGGER book_before_insert_trg BEFORE INSERT ON book FOR EACH ROW SET NEW.title = TRIM(NEW.title)
+` + "```" + `
+`,
+			"views/book_author": `# book_author
+
+[Back to index](../index.md)
+
+Books and their authors.
+
+## Definition
+
+` + "```sql" + `
+SELECT book.title, author.name FROM book JOIN author USING (author_id)
+` + "```" + `
+`,
+		}
+		if diff := testutil.Diff(gotPages, wantPages); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("WriteDocs", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		err := dbMetadata.WriteDocs(dir, &DocsOptions{
+			RenderHTML: func(w io.Writer, title string, markdown []byte) error {
+				_, err := io.WriteString(w, "<title>"+title+"</title>\n"+string(markdown))
+				return err
+			},
+		})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, "views", "book_author.html"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantHTML := `<title>book_author</title>
+# book_author
+
+[Back to index](../index.html)
+
+Books and their authors.
+
+## Definition
+
+` + "```sql" + `
+SELECT book.title, author.name FROM book JOIN author USING (author_id)
+` + "```" + `
+`
+		if diff := testutil.Diff(string(b), wantHTML); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		for _, name := range []string{"index.md", "tables/author.md", "tables/book.md", "views/book_author.md", "index.html", "tables/author.html", "tables/book.html"} {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+				t.Error(testutil.Callers(), err)
+			}
+		}
+	})
+
+	t.Run("index table and HTML in comments", func(t *testing.T) {
+		t.Parallel()
+		dbMetadata := DatabaseMetadata{
+			Dialect: sq.DialectSQLite,
+			Schemas: []Schema{{
+				Tables: []Table{{
+					TableName: "index",
+					Comment:   "<script>alert(1)</script>",
+					Columns: []Column{{
+						ColumnName:    "word",
+						ColumnType:    "TEXT",
+						ColumnDefault: "'<a>'",
+						ColumnComment: "a < b & c",
+					}},
+				}},
+			}},
+		}
+		dir := t.TempDir()
+		err := dbMetadata.WriteDocs(dir, nil)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, "index.md"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantIndex := `# Database schema
+
+## Tables
+
+| Table | Comment |
+| --- | --- |
+| [index](tables/index.md) | &lt;script&gt;alert(1)&lt;/script&gt; |
+`
+		if diff := testutil.Diff(string(b), wantIndex); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		b, err = os.ReadFile(filepath.Join(dir, "tables", "index.md"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantTable := `# index
+
+[Back to index](../index.md)
+
+&lt;script&gt;alert(1)&lt;/script&gt;
+
+## Columns
+
+| Column | Type | Nullable | Default | Comment |
+| --- | --- | --- | --- | --- |
+| ` + "`word` | `TEXT` | YES | `'<a>'`" + ` | a &lt; b &amp; c |
+`
+		if diff := testutil.Diff(string(b), wantTable); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+}
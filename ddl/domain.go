@@ -0,0 +1,245 @@
+package ddl
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bokwoon95/sq"
+)
+
+// Domain is a postgres domain: a data type with optional NOT NULL, DEFAULT
+// and CHECK constraints. The UnderlyingType of an existing domain cannot be
+// changed by Migrate, it returns an error instead. Migrate creates domains
+// before composite types (so that composite type attributes can be domains),
+// so it also returns an error for a domain over a composite type that is
+// created in the same migration.
+type Domain struct {
+	DomainSchema   string        `json:",omitempty"`
+	DomainName     string        `json:",omitempty"`
+	UnderlyingType string        `json:",omitempty"`
+	CollationName  string        `json:",omitempty"`
+	IsNotNull      bool          `json:",omitempty"`
+	DomainDefault  string        `json:",omitempty"`
+	Checks         []DomainCheck `json:",omitempty"`
+	Ignore         bool          `json:",omitempty"`
+}
+
+// DomainCheck is a CHECK constraint of a domain. CheckExpr refers to the value
+// being checked as VALUE e.g. VALUE > 0.
+type DomainCheck struct {
+	ConstraintName string `json:",omitempty"`
+	CheckExpr      string `json:",omitempty"`
+}
+
+type CreateDomainCommand struct {
+	Domain Domain
+	Ignore bool
+}
+
+func (cmd CreateDomainCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=domains", ErrUnsupportedFeature, dialect)
+	}
+	if cmd.Domain.UnderlyingType == "" {
+		return fmt.Errorf("domain %s has no underlying type", cmd.Domain.DomainName)
+	}
+	buf.WriteString("CREATE DOMAIN ")
+	writeQualifiedName(dialect, buf, cmd.Domain.DomainSchema, cmd.Domain.DomainName)
+	buf.WriteString(" AS " + cmd.Domain.UnderlyingType)
+	if cmd.Domain.CollationName != "" {
+		buf.WriteString(` COLLATE "` + sq.EscapeQuote(cmd.Domain.CollationName, '"') + `"`)
+	}
+	if cmd.Domain.DomainDefault != "" {
+		buf.WriteString(" DEFAULT " + cmd.Domain.DomainDefault)
+	}
+	if cmd.Domain.IsNotNull {
+		buf.WriteString(" NOT NULL")
+	}
+	for _, check := range cmd.Domain.Checks {
+		if check.ConstraintName != "" {
+			buf.WriteString(" CONSTRAINT " + sq.QuoteIdentifier(dialect, check.ConstraintName))
+		}
+		buf.WriteString(" CHECK (" + check.CheckExpr + ")")
+	}
+	return nil
+}
+
+// AlterDomainCommand changes the constraints of an existing domain. Postgres
+// allows one change per ALTER DOMAIN, so every change is written as its own
+// statement. Migrate creates one AlterDomainCommand per change.
+type AlterDomainCommand struct {
+	DomainSchema    string
+	DomainName      string
+	SetDefault      string
+	DropDefault     bool
+	SetNotNull      bool
+	DropNotNull     bool
+	AddChecks       []DomainCheck
+	DropConstraints []string
+	Ignore          bool
+}
+
+func (cmd AlterDomainCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=domains", ErrUnsupportedFeature, dialect)
+	}
+	var written bool
+	alterDomain := func(action string) {
+		if written {
+			buf.WriteString(";\n")
+		}
+		written = true
+		buf.WriteString("ALTER DOMAIN ")
+		writeQualifiedName(dialect, buf, cmd.DomainSchema, cmd.DomainName)
+		buf.WriteString(" " + action)
+	}
+	for _, constraintName := range cmd.DropConstraints {
+		alterDomain("DROP CONSTRAINT IF EXISTS " + sq.QuoteIdentifier(dialect, constraintName))
+	}
+	if cmd.DropDefault {
+		alterDomain("DROP DEFAULT")
+	} else if cmd.SetDefault != "" {
+		alterDomain("SET DEFAULT " + cmd.SetDefault)
+	}
+	if cmd.DropNotNull {
+		alterDomain("DROP NOT NULL")
+	} else if cmd.SetNotNull {
+		alterDomain("SET NOT NULL")
+	}
+	for _, check := range cmd.AddChecks {
+		action := "ADD"
+		if check.ConstraintName != "" {
+			action += " CONSTRAINT " + sq.QuoteIdentifier(dialect, check.ConstraintName)
+		}
+		alterDomain(action + " CHECK (" + check.CheckExpr + ")")
+	}
+	return nil
+}
+
+type DropDomainCommand struct {
+	DropIfExists  bool
+	DomainSchemas []string
+	DomainNames   []string
+	DropCascade   bool
+	Ignore        bool
+}
+
+func (cmd DropDomainCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=domains", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("DROP DOMAIN ")
+	if cmd.DropIfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	for i, domainName := range cmd.DomainNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeQualifiedName(dialect, buf, cmd.DomainSchemas[i], domainName)
+	}
+	if cmd.DropCascade {
+		buf.WriteString(" CASCADE")
+	}
+	return nil
+}
+
+// diffDomain returns the AlterDomainCommands that turn gotDomain into
+// wantDomain, one per change. CHECK constraints are matched by name, like
+// table constraints, and a CHECK whose expression changed is dropped and added
+// again. Constraints missing from wantDomain are only dropped if
+// dropExtraneous is true. Postgres cannot change the underlying type of a
+// domain, so that is an error.
+func diffDomain(gotDomain, wantDomain Domain, dropExtraneous bool) ([]AlterDomainCommand, error) {
+	if !sameDataType(sq.DialectPostgres, gotDomain.UnderlyingType, wantDomain.UnderlyingType) {
+		return nil, fmt.Errorf("domain %s: cannot change the underlying type from %s to %s, the domain has to be dropped and created again", qualifiedName(wantDomain.DomainSchema, wantDomain.DomainName), gotDomain.UnderlyingType, wantDomain.UnderlyingType)
+	}
+	var alterDomainCmds []AlterDomainCommand
+	alter := func(config func(cmd *AlterDomainCommand)) {
+		alterDomainCmd := AlterDomainCommand{
+			DomainSchema: wantDomain.DomainSchema,
+			DomainName:   wantDomain.DomainName,
+		}
+		config(&alterDomainCmd)
+		alterDomainCmds = append(alterDomainCmds, alterDomainCmd)
+	}
+	wantChecks := make(map[string]string)
+	for _, check := range wantDomain.Checks {
+		wantChecks[check.ConstraintName] = check.CheckExpr
+	}
+	gotChecks := make(map[string]string)
+	for _, check := range gotDomain.Checks {
+		gotChecks[check.ConstraintName] = check.CheckExpr
+	}
+	for _, check := range gotDomain.Checks {
+		wantCheckExpr, ok := wantChecks[check.ConstraintName]
+		if (!ok && dropExtraneous) || (ok && !sameExpr(check.CheckExpr, wantCheckExpr)) {
+			constraintName := check.ConstraintName
+			alter(func(cmd *AlterDomainCommand) { cmd.DropConstraints = []string{constraintName} })
+		}
+	}
+	if gotDomain.DomainDefault != "" && wantDomain.DomainDefault == "" {
+		alter(func(cmd *AlterDomainCommand) { cmd.DropDefault = true })
+	} else if wantDomain.DomainDefault != "" && !sameExpr(gotDomain.DomainDefault, wantDomain.DomainDefault) {
+		alter(func(cmd *AlterDomainCommand) { cmd.SetDefault = wantDomain.DomainDefault })
+	}
+	if gotDomain.IsNotNull && !wantDomain.IsNotNull {
+		alter(func(cmd *AlterDomainCommand) { cmd.DropNotNull = true })
+	} else if !gotDomain.IsNotNull && wantDomain.IsNotNull {
+		alter(func(cmd *AlterDomainCommand) { cmd.SetNotNull = true })
+	}
+	for _, check := range wantDomain.Checks {
+		gotCheckExpr, ok := gotChecks[check.ConstraintName]
+		if !ok || !sameExpr(gotCheckExpr, check.CheckExpr) {
+			check := check
+			alter(func(cmd *AlterDomainCommand) { cmd.AddChecks = []DomainCheck{check} })
+		}
+	}
+	return alterDomainCmds, nil
+}
+
+var (
+	typeCastRegexp  = regexp.MustCompile(`(?i)::\s*(?:character varying|double precision|time(?:stamp)? with(?:out)? time zone|[a-z_][a-z0-9_.]*)(?:\s*\([0-9, ]*\))?(?:\[\])?`)
+	exprNoiseRegexp = regexp.MustCompile(`[\s()]+`)
+)
+
+// sameExpr reports whether two postgres expressions are the same, ignoring
+// the type casts, brackets, whitespace and LIKE operators that postgres adds
+// or rewrites when it stores an expression (VALUE LIKE 'a%' is stored as
+// (VALUE ~~ 'a%'::text)).
+func sameExpr(expr1, expr2 string) bool {
+	normalize := func(expr string) string {
+		expr = typeCastRegexp.ReplaceAllString(expr, "")
+		expr = strings.NewReplacer("!~~*", " NOT ILIKE ", "~~*", " ILIKE ", "!~~", " NOT LIKE ", "~~", " LIKE ").Replace(expr)
+		return strings.ToUpper(exprNoiseRegexp.ReplaceAllString(expr, ""))
+	}
+	return normalize(expr1) == normalize(expr2)
+}
+
+// trimCheckConstraintDef turns the output of pg_get_constraintdef for a CHECK
+// constraint, "CHECK ((VALUE > 0))", back into the expression "VALUE > 0".
+// A trailing NOT VALID is dropped.
+func trimCheckConstraintDef(def string) string {
+	def = strings.TrimSpace(def)
+	if len(def) >= 10 && strings.EqualFold(def[len(def)-10:], " NOT VALID") {
+		def = strings.TrimSpace(def[:len(def)-10])
+	}
+	if len(def) >= 5 && strings.EqualFold(def[:5], "CHECK") {
+		def = strings.TrimSpace(def[5:])
+	}
+	for strings.HasPrefix(def, "(") && closingBracketPosition(def, 0) == len(def)-1 {
+		def = strings.TrimSpace(def[1 : len(def)-1])
+	}
+	return def
+}
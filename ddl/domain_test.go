@@ -0,0 +1,220 @@
+package ddl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_DomainCommands(t *testing.T) {
+	type TT struct {
+		item    Command
+		wantSQL string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		buf := &bytes.Buffer{}
+		err := tt.item.AppendSQL(sq.DialectPostgres, buf, nil, nil, nil)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.item = CreateDomainCommand{
+			Domain: Domain{
+				DomainSchema:   "billing",
+				DomainName:     "currency_code",
+				UnderlyingType: "CHAR(3)",
+				CollationName:  "C",
+				DomainDefault:  "'USD'",
+				IsNotNull:      true,
+				Checks: []DomainCheck{
+					{ConstraintName: "currency_code_upper", CheckExpr: "VALUE = UPPER(VALUE)"},
+					{CheckExpr: "LENGTH(VALUE) = 3"},
+				},
+			},
+		}
+		tt.wantSQL = `CREATE DOMAIN billing.currency_code AS CHAR(3) COLLATE "C" DEFAULT 'USD' NOT NULL` +
+			` CONSTRAINT currency_code_upper CHECK (VALUE = UPPER(VALUE)) CHECK (LENGTH(VALUE) = 3)`
+		assert(t, tt)
+	})
+
+	t.Run("alter", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.item = AlterDomainCommand{
+			DomainName:      "currency_code",
+			SetDefault:      "'EUR'",
+			DropNotNull:     true,
+			AddChecks:       []DomainCheck{{ConstraintName: "currency_code_check", CheckExpr: "VALUE <> ''"}},
+			DropConstraints: []string{"currency_code_upper"},
+		}
+		tt.wantSQL = "ALTER DOMAIN currency_code DROP CONSTRAINT IF EXISTS currency_code_upper;" +
+			"\nALTER DOMAIN currency_code SET DEFAULT 'EUR';" +
+			"\nALTER DOMAIN currency_code DROP NOT NULL;" +
+			"\nALTER DOMAIN currency_code ADD CONSTRAINT currency_code_check CHECK (VALUE <> '')"
+		assert(t, tt)
+	})
+}
+
+func Test_trimCheckConstraintDef(t *testing.T) {
+	for def, wantExpr := range map[string]string{
+		"CHECK ((VALUE > 0))":                                "VALUE > 0",
+		"CHECK (VALUE > 0)":                                  "VALUE > 0",
+		"CHECK (((VALUE > 0) AND (VALUE < 100)))":            "(VALUE > 0) AND (VALUE < 100)",
+		"CHECK ((VALUE)::text ~ '^[A-Z]+$'::text) NOT VALID": "(VALUE)::text ~ '^[A-Z]+$'::text",
+	} {
+		if diff := testutil.Diff(trimCheckConstraintDef(def), wantExpr); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+}
+
+func Test_WithDomains(t *testing.T) {
+	dbMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithDomains(Domain{
+		DomainName:     "percentage",
+		UnderlyingType: "INT",
+		Checks: []DomainCheck{
+			{CheckExpr: "VALUE >= 0"},
+			{ConstraintName: "percentage_max", CheckExpr: "VALUE <= 100"},
+			{CheckExpr: "VALUE % 5 = 0"},
+		},
+	}))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	wantChecks := []DomainCheck{
+		{ConstraintName: "percentage_check", CheckExpr: "VALUE >= 0"},
+		{ConstraintName: "percentage_max", CheckExpr: "VALUE <= 100"},
+		{ConstraintName: "percentage_check1", CheckExpr: "VALUE % 5 = 0"},
+	}
+	n := dbMetadata.Schemas[0].CachedDomainPosition("percentage")
+	if n < 0 {
+		t.Fatal(testutil.Callers(), "domain percentage not found")
+	}
+	if diff := testutil.Diff(dbMetadata.Schemas[0].Domains[n].Checks, wantChecks); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+}
+
+func Test_diffDomain(t *testing.T) {
+	type TT struct {
+		description string
+		gotDomain   Domain
+		wantDomain  Domain
+		wantCmds    []AlterDomainCommand
+	}
+
+	tests := []TT{{
+		description: "postgres formatting is ignored",
+		gotDomain: Domain{
+			DomainName:     "email",
+			UnderlyingType: "CHARACTER VARYING(255)",
+			DomainDefault:  "'nobody@example.com'::character varying",
+			Checks:         []DomainCheck{{ConstraintName: "email_check", CheckExpr: "(VALUE)::text ~~ '%@%'::text"}},
+		},
+		wantDomain: Domain{
+			DomainName:     "email",
+			UnderlyingType: "varchar(255)",
+			DomainDefault:  "'nobody@example.com'",
+			Checks:         []DomainCheck{{ConstraintName: "email_check", CheckExpr: "VALUE LIKE '%@%'"}},
+		},
+	}, {
+		description: "changed default and check expression",
+		gotDomain: Domain{
+			DomainName:     "amount",
+			UnderlyingType: "NUMERIC(10,2)",
+			DomainDefault:  "0",
+			Checks:         []DomainCheck{{ConstraintName: "amount_check", CheckExpr: "VALUE >= 0"}},
+		},
+		wantDomain: Domain{
+			DomainName:     "amount",
+			UnderlyingType: "NUMERIC(10, 2)",
+			DomainDefault:  "1",
+			Checks:         []DomainCheck{{ConstraintName: "amount_check", CheckExpr: "VALUE > 0"}},
+		},
+		wantCmds: []AlterDomainCommand{
+			{DomainName: "amount", DropConstraints: []string{"amount_check"}},
+			{DomainName: "amount", SetDefault: "1"},
+			{DomainName: "amount", AddChecks: []DomainCheck{{ConstraintName: "amount_check", CheckExpr: "VALUE > 0"}}},
+		},
+	}}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.description, func(t *testing.T) {
+			t.Parallel()
+			gotCmds, err := diffDomain(tt.gotDomain, tt.wantDomain, true)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(gotCmds, tt.wantCmds); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		})
+	}
+
+	t.Run("changed underlying type", func(t *testing.T) {
+		t.Parallel()
+		_, err := diffDomain(Domain{DomainName: "amount", UnderlyingType: "INTEGER"}, Domain{DomainName: "amount", UnderlyingType: "BIGINT"}, true)
+		if err == nil {
+			t.Error(testutil.Callers(), "expected an error for a changed underlying type")
+		}
+	})
+}
+
+func Test_MigrateDomainCompositeTypeOrder(t *testing.T) {
+	t.Run("composite type attribute is a domain", func(t *testing.T) {
+		t.Parallel()
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres,
+			WithCompositeTypes(CompositeType{
+				TypeName:   "line_item",
+				Attributes: []CompositeTypeAttribute{{AttributeName: "amount", AttributeType: "positive_amount"}},
+			}),
+			WithDomains(Domain{DomainName: "positive_amount", UnderlyingType: "NUMERIC(12,2)"}),
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		m, err := Migrate(CreateMissing, DatabaseMetadata{Dialect: sq.DialectPostgres}, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantSQL := "CREATE DOMAIN positive_amount AS NUMERIC(12,2);" +
+			"\n\nCREATE TYPE line_item AS (amount positive_amount);"
+		if diff := testutil.Diff(buf.String(), wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("domain over a new composite type", func(t *testing.T) {
+		t.Parallel()
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres,
+			WithCompositeTypes(CompositeType{
+				TypeName:   "point2d",
+				Attributes: []CompositeTypeAttribute{{AttributeName: "x", AttributeType: "FLOAT8"}, {AttributeName: "y", AttributeType: "FLOAT8"}},
+			}),
+			WithDomains(Domain{DomainName: "location", UnderlyingType: "point2d"}),
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		_, err = Migrate(CreateMissing, DatabaseMetadata{Dialect: sq.DialectPostgres}, wantDBMetadata)
+		if err == nil {
+			t.Error(testutil.Callers(), "expected an error for a domain over a composite type created in the same migration")
+		}
+	})
+}
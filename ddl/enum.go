@@ -1,13 +1,213 @@
 package ddl
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
 
+	"github.com/bokwoon95/sq"
+)
+
+// DDLEnum is implemented by Go enum types that can be used as column types.
+// EnumerateStringer returns every value of the enum in order, the String() of
+// each value is its label.
 type DDLEnum interface {
 	fmt.Stringer
 	EnumerateStringer() []fmt.Stringer
 }
 
 type Enum struct {
-	EnumName   string
-	EnumLabels []string
+	EnumSchema string   `json:",omitempty"`
+	EnumName   string   `json:",omitempty"`
+	EnumLabels []string `json:",omitempty"`
+	Ignore     bool     `json:",omitempty"`
+}
+
+// NewEnum returns the Enum of a DDLEnum. The enum name is the Go type name in
+// snake_case e.g. FilmRating becomes film_rating.
+func NewEnum(ddlEnum DDLEnum) (Enum, error) {
+	if ddlEnum == nil {
+		return Enum{}, fmt.Errorf("enum is nil")
+	}
+	typ := reflect.TypeOf(ddlEnum)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Name() == "" {
+		return Enum{}, fmt.Errorf("enum type %s has no name", typ.String())
+	}
+	enum := Enum{EnumName: toSnakeCase(typ.Name())}
+	for _, value := range ddlEnum.EnumerateStringer() {
+		enum.EnumLabels = append(enum.EnumLabels, value.String())
+	}
+	if len(enum.EnumLabels) == 0 {
+		return Enum{}, fmt.Errorf("enum %s has no labels", enum.EnumName)
+	}
+	return enum, nil
+}
+
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// enumColumnType returns the column type of an enum column: the enum's name
+// on postgres and an inline ENUM(...) on mysql. SQLite has no enum types, the
+// labels are enforced by a CHECK constraint instead (see enumCheckExpr).
+func enumColumnType(dialect string, enum Enum) string {
+	switch dialect {
+	case sq.DialectPostgres:
+		if enum.EnumSchema != "" {
+			return sq.QuoteIdentifier(dialect, enum.EnumSchema) + "." + sq.QuoteIdentifier(dialect, enum.EnumName)
+		}
+		return sq.QuoteIdentifier(dialect, enum.EnumName)
+	case sq.DialectMySQL:
+		// no spaces after the commas, matching the COLUMN_TYPE reported
+		// by information_schema so that the column does not show up as
+		// altered on every migration
+		return "ENUM(" + enumLabelList(enum.EnumLabels, ",") + ")"
+	}
+	return ""
+}
+
+func enumCheckExpr(dialect, columnName string, enum Enum) string {
+	return sq.QuoteIdentifier(dialect, columnName) + " IN (" + enumLabelList(enum.EnumLabels, ", ") + ")"
+}
+
+func enumLabelList(labels []string, sep string) string {
+	quoted := make([]string, len(labels))
+	for i, label := range labels {
+		quoted[i] = "'" + sq.EscapeQuote(label, '\'') + "'"
+	}
+	return strings.Join(quoted, sep)
+}
+
+type CreateEnumCommand struct {
+	Enum   Enum
+	Ignore bool
+}
+
+func (cmd CreateEnumCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=enums", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("CREATE TYPE ")
+	writeQualifiedName(dialect, buf, cmd.Enum.EnumSchema, cmd.Enum.EnumName)
+	buf.WriteString(" AS ENUM (" + enumLabelList(cmd.Enum.EnumLabels, ", ") + ")")
+	return nil
+}
+
+// AddEnumValueCommand adds a label to an existing enum. Postgres only allows
+// one label per ALTER TYPE, and labels cannot be removed once added.
+type AddEnumValueCommand struct {
+	EnumSchema     string
+	EnumName       string
+	AddIfNotExists bool
+	Value          string
+	BeforeValue    string
+	AfterValue     string
+	Ignore         bool
+}
+
+func (cmd AddEnumValueCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=enums", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("ALTER TYPE ")
+	writeQualifiedName(dialect, buf, cmd.EnumSchema, cmd.EnumName)
+	buf.WriteString(" ADD VALUE ")
+	if cmd.AddIfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	buf.WriteString("'" + sq.EscapeQuote(cmd.Value, '\'') + "'")
+	if cmd.BeforeValue != "" {
+		buf.WriteString(" BEFORE '" + sq.EscapeQuote(cmd.BeforeValue, '\'') + "'")
+	} else if cmd.AfterValue != "" {
+		buf.WriteString(" AFTER '" + sq.EscapeQuote(cmd.AfterValue, '\'') + "'")
+	}
+	return nil
+}
+
+type DropEnumCommand struct {
+	DropIfExists bool
+	EnumSchemas  []string
+	EnumNames    []string
+	DropCascade  bool
+	Ignore       bool
+}
+
+func (cmd DropEnumCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=enums", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("DROP TYPE ")
+	if cmd.DropIfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	for i, enumName := range cmd.EnumNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeQualifiedName(dialect, buf, cmd.EnumSchemas[i], enumName)
+	}
+	if cmd.DropCascade {
+		buf.WriteString(" CASCADE")
+	}
+	return nil
+}
+
+// addedEnumValues returns the commands that add the labels of wantEnum missing
+// from gotEnum. Each label is positioned relative to the label after it in
+// wantEnum, or the label before it if it is the last one.
+func addedEnumValues(gotEnum, wantEnum Enum) []AddEnumValueCommand {
+	gotLabels := make(map[string]bool, len(gotEnum.EnumLabels))
+	for _, label := range gotEnum.EnumLabels {
+		gotLabels[label] = true
+	}
+	var addEnumValueCmds []AddEnumValueCommand
+	for i, label := range wantEnum.EnumLabels {
+		if gotLabels[label] {
+			continue
+		}
+		addEnumValueCmd := AddEnumValueCommand{
+			EnumSchema:     wantEnum.EnumSchema,
+			EnumName:       wantEnum.EnumName,
+			AddIfNotExists: true,
+			Value:          label,
+		}
+		for _, nextLabel := range wantEnum.EnumLabels[i+1:] {
+			if gotLabels[nextLabel] {
+				addEnumValueCmd.BeforeValue = nextLabel
+				break
+			}
+		}
+		if addEnumValueCmd.BeforeValue == "" && i > 0 {
+			addEnumValueCmd.AfterValue = wantEnum.EnumLabels[i-1]
+		}
+		addEnumValueCmds = append(addEnumValueCmds, addEnumValueCmd)
+		gotLabels[label] = true
+	}
+	return addEnumValueCmds
 }
@@ -0,0 +1,233 @@
+package ddl
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+type FilmRating int
+
+const (
+	FilmRatingG FilmRating = iota
+	FilmRatingPG
+	FilmRatingPG13
+	FilmRatingR
+	FilmRatingNC17
+)
+
+func (r FilmRating) String() string {
+	return [...]string{"G", "PG", "PG-13", "R", "NC-17"}[r]
+}
+
+func (r FilmRating) EnumerateStringer() []fmt.Stringer {
+	return []fmt.Stringer{FilmRatingG, FilmRatingPG, FilmRatingPG13, FilmRatingR, FilmRatingNC17}
+}
+
+type ENUM_FILM struct {
+	sq.TableInfo
+	FILM_ID sq.NumberField `ddl:"primarykey"`
+	RATING  sq.StringField `ddl:"notnull"`
+}
+
+func (tbl ENUM_FILM) DDL(dialect string, t *T) {
+	t.Column(tbl.RATING).Enum(FilmRatingG)
+}
+
+func NEW_ENUM_FILM() ENUM_FILM {
+	tbl := ENUM_FILM{TableInfo: sq.TableInfo{TableName: "film"}}
+	tbl.FILM_ID = sq.NewNumberField("film_id", tbl.TableInfo)
+	tbl.RATING = sq.NewStringField("rating", tbl.TableInfo)
+	return tbl
+}
+
+func Test_NewEnum(t *testing.T) {
+	enum, err := NewEnum(FilmRatingPG)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	wantEnum := Enum{
+		EnumName:   "film_rating",
+		EnumLabels: []string{"G", "PG", "PG-13", "R", "NC-17"},
+	}
+	if diff := testutil.Diff(enum, wantEnum); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	for name, wantName := range map[string]string{
+		"Status":        "status",
+		"FilmRating":    "film_rating",
+		"HTTPMethod":    "http_method",
+		"OAuth2Scope":   "o_auth2_scope",
+		"already_snake": "already_snake",
+	} {
+		if diff := testutil.Diff(toSnakeCase(name), wantName); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+}
+
+func Test_MigrateEnum(t *testing.T) {
+	type TT struct {
+		dialect       string
+		gotDBMetadata DatabaseMetadata
+		wantSQL       string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		wantDBMetadata, err := NewDatabaseMetadata(tt.dialect, WithTables(NEW_ENUM_FILM()))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if tt.gotDBMetadata.Dialect == "" {
+			tt.gotDBMetadata.Dialect = tt.dialect
+		}
+		m, err := Migrate(CreateMissing|UpdateExisting|DropExtraneous, tt.gotDBMetadata, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("postgres create", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.wantSQL = "CREATE TYPE film_rating AS ENUM ('G', 'PG', 'PG-13', 'R', 'NC-17');" +
+			"\n\nCREATE TABLE IF NOT EXISTS film (" +
+			"\n    film_id INT" +
+			"\n    ,rating film_rating NOT NULL" +
+			"\n" +
+			"\n    ,CONSTRAINT film_film_id_pkey PRIMARY KEY (film_id)" +
+			"\n);"
+		assert(t, tt)
+	})
+
+	t.Run("postgres add values", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		gotDBMetadata, err := NewDatabaseMetadata(tt.dialect, WithTables(NEW_ENUM_FILM()))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		gotDBMetadata.Schemas[0].Enums[0].EnumLabels = []string{"PG", "R"}
+		tt.gotDBMetadata = gotDBMetadata
+		tt.wantSQL = "ALTER TYPE film_rating ADD VALUE IF NOT EXISTS 'G' BEFORE 'PG';" +
+			"\n\nALTER TYPE film_rating ADD VALUE IF NOT EXISTS 'PG-13' BEFORE 'R';" +
+			"\n\nALTER TYPE film_rating ADD VALUE IF NOT EXISTS 'NC-17' AFTER 'R';"
+		assert(t, tt)
+	})
+
+	t.Run("postgres drop", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		gotDBMetadata, err := NewDatabaseMetadata(tt.dialect, WithTables(NEW_ENUM_FILM()))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		gotDBMetadata.Schemas[0].AppendEnum(Enum{EnumName: "mood", EnumLabels: []string{"sad", "happy"}})
+		tt.gotDBMetadata = gotDBMetadata
+		tt.wantSQL = "DROP TYPE IF EXISTS mood;"
+		assert(t, tt)
+	})
+
+	t.Run("postgres schema-qualified table", func(t *testing.T) {
+		t.Parallel()
+		tbl := NEW_ENUM_FILM()
+		tbl.TableInfo.TableSchema = "public"
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(tbl))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		schema := wantDBMetadata.Schemas[0]
+		table := schema.Tables[schema.CachedTablePosition("film")]
+		if diff := testutil.Diff(table.Columns[table.CachedColumnPosition("rating")].ColumnType, "public.film_rating"); diff != "" {
+			t.Fatal(testutil.Callers(), diff)
+		}
+		for _, columnType := range []string{"FILM_RATING", "PUBLIC.FILM_RATING", "OTHER.FILM_RATING"} {
+			gotDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(tbl))
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			// format_type leaves out the schema of types on the search_path
+			schema := gotDBMetadata.Schemas[0]
+			schema.Tables[schema.CachedTablePosition("film")].Columns[table.CachedColumnPosition("rating")].ColumnType = columnType
+			m, err := Migrate(CreateMissing|UpdateExisting|DropExtraneous, gotDBMetadata, wantDBMetadata)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			buf := &bytes.Buffer{}
+			err = m.WriteSQL(buf)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			var wantSQL string
+			if columnType == "OTHER.FILM_RATING" {
+				wantSQL = "ALTER TABLE IF EXISTS public.film\n    ALTER COLUMN rating SET DATA TYPE public.film_rating;"
+			}
+			if diff := testutil.Diff(buf.String(), wantSQL); diff != "" {
+				t.Error(testutil.Callers(), columnType, diff)
+			}
+		}
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.wantSQL = "CREATE TABLE IF NOT EXISTS film (" +
+			"\n    film_id INT" +
+			"\n    ,rating ENUM('G','PG','PG-13','R','NC-17') NOT NULL" +
+			"\n" +
+			"\n    ,PRIMARY KEY (film_id)" +
+			"\n);"
+		assert(t, tt)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectSQLite
+		tt.wantSQL = "CREATE TABLE IF NOT EXISTS film (" +
+			"\n    film_id INT PRIMARY KEY" +
+			"\n    ,rating TEXT NOT NULL" +
+			"\n" +
+			"\n    ,CONSTRAINT film_rating_check CHECK (rating IN ('G', 'PG', 'PG-13', 'R', 'NC-17'))" +
+			"\n);"
+		assert(t, tt)
+	})
+}
+
+func Test_EnumSQLite(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "db.sqlite3"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer db.Close()
+	err = AutoMigrate(sq.DialectSQLite, db, CreateMissing, WithTables(NEW_ENUM_FILM()))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	_, err = db.Exec("INSERT INTO film (film_id, rating) VALUES (1, 'PG-13')")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	_, err = db.Exec("INSERT INTO film (film_id, rating) VALUES (2, 'X')")
+	if err == nil {
+		t.Error(testutil.Callers(), "expected the CHECK constraint to reject 'X'")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/fs"
+	"regexp"
 	"strings"
 
 	"github.com/bokwoon95/sq"
@@ -15,8 +16,13 @@ type Function struct {
 	RawArgs        string `json:",omitempty"`
 	ReturnType     string `json:",omitempty"`
 	SQL            string `json:",omitempty"`
-	Comment        string `json:",omitempty"`
-	Ignore         bool   `json:",omitempty"`
+	// IsProcedure and Body are only populated for MySQL, which has no CREATE
+	// OR REPLACE: Body, RawArgs and ReturnType are compared to decide if the
+	// routine has to be dropped and created again.
+	IsProcedure bool   `json:",omitempty"`
+	Body        string `json:",omitempty"`
+	Comment     string `json:",omitempty"`
+	Ignore      bool   `json:",omitempty"`
 }
 
 func (fun *Function) populateFunctionInfo(dialect string) error {
@@ -24,6 +30,9 @@ func (fun *Function) populateFunctionInfo(dialect string) error {
 		PRE_FUNCTION = iota
 		FUNCTION
 	)
+	if dialect == sq.DialectMySQL {
+		return fun.populateRoutineInfoMySQL()
+	}
 	if dialect != sq.DialectPostgres {
 		return nil
 	}
@@ -67,6 +76,240 @@ LOOP:
 	return nil
 }
 
+// populateRoutineInfoMySQL parses a MySQL CREATE FUNCTION or CREATE PROCEDURE
+// statement. Everything after the routine characteristics is the body.
+func (fun *Function) populateRoutineInfoMySQL() error {
+	if strings.TrimSpace(fun.SQL) == "" {
+		return nil
+	}
+	token, remainder := "", fun.SQL
+	for {
+		token, remainder, _ = popIdentifierToken(sq.DialectMySQL, remainder)
+		if token == "" {
+			return fmt.Errorf("could not find FUNCTION or PROCEDURE, did you write the function correctly?")
+		}
+		if strings.EqualFold(token, "FUNCTION") {
+			break
+		}
+		if strings.EqualFold(token, "PROCEDURE") {
+			fun.IsProcedure = true
+			break
+		}
+	}
+	if tokens, tmp, _ := popIdentifierTokens(sq.DialectMySQL, remainder, 3); len(tokens) == 3 &&
+		strings.EqualFold(tokens[0], "IF") && strings.EqualFold(tokens[1], "NOT") && strings.EqualFold(tokens[2], "EXISTS") {
+		remainder = tmp
+	}
+	i := strings.IndexByte(remainder, '(')
+	if i < 0 {
+		return fmt.Errorf("opening bracket for args not found")
+	}
+	j := closingBracketPosition(remainder, i)
+	if j < 0 {
+		return fmt.Errorf("closing bracket for args not found")
+	}
+	name := strings.TrimSpace(remainder[:i])
+	if k := strings.Index(name, "`.`"); k >= 0 {
+		fun.FunctionSchema, name = name[:k+1], name[k+2:]
+	} else if k := strings.IndexByte(name, '.'); k >= 0 && !strings.HasPrefix(name, "`") {
+		fun.FunctionSchema, name = name[:k], name[k+1:]
+	}
+	fun.FunctionSchema = strings.Trim(fun.FunctionSchema, "`")
+	fun.FunctionName = strings.Trim(name, "`")
+	fun.RawArgs = strings.TrimSpace(remainder[i+1 : j])
+	remainder = remainder[j+1:]
+	if !fun.IsProcedure {
+		token, tmp, _ := popIdentifierToken(sq.DialectMySQL, remainder)
+		if !strings.EqualFold(token, "RETURNS") {
+			return fmt.Errorf("function %s has no RETURNS clause", fun.FunctionName)
+		}
+		fun.ReturnType, remainder, _ = popIdentifierToken(sq.DialectMySQL, tmp)
+		if k := strings.IndexByte(fun.ReturnType, '('); k >= 0 && closingBracketPosition(fun.ReturnType, k) < 0 {
+			// the type's brackets contain spaces e.g. DECIMAL(5, 2)
+			k = strings.Index(tmp, fun.ReturnType)
+			end := closingBracketPosition(tmp, k+strings.IndexByte(fun.ReturnType, '('))
+			if end < 0 {
+				return fmt.Errorf("closing bracket for return type not found")
+			}
+			fun.ReturnType, remainder = tmp[k:end+1], tmp[end+1:]
+		}
+		// CHARSET and COLLATE belong to the return type
+		for {
+			tokens, tmp, _ := popIdentifierTokens(sq.DialectMySQL, remainder, 2)
+			if len(tokens) < 2 || (!strings.EqualFold(tokens[0], "CHARSET") && !strings.EqualFold(tokens[0], "COLLATE")) {
+				break
+			}
+			remainder = tmp
+		}
+	}
+	// skip the routine characteristics
+	for {
+		token, tmp, _ := popIdentifierToken(sq.DialectMySQL, remainder)
+		switch strings.ToUpper(token) {
+		case "DETERMINISTIC":
+			remainder = tmp
+			continue
+		case "NOT", "LANGUAGE", "CONTAINS", "NO":
+			_, remainder, _ = popIdentifierTokens(sq.DialectMySQL, tmp, 1)
+			continue
+		case "READS", "MODIFIES", "SQL":
+			_, remainder, _ = popIdentifierTokens(sq.DialectMySQL, tmp, 2)
+			continue
+		case "COMMENT":
+			tmp = strings.TrimSpace(tmp)
+			end := closingQuotePosition(tmp)
+			if end < 0 {
+				return fmt.Errorf("unterminated COMMENT in %s", fun.FunctionName)
+			}
+			remainder = tmp[end+1:]
+			continue
+		}
+		break
+	}
+	fun.Body = strings.TrimSpace(remainder)
+	return nil
+}
+
+// closingBracketPosition returns the position of the bracket that closes the
+// opening bracket at s[openingBracket], or -1 if there is none.
+func closingBracketPosition(s string, openingBracket int) int {
+	var level int
+	var quote byte
+	for i := openingBracket; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '\'' || s[i] == '"' || s[i] == '`':
+			quote = s[i]
+		case s[i] == '(':
+			level++
+		case s[i] == ')':
+			level--
+			if level == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// closingQuotePosition returns the position of the quote closing the string
+// literal that s starts with, or -1 if there is none.
+func closingQuotePosition(s string) int {
+	if s == "" || (s[0] != '\'' && s[0] != '"') {
+		return -1
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case s[0]:
+			if i+1 < len(s) && s[i+1] == s[0] {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// sameRoutineBody reports whether two MySQL routine bodies are the same,
+// ignoring differences in whitespace and a trailing semicolon.
+func sameRoutineBody(a, b string) bool {
+	normalize := func(s string) string {
+		return strings.TrimSuffix(strings.Join(strings.Fields(s), " "), ";")
+	}
+	return normalize(a) == normalize(b)
+}
+
+var (
+	routineCharsetRegexp     = regexp.MustCompile(`\s+(?:charset|character set|collate)\s+\w+`)
+	routineIntWidthRegexp    = regexp.MustCompile(`\b(tinyint|smallint|mediumint|int|integer|bigint)\(\d+\)`)
+	routinePunctuationRegexp = regexp.MustCompile(`\s*([(),])\s*`)
+)
+
+// sameRoutineSignature reports whether two MySQL routines take the same
+// arguments and return the same type. information_schema reports types in
+// lowercase, without CHARSET, COLLATE or integer display widths and with an
+// explicit IN for procedure parameters, so those differences are ignored.
+func sameRoutineSignature(fun1, fun2 Function) bool {
+	normalizeType := func(s string) string {
+		s = strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(s, "`", "")), " "))
+		s = routineCharsetRegexp.ReplaceAllString(s, "")
+		s = routineIntWidthRegexp.ReplaceAllString(s, "$1")
+		s = strings.ReplaceAll(s, "integer", "int")
+		return routinePunctuationRegexp.ReplaceAllString(s, "$1")
+	}
+	normalizeArgs := func(fun Function) string {
+		var args []string
+		rawArgs := fun.RawArgs
+		for rawArgs != "" {
+			// split on the commas outside of brackets e.g. DECIMAL(5, 2)
+			end := len(rawArgs)
+			var level int
+			for i := 0; i < len(rawArgs); i++ {
+				if rawArgs[i] == '(' {
+					level++
+				} else if rawArgs[i] == ')' {
+					level--
+				} else if rawArgs[i] == ',' && level == 0 {
+					end = i
+					break
+				}
+			}
+			arg := normalizeType(rawArgs[:end])
+			if fun.IsProcedure && !strings.HasPrefix(arg, "in ") && !strings.HasPrefix(arg, "out ") && !strings.HasPrefix(arg, "inout ") {
+				arg = "in " + arg
+			}
+			args = append(args, arg)
+			if end == len(rawArgs) {
+				break
+			}
+			rawArgs = rawArgs[end+1:]
+		}
+		return strings.Join(args, ",")
+	}
+	return fun1.IsProcedure == fun2.IsProcedure &&
+		normalizeArgs(fun1) == normalizeArgs(fun2) &&
+		normalizeType(fun1.ReturnType) == normalizeType(fun2.ReturnType)
+}
+
+// mysqlRoutineSQL rebuilds the CREATE statement of a routine introspected from
+// information_schema.routines, which only has its parts.
+func mysqlRoutineSQL(fun Function, isDeterministic bool, sqlDataAccess, securityType string) string {
+	buf := bufpool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufpool.Put(buf)
+	}()
+	if fun.IsProcedure {
+		buf.WriteString("CREATE PROCEDURE ")
+	} else {
+		buf.WriteString("CREATE FUNCTION ")
+	}
+	buf.WriteString(sq.QuoteIdentifier(sq.DialectMySQL, fun.FunctionName) + "(" + fun.RawArgs + ")")
+	if !fun.IsProcedure {
+		buf.WriteString(" RETURNS " + fun.ReturnType)
+	}
+	if isDeterministic {
+		buf.WriteString("\nDETERMINISTIC")
+	}
+	if sqlDataAccess != "" && sqlDataAccess != "CONTAINS SQL" {
+		buf.WriteString("\n" + sqlDataAccess)
+	}
+	if securityType != "" && securityType != "DEFINER" {
+		buf.WriteString("\nSQL SECURITY " + securityType)
+	}
+	if fun.Comment != "" {
+		buf.WriteString("\nCOMMENT '" + sq.EscapeQuote(fun.Comment, '\'') + "'")
+	}
+	buf.WriteString("\n" + fun.Body)
+	return buf.String()
+}
+
 func FilesToFunctions(dialect string, fsys fs.FS, filenames ...string) ([]Function, error) {
 	var functions []Function
 	for _, filename := range filenames {
@@ -114,7 +357,11 @@ func (cmd DropFunctionCommand) AppendSQL(dialect string, buf *bytes.Buffer, args
 	if dialect == sq.DialectSQLite {
 		return fmt.Errorf("sqlite does not support functions")
 	}
-	buf.WriteString("DROP FUNCTION ")
+	if cmd.Function.IsProcedure {
+		buf.WriteString("DROP PROCEDURE ")
+	} else {
+		buf.WriteString("DROP FUNCTION ")
+	}
 	if cmd.DropIfExists {
 		buf.WriteString("IF EXISTS ")
 	}
@@ -125,7 +372,8 @@ func (cmd DropFunctionCommand) AppendSQL(dialect string, buf *bytes.Buffer, args
 	if dialect == sq.DialectPostgres {
 		buf.WriteString("(" + cmd.Function.RawArgs + ")")
 	}
-	if cmd.DropCascade {
+	if cmd.DropCascade && dialect == sq.DialectPostgres {
+		// mysql routines have no dependents to cascade to
 		buf.WriteString(" CASCADE")
 	}
 	return nil
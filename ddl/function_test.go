@@ -1,6 +1,7 @@
 package ddl
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/bokwoon95/sq"
@@ -15,6 +16,8 @@ func Test_Function(t *testing.T) {
 		wantFunctionName   string
 		wantRawArgs        string
 		wantReturnType     string
+		wantIsProcedure    bool
+		wantBody           string
 	}
 
 	assert := func(t *testing.T, tt TT) {
@@ -41,6 +44,12 @@ func Test_Function(t *testing.T) {
 		if diff := testutil.Diff(tt.item.ReturnType, tt.wantReturnType); diff != "" {
 			t.Error(testutil.Callers(), diff)
 		}
+		if diff := testutil.Diff(tt.item.IsProcedure, tt.wantIsProcedure); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(tt.item.Body, tt.wantBody); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
 	}
 
 	t.Run("(dialect == postgres)", func(t *testing.T) {
@@ -144,11 +153,63 @@ CREATE OR REPLACE FUNCTION years_compare( IN year1 integer DEFAULT NULL,
 		assert(t, tt)
 	})
 
-	t.Run("(dialect != postgres)", func(t *testing.T) {
+	t.Run("(dialect == mysql)", func(t *testing.T) {
 		t.Parallel()
 		var tt TT
 		tt.dialect = sq.DialectMySQL
 		tt.item.SQL = `CREATE FUNCTION hello (s CHAR(20)) RETURNS CHAR(50) DETERMINISTIC RETURN CONCAT('Hello, ',s,'!')`
+		tt.wantFunctionName = "hello"
+		tt.wantRawArgs = "s CHAR(20)"
+		tt.wantReturnType = "CHAR(50)"
+		tt.wantBody = "RETURN CONCAT('Hello, ',s,'!')"
+		assert(t, tt)
+	})
+
+	t.Run("(dialect == mysql)", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.item.SQL = "CREATE DEFINER=`root`@`%` FUNCTION `app`.`balance`(p_id INT, p_date DATETIME) RETURNS DECIMAL(5, 2) CHARSET utf8mb4" +
+			"\n    NOT DETERMINISTIC READS SQL DATA COMMENT 'the balance (in dollars)'" +
+			"\nBEGIN\n    RETURN 0;\nEND"
+		tt.wantFunctionSchema = "app"
+		tt.wantFunctionName = "balance"
+		tt.wantRawArgs = "p_id INT, p_date DATETIME"
+		tt.wantReturnType = "DECIMAL(5, 2)"
+		tt.wantBody = "BEGIN\n    RETURN 0;\nEND"
+		assert(t, tt)
+	})
+
+	t.Run("(dialect == mysql) procedure", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.item.SQL = "CREATE PROCEDURE rewards_report (min_monthly_purchases INT, min_dollar_amount_purchased DECIMAL(10,2))" +
+			"\nLANGUAGE SQL\nNOT DETERMINISTIC\nREADS SQL DATA\nSQL SECURITY DEFINER" +
+			"\nCOMMENT 'Provides a customizable report on best customers'" +
+			"\nproc: BEGIN\n    SELECT 1;\nEND"
+		tt.wantFunctionName = "rewards_report"
+		tt.wantRawArgs = "min_monthly_purchases INT, min_dollar_amount_purchased DECIMAL(10,2)"
+		tt.wantIsProcedure = true
+		tt.wantBody = "proc: BEGIN\n    SELECT 1;\nEND"
+		assert(t, tt)
+	})
+
+	t.Run("(dialect == mysql) introspected routine", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.item.SQL = mysqlRoutineSQL(Function{
+			FunctionName: "hello",
+			RawArgs:      "s char(20)",
+			ReturnType:   "char(50)",
+			Comment:      "it's a greeting",
+			Body:         "RETURN CONCAT('Hello, ',s,'!')",
+		}, true, "NO SQL", "INVOKER")
+		tt.wantFunctionName = "hello"
+		tt.wantRawArgs = "s char(20)"
+		tt.wantReturnType = "char(50)"
+		tt.wantBody = "RETURN CONCAT('Hello, ',s,'!')"
 		assert(t, tt)
 	})
 
@@ -213,6 +274,23 @@ func Test_DropFunctionCommand(t *testing.T) {
 		assert(t, tt)
 	})
 
+	t.Run("(dialect == mysql)", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.item = DropFunctionCommand{
+			DropIfExists: true,
+			Function: Function{
+				FunctionSchema: "app",
+				FunctionName:   "rewards_report",
+				IsProcedure:    true,
+			},
+			DropCascade: true,
+		}
+		tt.wantQuery = `DROP PROCEDURE IF EXISTS app.rewards_report`
+		assert(t, tt)
+	})
+
 	t.Run("(dialect == sqlite)", func(t *testing.T) {
 		t.Parallel()
 		var tt TT
@@ -226,3 +304,112 @@ func Test_DropFunctionCommand(t *testing.T) {
 		}
 	})
 }
+
+func Test_MigrateFunctionMySQL(t *testing.T) {
+	helloV1 := Function{SQL: "CREATE FUNCTION hello (s CHAR(20)) RETURNS CHAR(50) DETERMINISTIC RETURN CONCAT('Hello, ',s,'!')"}
+	helloV2 := Function{SQL: "CREATE FUNCTION hello (s CHAR(20)) RETURNS CHAR(50) DETERMINISTIC RETURN CONCAT('Hi, ',s,'!')"}
+	report := Function{SQL: "CREATE PROCEDURE report () READS SQL DATA SELECT 1"}
+	gotDBMetadata, err := NewDatabaseMetadata(sq.DialectMySQL, WithFunctions(helloV1, report))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	// the introspected body of an unchanged routine does not keep its
+	// original whitespace
+	gotDBMetadata.Schemas[0].Functions[0].Body = "RETURN  CONCAT('Hello, ',s,'!');"
+	wantDBMetadata, err := NewDatabaseMetadata(sq.DialectMySQL, WithFunctions(helloV1))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err := Migrate(CreateMissing|UpdateExisting|DropExtraneous, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	buf := &bytes.Buffer{}
+	err = m.WriteSQL(buf)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(buf.String(), "DROP PROCEDURE IF EXISTS report;"); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	wantDBMetadata, err = NewDatabaseMetadata(sq.DialectMySQL, WithFunctions(helloV2, report))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err = Migrate(CreateMissing|UpdateExisting|DropExtraneous, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	buf.Reset()
+	err = m.WriteSQL(buf)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	wantSQL := "DROP FUNCTION IF EXISTS hello;" +
+		"\n\n-- DELIMITER ;;" +
+		"\n\nCREATE FUNCTION hello (s CHAR(20)) RETURNS CHAR(50) DETERMINISTIC RETURN CONCAT('Hi, ',s,'!'); -- ;;" +
+		"\n\n-- DELIMITER ;"
+	if diff := testutil.Diff(buf.String(), wantSQL); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	var changes []string
+	for _, change := range m.Analyze() {
+		changes = append(changes, change.String())
+	}
+	wantChanges := []string{
+		"locking: DROP FUNCTION hello (it changed and is created again, calls to it fail until then)",
+		"safe: CREATE FUNCTION hello",
+	}
+	if diff := testutil.Diff(changes, wantChanges); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	// A changed return type with the same body is also replaced.
+	helloV3 := Function{SQL: "CREATE FUNCTION hello (s CHAR(20)) RETURNS VARCHAR(50) DETERMINISTIC RETURN CONCAT('Hello, ',s,'!')"}
+	wantDBMetadata, err = NewDatabaseMetadata(sq.DialectMySQL, WithFunctions(helloV3, report))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err = Migrate(CreateMissing|UpdateExisting|DropExtraneous, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if len(m.DropReplacedFunctionCmds) != 1 {
+		t.Errorf(testutil.Callers()+" expected hello to be replaced, got %d DropReplacedFunctionCmds", len(m.DropReplacedFunctionCmds))
+	}
+}
+
+func Test_sameRoutineSignature(t *testing.T) {
+	type TT struct {
+		description string
+		fun1, fun2  Function
+		want        bool
+	}
+
+	tests := []TT{{
+		description: "introspected function",
+		fun1:        Function{RawArgs: "amount DECIMAL(5, 2), n INT(11)", ReturnType: "VARCHAR(50) CHARSET utf8mb4"},
+		fun2:        Function{RawArgs: "amount decimal(5,2), n int", ReturnType: "varchar(50)"},
+		want:        true,
+	}, {
+		description: "introspected procedure",
+		fun1:        Function{IsProcedure: true, RawArgs: "`id` INT, OUT total INT"},
+		fun2:        Function{IsProcedure: true, RawArgs: "IN id int, OUT total int"},
+		want:        true,
+	}, {
+		description: "changed argument",
+		fun1:        Function{RawArgs: "s CHAR(20)", ReturnType: "CHAR(50)"},
+		fun2:        Function{RawArgs: "s CHAR(30)", ReturnType: "CHAR(50)"},
+		want:        false,
+	}, {
+		description: "changed return type",
+		fun1:        Function{RawArgs: "s CHAR(20)", ReturnType: "CHAR(50)"},
+		fun2:        Function{RawArgs: "s CHAR(20)", ReturnType: "TEXT"},
+		want:        false,
+	}}
+
+	for _, tt := range tests {
+		if got := sameRoutineSignature(tt.fun1, tt.fun2); got != tt.want {
+			t.Errorf(testutil.Callers()+" %s: expected %v, got %v", tt.description, tt.want, got)
+		}
+	}
+}
@@ -147,3 +147,30 @@ func (cmd DropIndexCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[
 	}
 	return nil
 }
+
+type RenameIndexCommand struct {
+	TableSchema  string
+	TableName    string
+	IndexName    string
+	RenameToName string
+	Ignore       bool
+}
+
+func (cmd RenameIndexCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	var tableSchema string
+	if cmd.TableSchema != "" {
+		tableSchema = sq.QuoteIdentifier(dialect, cmd.TableSchema) + "."
+	}
+	switch dialect {
+	case sq.DialectPostgres:
+		buf.WriteString("ALTER INDEX " + tableSchema + sq.QuoteIdentifier(dialect, cmd.IndexName) + " RENAME TO " + sq.QuoteIdentifier(dialect, cmd.RenameToName))
+	case sq.DialectMySQL:
+		buf.WriteString("ALTER TABLE " + tableSchema + sq.QuoteIdentifier(dialect, cmd.TableName) + " RENAME INDEX " + sq.QuoteIdentifier(dialect, cmd.IndexName) + " TO " + sq.QuoteIdentifier(dialect, cmd.RenameToName))
+	default:
+		return fmt.Errorf("%s does not support renaming indexes", dialect)
+	}
+	return nil
+}
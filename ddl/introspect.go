@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -43,6 +44,10 @@ type Introspector interface {
 	GetTriggers(context.Context, *Filter) ([]Trigger, error)
 	GetViews(context.Context, *Filter) ([]View, error)
 	GetFunctions(context.Context, *Filter) ([]Function, error)
+	GetEnums(context.Context, *Filter) ([]Enum, error)
+	GetSequences(context.Context, *Filter) ([]Sequence, error)
+	GetDomains(context.Context, *Filter) ([]Domain, error)
+	GetCompositeTypes(context.Context, *Filter) ([]CompositeType, error)
 }
 
 type DatabaseIntrospector struct {
@@ -362,16 +367,21 @@ func (dbi *DatabaseIntrospector) GetColumns(ctx context.Context, filter *Filter)
 		var column Column
 		switch dbi.dialect {
 		case sq.DialectSQLite:
+			var tableSQL string
 			err = rows.Scan(
 				&column.TableName,
 				&column.ColumnName,
 				&column.ColumnType,
 				&column.IsNotNull,
 				&column.ColumnDefault,
+				&tableSQL,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("scanning Column: %w", err)
 			}
+			if tableSQL != "" {
+				column.IsAutoincrement = isSQLiteAutoincrement(tableSQL, column.ColumnName)
+			}
 			column.ColumnType = strings.TrimSuffix(column.ColumnType, " GENERATED ALWAYS")
 			if column.ColumnDefault != "" {
 				column.ColumnDefault = toExpr(dbi.dialect, column.ColumnDefault)
@@ -432,6 +442,25 @@ func (dbi *DatabaseIntrospector) GetColumns(ctx context.Context, filter *Filter)
 	return columns, nil
 }
 
+// isSQLiteAutoincrement reports whether the column is declared AUTOINCREMENT
+// in the CREATE TABLE statement of its table. pragma_table_xinfo does not
+// report it, and the word may also appear in comments, strings or other
+// column names, so the statement is parsed instead of searched.
+func isSQLiteAutoincrement(tableSQL, columnName string) bool {
+	dbm := DatabaseMetadata{Dialect: sq.DialectSQLite}
+	err := dbm.loadSQLStatement(tableSQL, false)
+	if err != nil || len(dbm.Schemas) == 0 || len(dbm.Schemas[0].Tables) == 0 {
+		return false
+	}
+	tbl := dbm.Schemas[0].Tables[0]
+	for _, column := range tbl.Columns {
+		if strings.EqualFold(column.ColumnName, columnName) {
+			return column.IsAutoincrement
+		}
+	}
+	return false
+}
+
 func (dbi *DatabaseIntrospector) GetConstraints(ctx context.Context, filter *Filter) ([]Constraint, error) {
 	var err error
 	var rows *sql.Rows
@@ -785,7 +814,10 @@ func (dbi *DatabaseIntrospector) GetFunctions(ctx context.Context, filter *Filte
 			return nil, err
 		}
 	case sq.DialectMySQL:
-		return nil, fmt.Errorf("(*DatabaseIntrospector).GetFunctions() has not yet been implemented for MySQL (TODO)")
+		rows, err = dbi.queryContext(ctx, embeddedFiles, "introspection_scripts/mysql_functions.sql", filter)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported dialect: %s", dbi.dialect)
 	}
@@ -805,6 +837,25 @@ func (dbi *DatabaseIntrospector) GetFunctions(ctx context.Context, filter *Filte
 			if err != nil {
 				return nil, fmt.Errorf("scanning Function: %w", err)
 			}
+		case sq.DialectMySQL:
+			var isDeterministic bool
+			var sqlDataAccess, securityType string
+			err = rows.Scan(
+				&function.FunctionSchema,
+				&function.FunctionName,
+				&function.IsProcedure,
+				&function.RawArgs,
+				&function.ReturnType,
+				&isDeterministic,
+				&sqlDataAccess,
+				&securityType,
+				&function.Comment,
+				&function.Body,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("scanning Function: %w", err)
+			}
+			function.SQL = mysqlRoutineSQL(function, isDeterministic, sqlDataAccess, securityType)
 		}
 		functions = append(functions, function)
 	}
@@ -819,6 +870,157 @@ func (dbi *DatabaseIntrospector) GetFunctions(ctx context.Context, filter *Filte
 	return functions, nil
 }
 
+func (dbi *DatabaseIntrospector) GetEnums(ctx context.Context, filter *Filter) ([]Enum, error) {
+	if dbi.dialect != sq.DialectPostgres {
+		return nil, fmt.Errorf("%w dialect=%s, feature=enums", ErrUnsupportedFeature, dbi.dialect)
+	}
+	rows, err := dbi.queryContext(ctx, embeddedFiles, "introspection_scripts/postgres_enums.sql", filter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var enums []Enum
+	for rows.Next() {
+		var enum Enum
+		var rawLabels []byte
+		err = rows.Scan(&enum.EnumSchema, &enum.EnumName, &rawLabels)
+		if err != nil {
+			return nil, fmt.Errorf("scanning Enum: %w", err)
+		}
+		err = json.Unmarshal(rawLabels, &enum.EnumLabels)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling labels of enum %s: %w", enum.EnumName, err)
+		}
+		enums = append(enums, enum)
+	}
+	err = rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Close: %w", err)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return enums, nil
+}
+
+func (dbi *DatabaseIntrospector) GetSequences(ctx context.Context, filter *Filter) ([]Sequence, error) {
+	if dbi.dialect != sq.DialectPostgres {
+		return nil, fmt.Errorf("%w dialect=%s, feature=sequences", ErrUnsupportedFeature, dbi.dialect)
+	}
+	rows, err := dbi.queryContext(ctx, embeddedFiles, "introspection_scripts/postgres_sequences.sql", filter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var sequences []Sequence
+	for rows.Next() {
+		var sequence Sequence
+		err = rows.Scan(
+			&sequence.SequenceSchema,
+			&sequence.SequenceName,
+			&sequence.DataType,
+			&sequence.StartValue,
+			&sequence.Increment,
+			&sequence.MinValue,
+			&sequence.MaxValue,
+			&sequence.Cycle,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning Sequence: %w", err)
+		}
+		sequences = append(sequences, sequence)
+	}
+	err = rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Close: %w", err)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return sequences, nil
+}
+
+func (dbi *DatabaseIntrospector) GetDomains(ctx context.Context, filter *Filter) ([]Domain, error) {
+	if dbi.dialect != sq.DialectPostgres {
+		return nil, fmt.Errorf("%w dialect=%s, feature=domains", ErrUnsupportedFeature, dbi.dialect)
+	}
+	rows, err := dbi.queryContext(ctx, embeddedFiles, "introspection_scripts/postgres_domains.sql", filter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var domains []Domain
+	for rows.Next() {
+		var domain Domain
+		var rawChecks []byte
+		err = rows.Scan(
+			&domain.DomainSchema,
+			&domain.DomainName,
+			&domain.UnderlyingType,
+			&domain.CollationName,
+			&domain.IsNotNull,
+			&domain.DomainDefault,
+			&rawChecks,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning Domain: %w", err)
+		}
+		err = json.Unmarshal(rawChecks, &domain.Checks)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling checks of domain %s: %w", domain.DomainName, err)
+		}
+		for i := range domain.Checks {
+			domain.Checks[i].CheckExpr = trimCheckConstraintDef(domain.Checks[i].CheckExpr)
+		}
+		domains = append(domains, domain)
+	}
+	err = rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Close: %w", err)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return domains, nil
+}
+
+func (dbi *DatabaseIntrospector) GetCompositeTypes(ctx context.Context, filter *Filter) ([]CompositeType, error) {
+	if dbi.dialect != sq.DialectPostgres {
+		return nil, fmt.Errorf("%w dialect=%s, feature=composite types", ErrUnsupportedFeature, dbi.dialect)
+	}
+	rows, err := dbi.queryContext(ctx, embeddedFiles, "introspection_scripts/postgres_composite_types.sql", filter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var compositeTypes []CompositeType
+	for rows.Next() {
+		var compositeType CompositeType
+		var rawAttributes []byte
+		err = rows.Scan(&compositeType.TypeSchema, &compositeType.TypeName, &rawAttributes)
+		if err != nil {
+			return nil, fmt.Errorf("scanning CompositeType: %w", err)
+		}
+		err = json.Unmarshal(rawAttributes, &compositeType.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling attributes of type %s: %w", compositeType.TypeName, err)
+		}
+		compositeTypes = append(compositeTypes, compositeType)
+	}
+	err = rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Close: %w", err)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return compositeTypes, nil
+}
+
 func (dbi *DatabaseIntrospector) GetViews(ctx context.Context, filter *Filter) ([]View, error) {
 	var err error
 	var rows *sql.Rows
@@ -1,8 +1,43 @@
 SELECT
-    *
+    routines.routine_schema AS function_schema
+    ,routines.routine_name AS function_name
+    ,routines.routine_type = 'PROCEDURE' AS is_procedure
+    ,COALESCE(params.raw_args, '') AS raw_args
+    ,CASE routines.routine_type WHEN 'FUNCTION' THEN routines.dtd_identifier ELSE '' END AS return_type
+    ,routines.is_deterministic = 'YES' AS is_deterministic
+    ,routines.sql_data_access
+    ,routines.security_type
+    ,routines.routine_comment
+    ,COALESCE(routines.routine_definition, '') AS body
 FROM
     information_schema.routines
-    JOIN information_schema.parameters ON 
-        parameters.specific_schema = routines.routine_schema
-        AND parameters.specific_name
-        -- uhhh need to GROUP BY group_concat the params first before joining it back to the routines
+    LEFT JOIN (
+        SELECT
+            specific_schema
+            ,specific_name
+            ,GROUP_CONCAT(
+                CONCAT_WS(' ', CASE routine_type WHEN 'PROCEDURE' THEN parameter_mode END, parameter_name, dtd_identifier)
+                ORDER BY ordinal_position
+                SEPARATOR ', '
+            ) AS raw_args
+        FROM
+            information_schema.parameters
+        WHERE
+            ordinal_position > 0
+        GROUP BY
+            specific_schema
+            ,specific_name
+    ) AS params ON params.specific_schema = routines.routine_schema AND params.specific_name = routines.specific_name
+WHERE
+    TRUE
+    {{ if not .IncludeSystemCatalogs }}AND routines.routine_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys'){{ end }}
+    {{ if .WithSchemas }}AND routines.routine_schema IN ({{ printList .WithSchemas }}){{ end }}
+    {{ if .WithoutSchemas }}AND routines.routine_schema NOT IN ({{ printList .WithoutSchemas }}){{ end }}
+    {{ if .WithFunctions }}AND routines.routine_name IN ({{ printList .WithFunctions }}){{ end }}
+    {{ if .WithoutFunctions }}AND routines.routine_name NOT IN ({{ printList .WithoutFunctions }}){{ end }}
+{{- if .SortOutput }}
+ORDER BY
+    routines.routine_schema
+    ,routines.routine_name
+{{- end }}
+;
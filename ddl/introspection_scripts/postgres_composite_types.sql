@@ -0,0 +1,30 @@
+SELECT
+    schemas.nspname AS type_schema
+    ,pg_type.typname AS type_name
+    ,COALESCE(
+        json_agg(
+            json_build_object('AttributeName', pg_attribute.attname, 'AttributeType', UPPER(format_type(pg_attribute.atttypid, pg_attribute.atttypmod)))
+            ORDER BY pg_attribute.attnum
+        ) FILTER (WHERE pg_attribute.attname IS NOT NULL)
+        ,'[]'
+    ) AS attributes
+FROM
+    pg_type
+    JOIN pg_class ON pg_class.oid = pg_type.typrelid
+    JOIN pg_namespace AS schemas ON schemas.oid = pg_type.typnamespace
+    LEFT JOIN pg_attribute ON pg_attribute.attrelid = pg_class.oid AND pg_attribute.attnum > 0 AND NOT pg_attribute.attisdropped
+WHERE
+    pg_type.typtype = 'c'
+    AND pg_class.relkind = 'c'
+    {{ if not .IncludeSystemCatalogs }}AND schemas.nspname <> 'information_schema' AND schemas.nspname NOT LIKE 'pg_%'{{ end }}
+    {{ if .WithSchemas }}AND schemas.nspname IN ({{ printList .WithSchemas }}){{ end }}
+    {{ if .WithoutSchemas }}AND schemas.nspname NOT IN ({{ printList .WithoutSchemas }}){{ end }}
+GROUP BY
+    schemas.nspname
+    ,pg_type.typname
+{{- if .SortOutput }}
+ORDER BY
+    schemas.nspname
+    ,pg_type.typname
+{{- end }}
+;
@@ -0,0 +1,34 @@
+SELECT
+    schemas.nspname AS domain_schema
+    ,pg_type.typname AS domain_name
+    ,UPPER(format_type(pg_type.typbasetype, pg_type.typtypmod)) AS underlying_type
+    ,COALESCE(pg_collation.collname, '') AS collation_name
+    ,pg_type.typnotnull AS is_not_null
+    ,COALESCE(pg_type.typdefault, '') AS domain_default
+    ,COALESCE(
+        (
+            SELECT
+                json_agg(json_build_object('ConstraintName', pg_constraint.conname, 'CheckExpr', pg_get_constraintdef(pg_constraint.oid)) ORDER BY pg_constraint.conname)
+            FROM
+                pg_constraint
+            WHERE
+                pg_constraint.contypid = pg_type.oid
+                AND pg_constraint.contype = 'c'
+        )
+        ,'[]'
+    ) AS checks
+FROM
+    pg_type
+    JOIN pg_namespace AS schemas ON schemas.oid = pg_type.typnamespace
+    LEFT JOIN pg_collation ON pg_collation.oid = pg_type.typcollation AND pg_type.typcollation <> (SELECT oid FROM pg_collation WHERE collname = 'default')
+WHERE
+    pg_type.typtype = 'd'
+    {{ if not .IncludeSystemCatalogs }}AND schemas.nspname <> 'information_schema' AND schemas.nspname NOT LIKE 'pg_%'{{ end }}
+    {{ if .WithSchemas }}AND schemas.nspname IN ({{ printList .WithSchemas }}){{ end }}
+    {{ if .WithoutSchemas }}AND schemas.nspname NOT IN ({{ printList .WithoutSchemas }}){{ end }}
+{{- if .SortOutput }}
+ORDER BY
+    schemas.nspname
+    ,pg_type.typname
+{{- end }}
+;
@@ -0,0 +1,33 @@
+SELECT
+    schemas.nspname AS sequence_schema
+    ,pg_class.relname AS sequence_name
+    ,UPPER(format_type(pg_sequence.seqtypid, NULL)) AS data_type
+    ,pg_sequence.seqstart AS start_value
+    ,pg_sequence.seqincrement AS increment
+    ,pg_sequence.seqmin AS min_value
+    ,pg_sequence.seqmax AS max_value
+    ,pg_sequence.seqcycle AS cycle
+FROM
+    pg_sequence
+    JOIN pg_class ON pg_class.oid = pg_sequence.seqrelid
+    JOIN pg_namespace AS schemas ON schemas.oid = pg_class.relnamespace
+WHERE
+    NOT EXISTS (
+        SELECT
+            *
+        FROM
+            pg_depend
+        WHERE
+            pg_depend.classid = 'pg_class'::regclass
+            AND pg_depend.objid = pg_class.oid
+            AND pg_depend.deptype IN ('a', 'i', 'e')
+    )
+    {{ if not .IncludeSystemCatalogs }}AND schemas.nspname <> 'information_schema' AND schemas.nspname NOT LIKE 'pg_%'{{ end }}
+    {{ if .WithSchemas }}AND schemas.nspname IN ({{ printList .WithSchemas }}){{ end }}
+    {{ if .WithoutSchemas }}AND schemas.nspname NOT IN ({{ printList .WithoutSchemas }}){{ end }}
+{{- if .SortOutput }}
+ORDER BY
+    schemas.nspname
+    ,pg_class.relname
+{{- end }}
+;
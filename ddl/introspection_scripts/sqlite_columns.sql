@@ -4,9 +4,11 @@ SELECT
     ,columns."type" AS column_type
     ,columns."notnull" AS is_notnull
     ,COALESCE(columns.dflt_value, '') AS column_default
+    ,CASE WHEN columns.pk > 0 AND tables.sql LIKE '%AUTOINCREMENT%' THEN tables.sql ELSE '' END AS table_sql
 FROM (
     SELECT
         tbl_name
+        ,sql
     FROM
         sqlite_schema
     WHERE
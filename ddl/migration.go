@@ -2,8 +2,10 @@ package ddl
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 
 	"github.com/bokwoon95/sq"
@@ -19,32 +21,65 @@ const (
 	UpdateExisting
 	DropExtraneous
 	DropCascade
+	// ValidateColRef makes Migrate check that every index and constraint
+	// refers to existing columns, and that every foreign key refers to a
+	// primary key or unique set of columns, before producing any commands.
 	ValidateColRef // NOTE: this is a good function name though, maybe rename this option to sth else
 	SetPkeyNotNull
 )
 
 type Migration struct {
-	Dialect             string
-	CurrentSchema       string
-	CreateSchemaCmds    []CreateSchemaCommand
-	CreateExtensionCmds []CreateExtensionCommand
-	CreateFunctionCmds  []CreateFunctionCommand
-	CreateTableCmds     []CreateTableCommand
-	AlterTableCmds      []AlterTableCommand // add & alter columns | add & alter constraints | add indexes
-	CreateViewCmds      []CreateViewCommand
-	CreateIndexCmds     []CreateIndexCommand
-	CreateTriggerCmds   []CreateTriggerCommand
-	AddForeignKeyCmds   []AlterTableCommand
-	DropViewCmds        []DropViewCommand
-	DropTableCmds       []DropTableCommand
-	DropTriggerCmds     []DropTriggerCommand
-	DropIndexCmds       []DropIndexCommand
-	AlterTableDropCmds  []AlterTableCommand
-	DropFunctionCmds    []DropFunctionCommand
-	DropExtensionCmds   []DropExtensionCommand
+	Dialect                  string
+	CurrentSchema            string
+	CreateSchemaCmds         []CreateSchemaCommand
+	CreateExtensionCmds      []CreateExtensionCommand
+	CreateEnumCmds           []CreateEnumCommand
+	AddEnumValueCmds         []AddEnumValueCommand
+	CreateSequenceCmds       []CreateSequenceCommand
+	AlterSequenceCmds        []AlterSequenceCommand
+	CreateDomainCmds         []CreateDomainCommand
+	AlterDomainCmds          []AlterDomainCommand
+	CreateCompositeTypeCmds  []CreateCompositeTypeCommand // after domains, so attributes can be domains
+	AlterCompositeTypeCmds   []AlterCompositeTypeCommand
+	DropReplacedFunctionCmds []DropFunctionCommand // mysql-only
+	CreateFunctionCmds       []CreateFunctionCommand
+	RenameTableCmds          []RenameTableCommand
+	RenameColumnCmds         []RenameColumnCommand
+	RenameConstraintCmds     []RenameConstraintCommand
+	RenameIndexCmds          []RenameIndexCommand
+	CreateTableCmds          []CreateTableCommand
+	RebuildTableCmds         []RebuildTableCommand // sqlite-only
+	AlterTableCmds           []AlterTableCommand   // add & alter columns | add & alter constraints | add indexes
+	CreateViewCmds           []CreateViewCommand
+	CreateIndexCmds          []CreateIndexCommand
+	CreateTriggerCmds        []CreateTriggerCommand
+	AddForeignKeyCmds        []AlterTableCommand
+	DropViewCmds             []DropViewCommand
+	DropTableCmds            []DropTableCommand
+	DropTriggerCmds          []DropTriggerCommand
+	DropIndexCmds            []DropIndexCommand
+	AlterTableDropCmds       []AlterTableCommand
+	DropFunctionCmds         []DropFunctionCommand
+	DropCompositeTypeCmds    []DropCompositeTypeCommand
+	DropDomainCmds           []DropDomainCommand
+	DropEnumCmds             []DropEnumCommand
+	DropSequenceCmds         []DropSequenceCommand
+	DropExtensionCmds        []DropExtensionCommand
+	// mode, gotDBMetadata and wantDBMetadata are the arguments the Migration
+	// was built from, Reverse needs them to build the inverse Migration.
+	mode           MigrationMode
+	gotDBMetadata  DatabaseMetadata
+	wantDBMetadata DatabaseMetadata
 }
 
 func AutoMigrate(dialect string, db sq.DB, migrationMode MigrationMode, databaseMetadataOpts ...DatabaseMetadataOption) error {
+	return AutoMigratePolicy(dialect, db, migrationMode, MigrationPolicy{AllowLocking: true, AllowDestructive: true}, databaseMetadataOpts...)
+}
+
+// AutoMigratePolicy is like AutoMigrate, but analyzes the migration first and
+// refuses to execute it (returning an error wrapping ErrMigrationRefused) if
+// it contains any changes that the policy does not allow.
+func AutoMigratePolicy(dialect string, db sq.DB, migrationMode MigrationMode, policy MigrationPolicy, databaseMetadataOpts ...DatabaseMetadataOption) error {
 	gotDBMetadata, err := NewDatabaseMetadata(dialect, WithDB(db, nil))
 	if err != nil {
 		return fmt.Errorf("introspecting db: %w", err)
@@ -53,13 +88,14 @@ func AutoMigrate(dialect string, db sq.DB, migrationMode MigrationMode, database
 	if err != nil {
 		return fmt.Errorf("building db metadata: %w", err)
 	}
-	if migrationMode&ValidateColRef != 0 {
-		// TODO: validate indexes, pkey, unique, fkey, excl
-	}
 	migration, err := Migrate(migrationMode, gotDBMetadata, wantDBMetadata)
 	if err != nil {
 		return fmt.Errorf("building migration: %w", err)
 	}
+	err = policy.Check(migration.Analyze())
+	if err != nil {
+		return err
+	}
 	err = migration.Exec(db)
 	if err != nil {
 		return fmt.Errorf("executing migration: %w", err)
@@ -69,8 +105,11 @@ func AutoMigrate(dialect string, db sq.DB, migrationMode MigrationMode, database
 
 func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata) (*Migration, error) {
 	m := &Migration{
-		Dialect:       gotDBMetadata.Dialect,
-		CurrentSchema: gotDBMetadata.CurrentSchema,
+		Dialect:        gotDBMetadata.Dialect,
+		CurrentSchema:  gotDBMetadata.CurrentSchema,
+		mode:           mode,
+		gotDBMetadata:  gotDBMetadata,
+		wantDBMetadata: wantDBMetadata,
 	}
 	if gotDBMetadata.Dialect == "" && wantDBMetadata.Dialect == "" {
 		return m, fmt.Errorf("dialect missing")
@@ -81,6 +120,22 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 	if m.Dialect == "" {
 		m.Dialect = wantDBMetadata.Dialect
 	}
+	if mode&ValidateColRef != 0 {
+		err := validateColRef(wantDBMetadata, gotDBMetadata)
+		if err != nil {
+			return m, err
+		}
+	}
+	// renamedTables maps the {schema, name} of renamed tables to their new
+	// name. renamedColumns, renamedConstraints and renamedIndexes hold the
+	// {schema, table, name} of renamed columns, constraints and indexes, using
+	// the old table name. They stop the renamed objects from being dropped as
+	// extraneous.
+	renamedTables := make(map[[2]string]string)
+	renamedColumns := make(map[[3]string]bool)
+	renamedConstraints := make(map[[3]string]bool)
+	renamedIndexes := make(map[[3]string]bool)
+	rebuiltTables := make(map[[2]string]bool)
 	if mode&CreateMissing != 0 || mode&UpdateExisting != 0 {
 		if m.Dialect == sq.DialectPostgres && mode&CreateMissing != 0 {
 			for _, wantExtension := range wantDBMetadata.Extensions {
@@ -115,6 +170,77 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 					m.CreateSchemaCmds = append(m.CreateSchemaCmds, createSchemaCmd)
 				}
 			}
+			if m.Dialect == sq.DialectPostgres {
+				for _, wantEnum := range wantSchema.Enums {
+					if wantEnum.Ignore {
+						continue
+					}
+					if n := gotSchema.CachedEnumPosition(wantEnum.EnumName); n >= 0 {
+						if mode&UpdateExisting != 0 {
+							m.AddEnumValueCmds = append(m.AddEnumValueCmds, addedEnumValues(gotSchema.Enums[n], wantEnum)...)
+						}
+					} else if mode&CreateMissing != 0 {
+						createEnumCmd := CreateEnumCommand{
+							Enum: wantEnum,
+						}
+						m.CreateEnumCmds = append(m.CreateEnumCmds, createEnumCmd)
+					}
+				}
+				for _, wantSequence := range wantSchema.Sequences {
+					if wantSequence.Ignore {
+						continue
+					}
+					if n := gotSchema.CachedSequencePosition(wantSequence.SequenceName); n >= 0 {
+						if mode&UpdateExisting != 0 {
+							if alterSequenceCmd, isDifferent := diffSequence(gotSchema.Sequences[n], wantSequence); isDifferent {
+								m.AlterSequenceCmds = append(m.AlterSequenceCmds, alterSequenceCmd)
+							}
+						}
+					} else if mode&CreateMissing != 0 {
+						createSequenceCmd := CreateSequenceCommand{
+							CreateIfNotExists: true,
+							Sequence:          wantSequence,
+						}
+						m.CreateSequenceCmds = append(m.CreateSequenceCmds, createSequenceCmd)
+					}
+				}
+				for _, wantDomain := range wantSchema.Domains {
+					if wantDomain.Ignore {
+						continue
+					}
+					if n := gotSchema.CachedDomainPosition(wantDomain.DomainName); n >= 0 {
+						if mode&UpdateExisting != 0 {
+							alterDomainCmds, err := diffDomain(gotSchema.Domains[n], wantDomain, mode&DropExtraneous != 0)
+							if err != nil {
+								return m, err
+							}
+							m.AlterDomainCmds = append(m.AlterDomainCmds, alterDomainCmds...)
+						}
+					} else if mode&CreateMissing != 0 {
+						createDomainCmd := CreateDomainCommand{
+							Domain: wantDomain,
+						}
+						m.CreateDomainCmds = append(m.CreateDomainCmds, createDomainCmd)
+					}
+				}
+				for _, wantCompositeType := range wantSchema.CompositeTypes {
+					if wantCompositeType.Ignore {
+						continue
+					}
+					if n := gotSchema.CachedCompositeTypePosition(wantCompositeType.TypeName); n >= 0 {
+						if mode&UpdateExisting != 0 {
+							if alterCompositeTypeCmd, isDifferent := diffCompositeType(gotSchema.CompositeTypes[n], wantCompositeType, mode&DropExtraneous != 0); isDifferent {
+								m.AlterCompositeTypeCmds = append(m.AlterCompositeTypeCmds, alterCompositeTypeCmd)
+							}
+						}
+					} else if mode&CreateMissing != 0 {
+						createCompositeTypeCmd := CreateCompositeTypeCommand{
+							CompositeType: wantCompositeType,
+						}
+						m.CreateCompositeTypeCmds = append(m.CreateCompositeTypeCmds, createCompositeTypeCmd)
+					}
+				}
+			}
 			for _, wantTable := range wantSchema.Tables {
 				if wantTable.Ignore {
 					continue
@@ -134,13 +260,32 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 					TableSchema: wantTable.TableSchema,
 					TableName:   wantTable.TableName,
 				}
+				var isRenamed, isRebuilt bool
 				if n := gotSchema.CachedTablePosition(wantTable.TableName); n >= 0 {
 					tableExists = true
 					gotTable = gotSchema.Tables[n]
+				} else if n := renamedTablePosition(gotSchema, wantSchema, wantTable); n >= 0 {
+					tableExists = true
+					isRenamed = true
+					gotTable = gotSchema.Tables[n]
+					renamedTables[[2]string{gotSchema.SchemaName, gotTable.TableName}] = wantTable.TableName
+					renameTableCmd := RenameTableCommand{
+						AlterIfExists:   m.Dialect == sq.DialectPostgres,
+						TableSchemas:    []string{wantTable.TableSchema},
+						TableNames:      []string{gotTable.TableName},
+						RenameToSchemas: []string{wantTable.TableSchema},
+						RenameToNames:   []string{wantTable.TableName},
+					}
+					m.RenameTableCmds = append(m.RenameTableCmds, renameTableCmd)
 				} else if mode&CreateMissing != 0 {
 					createTableCmd.Table = wantTable
 				}
+				// columnRenames maps the old names of renamed columns to their
+				// new names.
+				columnRenames := make(map[string]string)
 				if tableExists {
+					var renameColumnCmds []RenameColumnCommand
+					var renamedColumnChanged bool
 					for _, wantColumn := range wantTable.Columns {
 						if wantColumn.Ignore {
 							continue
@@ -150,7 +295,24 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 							TableName:   wantColumn.TableName,
 							ColumnName:  wantColumn.ColumnName,
 						}
-						if n := gotTable.CachedColumnPosition(wantColumn.ColumnName); n >= 0 {
+						n := gotTable.CachedColumnPosition(wantColumn.ColumnName)
+						isRenamedColumn := false
+						if n < 0 {
+							n = renamedColumnPosition(gotTable, wantTable, wantColumn)
+							if n >= 0 {
+								isRenamedColumn = true
+								columnRenames[gotTable.Columns[n].ColumnName] = wantColumn.ColumnName
+								renamedColumns[[3]string{gotSchema.SchemaName, gotTable.TableName, gotTable.Columns[n].ColumnName}] = true
+								renameColumnCmd := RenameColumnCommand{
+									TableSchema:  wantTable.TableSchema,
+									TableName:    wantTable.TableName,
+									ColumnName:   gotTable.Columns[n].ColumnName,
+									RenameToName: wantColumn.ColumnName,
+								}
+								renameColumnCmds = append(renameColumnCmds, renameColumnCmd)
+							}
+						}
+						if n >= 0 {
 							if mode&UpdateExisting != 0 {
 								gotColumn = gotTable.Columns[n]
 								alterColumnCmd, isDifferent := diffColumn(m.Dialect, gotColumn, wantColumn)
@@ -159,6 +321,9 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 										alterColumnCmd.Column = wantColumn
 									}
 									alterTableCmd.AlterColumnCmds = append(alterTableCmd.AlterColumnCmds, alterColumnCmd)
+									if isRenamedColumn {
+										renamedColumnChanged = true
+									}
 								}
 							}
 						} else if mode&CreateMissing != 0 {
@@ -169,7 +334,25 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 							alterTableCmd.AddColumnCmds = append(alterTableCmd.AddColumnCmds, addColumnCmd)
 						}
 					}
+					// SQLite cannot change a column definition with ALTER
+					// TABLE, which usually goes unnoticed because the change
+					// is skipped. But a column that is renamed and changed
+					// at the same time would otherwise silently keep its old
+					// definition, so the table is rebuilt instead.
+					if m.Dialect == sq.DialectSQLite && renamedColumnChanged {
+						rebuildTableCmd, ok := rebuildTable(gotTable, wantTable, columnRenames)
+						if ok {
+							isRebuilt = true
+							rebuiltTables[[2]string{gotSchema.SchemaName, gotTable.TableName}] = true
+							m.RebuildTableCmds = append(m.RebuildTableCmds, rebuildTableCmd)
+							alterTableCmd.AddColumnCmds = nil
+							alterTableCmd.AlterColumnCmds = nil
+							renameColumnCmds = nil
+						}
+					}
+					m.RenameColumnCmds = append(m.RenameColumnCmds, renameColumnCmds...)
 				}
+				hasRenames := isRenamed || len(columnRenames) > 0
 				if m.Dialect != sq.DialectSQLite {
 					for _, wantConstraint := range wantTable.Constraints {
 						if wantConstraint.Ignore {
@@ -178,6 +361,35 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 						if n := gotTable.CachedConstraintPosition(wantConstraint.ConstraintName); n >= 0 {
 							continue
 						}
+						if hasRenames {
+							n := renamedConstraintPosition(m.Dialect, gotTable, wantTable, wantConstraint, columnRenames, func(constraintName string) bool {
+								return renamedConstraints[[3]string{gotSchema.SchemaName, gotTable.TableName, constraintName}]
+							})
+							if n >= 0 {
+								gotConstraint := gotTable.Constraints[n]
+								renamedConstraints[[3]string{gotSchema.SchemaName, gotTable.TableName, gotConstraint.ConstraintName}] = true
+								switch {
+								case m.Dialect == sq.DialectPostgres:
+									renameConstraintCmd := RenameConstraintCommand{
+										TableSchema:    wantTable.TableSchema,
+										TableName:      wantTable.TableName,
+										ConstraintName: gotConstraint.ConstraintName,
+										RenameToName:   wantConstraint.ConstraintName,
+									}
+									m.RenameConstraintCmds = append(m.RenameConstraintCmds, renameConstraintCmd)
+								case wantConstraint.ConstraintType == UNIQUE:
+									// mysql UNIQUE constraints are indexes.
+									renameIndexCmd := RenameIndexCommand{
+										TableSchema:  wantTable.TableSchema,
+										TableName:    wantTable.TableName,
+										IndexName:    gotConstraint.ConstraintName,
+										RenameToName: wantConstraint.ConstraintName,
+									}
+									m.RenameIndexCmds = append(m.RenameIndexCmds, renameIndexCmd)
+								}
+								continue
+							}
+						}
 						addConstraintCmd := AddConstraintCommand{
 							Constraint: wantConstraint,
 						}
@@ -192,8 +404,29 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 					if wantIndex.Ignore {
 						continue
 					}
-					if n := gotTable.CachedIndexPosition(wantIndex.IndexName); n >= 0 {
-						continue
+					// A rebuilt table loses its indexes and triggers, so
+					// they must all be created again.
+					if !isRebuilt {
+						if n := gotTable.CachedIndexPosition(wantIndex.IndexName); n >= 0 {
+							continue
+						}
+					}
+					if hasRenames && m.Dialect != sq.DialectSQLite {
+						n := renamedIndexPosition(gotTable, wantTable, wantIndex, columnRenames, func(indexName string) bool {
+							return renamedIndexes[[3]string{gotSchema.SchemaName, gotTable.TableName, indexName}]
+						})
+						if n >= 0 {
+							gotIndex := gotTable.Indexes[n]
+							renamedIndexes[[3]string{gotSchema.SchemaName, gotTable.TableName, gotIndex.IndexName}] = true
+							renameIndexCmd := RenameIndexCommand{
+								TableSchema:  wantTable.TableSchema,
+								TableName:    wantTable.TableName,
+								IndexName:    gotIndex.IndexName,
+								RenameToName: wantIndex.IndexName,
+							}
+							m.RenameIndexCmds = append(m.RenameIndexCmds, renameIndexCmd)
+							continue
+						}
 					}
 					createIndexCmd := CreateIndexCommand{
 						CreateIfNotExists: m.Dialect != sq.DialectMySQL,
@@ -213,7 +446,7 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 					if wantTrigger.Ignore {
 						continue
 					}
-					if n := gotTable.CachedTriggerPosition(wantTrigger.TriggerName); n >= 0 {
+					if n := gotTable.CachedTriggerPosition(wantTrigger.TriggerName); n >= 0 && !isRebuilt {
 						continue
 					}
 					createTriggerCmd := CreateTriggerCommand{
@@ -278,7 +511,19 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 						continue
 					}
 					if positions := gotSchema.CachedFunctionPositions(wantFunction.FunctionName); len(positions) > 0 {
-						continue
+						// mysql has no CREATE OR REPLACE, a routine whose
+						// body, arguments or return type changed is dropped
+						// and created again.
+						gotFunction := gotSchema.Functions[positions[0]]
+						if m.Dialect != sq.DialectMySQL || mode&UpdateExisting == 0 || gotFunction.Body == "" || wantFunction.Body == "" ||
+							(sameRoutineBody(gotFunction.Body, wantFunction.Body) && sameRoutineSignature(gotFunction, wantFunction)) {
+							continue
+						}
+						dropFunctionCmd := DropFunctionCommand{
+							DropIfExists: true,
+							Function:     gotFunction,
+						}
+						m.DropReplacedFunctionCmds = append(m.DropReplacedFunctionCmds, dropFunctionCmd)
 					}
 					createFunctionCmd := CreateFunctionCommand{
 						Function: wantFunction,
@@ -306,6 +551,22 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 			DropIfExists: true,
 			DropCascade:  mode&DropCascade != 0,
 		}
+		dropEnumCmd := DropEnumCommand{
+			DropIfExists: true,
+			DropCascade:  mode&DropCascade != 0,
+		}
+		dropSequenceCmd := DropSequenceCommand{
+			DropIfExists: true,
+			DropCascade:  mode&DropCascade != 0,
+		}
+		dropDomainCmd := DropDomainCommand{
+			DropIfExists: true,
+			DropCascade:  mode&DropCascade != 0,
+		}
+		dropCompositeTypeCmd := DropCompositeTypeCommand{
+			DropIfExists: true,
+			DropCascade:  mode&DropCascade != 0,
+		}
 		var alterTableCmds []AlterTableCommand
 		// drop extensions
 		for _, gotExtension := range gotDBMetadata.Extensions {
@@ -343,18 +604,29 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 				}
 				if n := wantSchema.CachedTablePosition(gotTable.TableName); n >= 0 {
 					wantTable = wantSchema.Tables[n]
+				} else if n := wantSchema.CachedTablePosition(renamedTables[[2]string{gotSchema.SchemaName, gotTable.TableName}]); n >= 0 {
+					wantTable = wantSchema.Tables[n]
 				} else {
 					dropTableCmd.TableSchemas = append(dropTableCmd.TableSchemas, gotTable.TableSchema)
 					dropTableCmd.TableNames = append(dropTableCmd.TableNames, gotTable.TableName)
 					continue
 				}
+				if rebuiltTables[[2]string{gotSchema.SchemaName, gotTable.TableName}] {
+					// the rebuilt table has nothing extraneous left to drop
+					continue
+				}
+				isRenamed := func(renamed map[[3]string]bool, name string) bool {
+					return renamed[[3]string{gotSchema.SchemaName, gotTable.TableName, name}]
+				}
+				// by now the table has already been renamed, so use the new
+				// table name
 				alterTableCmd := AlterTableCommand{
 					TableSchema: gotTable.TableSchema,
-					TableName:   gotTable.TableName,
+					TableName:   wantTable.TableName,
 				}
 				// drop columns
 				for _, gotColumn := range gotTable.Columns {
-					if n := wantTable.CachedColumnPosition(gotColumn.ColumnName); n >= 0 {
+					if n := wantTable.CachedColumnPosition(gotColumn.ColumnName); n >= 0 || isRenamed(renamedColumns, gotColumn.ColumnName) {
 						continue
 					}
 					dropColumnCmd := DropColumnCommand{
@@ -367,7 +639,7 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 				// drop constraints
 				if m.Dialect != sq.DialectSQLite {
 					for _, gotConstraint := range gotTable.Constraints {
-						if n := wantTable.CachedConstraintPosition(gotConstraint.ConstraintName); n >= 0 {
+						if n := wantTable.CachedConstraintPosition(gotConstraint.ConstraintName); n >= 0 || isRenamed(renamedConstraints, gotConstraint.ConstraintName) {
 							continue
 						}
 						dropConstraintCmd := DropConstraintCommand{
@@ -380,13 +652,13 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 				}
 				// drop indexes
 				for _, gotIndex := range gotTable.Indexes {
-					if n := wantTable.CachedIndexPosition(gotIndex.IndexName); n >= 0 {
+					if n := wantTable.CachedIndexPosition(gotIndex.IndexName); n >= 0 || isRenamed(renamedIndexes, gotIndex.IndexName) {
 						continue
 					}
 					dropIndexCmd := DropIndexCommand{
 						DropIfExists: m.Dialect == sq.DialectPostgres || m.Dialect == sq.DialectSQLite,
 						TableSchema:  gotIndex.TableSchema,
-						TableName:    gotIndex.TableName,
+						TableName:    wantTable.TableName,
 						IndexName:    gotIndex.IndexName,
 						DropCascade:  m.Dialect == sq.DialectPostgres,
 					}
@@ -404,7 +676,7 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 					dropTriggerCmd := DropTriggerCommand{
 						DropIfExists: true,
 						TableSchema:  gotTrigger.TableSchema,
-						TableName:    gotTrigger.TableName,
+						TableName:    wantTable.TableName,
 						TriggerName:  gotTrigger.TriggerName,
 						DropCascade:  m.Dialect == sq.DialectPostgres,
 					}
@@ -441,6 +713,38 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 				}
 				m.DropFunctionCmds = append(m.DropFunctionCmds, dropFunctionCmd)
 			}
+			// drop enums
+			for _, gotEnum := range gotSchema.Enums {
+				if n := wantSchema.CachedEnumPosition(gotEnum.EnumName); n >= 0 {
+					continue
+				}
+				dropEnumCmd.EnumSchemas = append(dropEnumCmd.EnumSchemas, gotEnum.EnumSchema)
+				dropEnumCmd.EnumNames = append(dropEnumCmd.EnumNames, gotEnum.EnumName)
+			}
+			// drop sequences
+			for _, gotSequence := range gotSchema.Sequences {
+				if n := wantSchema.CachedSequencePosition(gotSequence.SequenceName); n >= 0 {
+					continue
+				}
+				dropSequenceCmd.SequenceSchemas = append(dropSequenceCmd.SequenceSchemas, gotSequence.SequenceSchema)
+				dropSequenceCmd.SequenceNames = append(dropSequenceCmd.SequenceNames, gotSequence.SequenceName)
+			}
+			// drop domains
+			for _, gotDomain := range gotSchema.Domains {
+				if n := wantSchema.CachedDomainPosition(gotDomain.DomainName); n >= 0 {
+					continue
+				}
+				dropDomainCmd.DomainSchemas = append(dropDomainCmd.DomainSchemas, gotDomain.DomainSchema)
+				dropDomainCmd.DomainNames = append(dropDomainCmd.DomainNames, gotDomain.DomainName)
+			}
+			// drop composite types
+			for _, gotCompositeType := range gotSchema.CompositeTypes {
+				if n := wantSchema.CachedCompositeTypePosition(gotCompositeType.TypeName); n >= 0 {
+					continue
+				}
+				dropCompositeTypeCmd.TypeSchemas = append(dropCompositeTypeCmd.TypeSchemas, gotCompositeType.TypeSchema)
+				dropCompositeTypeCmd.TypeNames = append(dropCompositeTypeCmd.TypeNames, gotCompositeType.TypeName)
+			}
 		}
 		if m.Dialect == sq.DialectSQLite {
 			m.DropViewCmds = append(m.DropViewCmds, decomposeDropViewCommandSQLite2(dropViewCmd)...)
@@ -461,6 +765,30 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 			if len(dropTableCmd.TableNames) > 0 {
 				m.DropTableCmds = append(m.DropTableCmds, dropTableCmd)
 			}
+			if len(dropCompositeTypeCmd.TypeNames) > 0 {
+				m.DropCompositeTypeCmds = append(m.DropCompositeTypeCmds, dropCompositeTypeCmd)
+			}
+			if len(dropDomainCmd.DomainNames) > 0 {
+				m.DropDomainCmds = append(m.DropDomainCmds, dropDomainCmd)
+			}
+			if len(dropEnumCmd.EnumNames) > 0 {
+				m.DropEnumCmds = append(m.DropEnumCmds, dropEnumCmd)
+			}
+			if len(dropSequenceCmd.SequenceNames) > 0 {
+				m.DropSequenceCmds = append(m.DropSequenceCmds, dropSequenceCmd)
+			}
+		}
+	}
+	// Domains are created before composite types so that composite type
+	// attributes can be domains, which means a domain cannot be created over
+	// a composite type that is created in the same migration.
+	for _, createDomainCmd := range m.CreateDomainCmds {
+		domain := createDomainCmd.Domain
+		for _, createCompositeTypeCmd := range m.CreateCompositeTypeCmds {
+			compositeType := createCompositeTypeCmd.CompositeType
+			if sameDataType(sq.DialectPostgres, strings.TrimSuffix(domain.UnderlyingType, "[]"), qualifiedName(compositeType.TypeSchema, compositeType.TypeName)) {
+				return m, fmt.Errorf("domain %s: its underlying type %s is a composite type created in the same migration, create the composite type in an earlier migration", qualifiedName(domain.DomainSchema, domain.DomainName), domain.UnderlyingType)
+			}
 		}
 	}
 	return m, nil
@@ -468,8 +796,11 @@ func Migrate(mode MigrationMode, gotDBMetadata, wantDBMetadata DatabaseMetadata)
 
 // TODO: this function is broken, check got_metadata.json and want_metadata.json and make sure it works
 func diffColumn(dialect string, gotColumn, wantColumn Column) (alterColumnCmd AlterColumnCommand, isDifferent bool) {
+	alterColumnCmd.Column.TableSchema = wantColumn.TableSchema
+	alterColumnCmd.Column.TableName = wantColumn.TableName
+	alterColumnCmd.Column.ColumnName = wantColumn.ColumnName
 	// do we SET DATA TYPE?
-	if !strings.EqualFold(gotColumn.ColumnType, wantColumn.ColumnType) {
+	if !sameDataType(dialect, gotColumn.ColumnType, wantColumn.ColumnType) {
 		isDifferent = true
 		alterColumnCmd.Column.ColumnType = wantColumn.ColumnType
 	}
@@ -509,6 +840,67 @@ func diffColumn(dialect string, gotColumn, wantColumn Column) (alterColumnCmd Al
 	return alterColumnCmd, isDifferent
 }
 
+var (
+	// typeSchemaPrefix matches the schema that qualifies a user-defined
+	// type such as public.film_rating.
+	typeSchemaPrefix    = regexp.MustCompile(`^(?:"(?:[^"]|"")*"|[A-Za-z_][A-Za-z0-9_$]*)\.`)
+	timePrecisionRegexp = regexp.MustCompile(`^(TIME(?:STAMP)?)(\([0-9]+\)) (WITH(?:OUT)? TIME ZONE)`)
+	postgresTypeRegexp  = regexp.MustCompile(`^([A-Z0-9_ ]+?)\s*(\(.*\))?(\[\])?$`)
+	postgresTypeAliases = map[string]string{
+		"VARCHAR":     "CHARACTER VARYING",
+		"CHAR":        "CHARACTER",
+		"BPCHAR":      "CHARACTER",
+		"INT":         "INTEGER",
+		"INT4":        "INTEGER",
+		"INT2":        "SMALLINT",
+		"INT8":        "BIGINT",
+		"FLOAT4":      "REAL",
+		"FLOAT8":      "DOUBLE PRECISION",
+		"BOOL":        "BOOLEAN",
+		"DECIMAL":     "NUMERIC",
+		"TIMESTAMP":   "TIMESTAMP WITHOUT TIME ZONE",
+		"TIMESTAMPTZ": "TIMESTAMP WITH TIME ZONE",
+		"TIME":        "TIME WITHOUT TIME ZONE",
+		"TIMETZ":      "TIME WITH TIME ZONE",
+	}
+)
+
+// sameDataType reports whether two data types (of a column, domain, sequence
+// or composite type attribute) are the same, ignoring case. On postgres the
+// introspected types come from format_type, so it also ignores whitespace,
+// the differences between a type's alias (e.g. VARCHAR(255)) and the name
+// format_type reports for it (CHARACTER VARYING(255)), and the schema that
+// format_type leaves out for types on the search_path.
+func sameDataType(dialect, gotType, wantType string) bool {
+	if strings.EqualFold(gotType, wantType) {
+		return true
+	}
+	if dialect != sq.DialectPostgres {
+		return false
+	}
+	normalize := func(typ string) string {
+		typ = strings.ToUpper(strings.Join(strings.Fields(typ), " "))
+		typ = strings.ReplaceAll(typ, ", ", ",")
+		// TIMESTAMP(3) WITHOUT TIME ZONE => TIMESTAMP WITHOUT TIME ZONE(3)
+		typ = timePrecisionRegexp.ReplaceAllString(typ, "$1 $3$2")
+		match := postgresTypeRegexp.FindStringSubmatch(typ)
+		if match == nil {
+			return typ
+		}
+		if alias, ok := postgresTypeAliases[match[1]]; ok {
+			match[1] = alias
+		}
+		return match[1] + match[2] + match[3]
+	}
+	gotUnqualified := typeSchemaPrefix.ReplaceAllString(gotType, "")
+	wantUnqualified := typeSchemaPrefix.ReplaceAllString(wantType, "")
+	if gotUnqualified != gotType && wantUnqualified != wantType {
+		// both types name a schema, compare them as is
+		return normalize(gotType) == normalize(wantType)
+	}
+	return normalize(gotUnqualified) == normalize(wantUnqualified)
+}
+
 func (m *Migration) WriteSQL(w io.Writer) error {
 	var err error
 	var written bool
@@ -557,9 +949,94 @@ func (m *Migration) WriteSQL(w io.Writer) error {
 			return err
 		}
 	}
+	for _, cmd := range m.CreateEnumCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AddEnumValueCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.CreateSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AlterSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.CreateDomainCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AlterDomainCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.CreateCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AlterCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropReplacedFunctionCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
 	if len(m.CreateFunctionCmds) > 0 {
 		if m.Dialect == sq.DialectMySQL {
-			io.WriteString(w, "\n\n-- DELIMITER ;;")
+			if written {
+				io.WriteString(w, "\n\n")
+			}
+			io.WriteString(w, "-- DELIMITER ;;")
+			written = true
 		}
 		for _, cmd := range m.CreateFunctionCmds {
 			if cmd.Ignore {
@@ -574,6 +1051,42 @@ func (m *Migration) WriteSQL(w io.Writer) error {
 			io.WriteString(w, "\n\n-- DELIMITER ;")
 		}
 	}
+	for _, cmd := range m.RenameTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(&cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.RenameColumnCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.RenameConstraintCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.RenameIndexCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
 	for _, cmd := range m.CreateTableCmds {
 		if cmd.Ignore {
 			continue
@@ -583,6 +1096,15 @@ func (m *Migration) WriteSQL(w io.Writer) error {
 			return err
 		}
 	}
+	for _, cmd := range m.RebuildTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
 	for _, cmd := range m.AlterTableCmds {
 		if cmd.Ignore {
 			continue
@@ -690,6 +1212,42 @@ func (m *Migration) WriteSQL(w io.Writer) error {
 			return err
 		}
 	}
+	for _, cmd := range m.DropCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropDomainCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropEnumCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = writeCmd(cmd, false)
+		if err != nil {
+			return err
+		}
+	}
 	for _, cmd := range m.DropExtensionCmds {
 		if cmd.Ignore {
 			continue
@@ -708,6 +1266,28 @@ func (m *Migration) Exec(db sq.DB) error {
 
 func (m *Migration) ExecContext(ctx context.Context, db sq.DB) error {
 	var err error
+	if m.Dialect == sq.DialectSQLite && m.hasRebuilds() {
+		// PRAGMA foreign_keys is a per connection setting, so it must be
+		// checked on the same connection that rebuilds the tables.
+		switch v := db.(type) {
+		case *sql.Conn, *sql.Tx:
+		case interface {
+			Conn(ctx context.Context) (*sql.Conn, error)
+		}:
+			conn, err := v.Conn(ctx)
+			if err != nil {
+				return fmt.Errorf("acquiring a connection to rebuild tables on: %w", err)
+			}
+			defer conn.Close()
+			db = conn
+		default:
+			return fmt.Errorf("rebuilding sqlite tables needs a *sql.DB, *sql.Conn or *sql.Tx to check PRAGMA foreign_keys on the connection it runs on, got %T", db)
+		}
+		err = checkForeignKeysOff(ctx, db)
+		if err != nil {
+			return err
+		}
+	}
 	execCmd := func(cmd Command) error {
 		query, args, _, err := sq.ToSQL(m.Dialect, cmd)
 		if err != nil {
@@ -737,6 +1317,87 @@ func (m *Migration) ExecContext(ctx context.Context, db sq.DB) error {
 			return err
 		}
 	}
+	for _, cmd := range m.CreateEnumCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AddEnumValueCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.CreateSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AlterSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.CreateDomainCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AlterDomainCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.CreateCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.AlterCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropReplacedFunctionCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
 	for _, cmd := range m.CreateFunctionCmds {
 		if cmd.Ignore {
 			continue
@@ -746,6 +1407,42 @@ func (m *Migration) ExecContext(ctx context.Context, db sq.DB) error {
 			return err
 		}
 	}
+	for _, cmd := range m.RenameTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(&cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.RenameColumnCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.RenameConstraintCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.RenameIndexCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
 	for _, cmd := range m.CreateTableCmds {
 		if cmd.Ignore {
 			continue
@@ -755,6 +1452,15 @@ func (m *Migration) ExecContext(ctx context.Context, db sq.DB) error {
 			return err
 		}
 	}
+	for _, cmd := range m.RebuildTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
 	for _, cmd := range m.AlterTableCmds {
 		if cmd.Ignore {
 			continue
@@ -854,6 +1560,42 @@ func (m *Migration) ExecContext(ctx context.Context, db sq.DB) error {
 			return err
 		}
 	}
+	for _, cmd := range m.DropCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropDomainCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropEnumCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
+	for _, cmd := range m.DropSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		err = execCmd(cmd)
+		if err != nil {
+			return err
+		}
+	}
 	for _, cmd := range m.DropExtensionCmds {
 		if cmd.Ignore {
 			continue
@@ -865,3 +1607,40 @@ func (m *Migration) ExecContext(ctx context.Context, db sq.DB) error {
 	}
 	return nil
 }
+
+// hasRebuilds reports whether the Migration rebuilds any tables.
+func (m *Migration) hasRebuilds() bool {
+	for _, cmd := range m.RebuildTableCmds {
+		if !cmd.Ignore {
+			return true
+		}
+	}
+	return false
+}
+
+// checkForeignKeysOff returns an error if sqlite's foreign keys are enforced,
+// since rebuilding a table would then fire the ON DELETE actions of the tables
+// referencing it. PRAGMA foreign_keys cannot be changed inside a transaction,
+// so it is not turned off here.
+func checkForeignKeysOff(ctx context.Context, db sq.DB) error {
+	rows, err := db.QueryContext(ctx, "PRAGMA foreign_keys")
+	if err != nil {
+		return fmt.Errorf("checking PRAGMA foreign_keys: %w", err)
+	}
+	defer rows.Close()
+	var foreignKeys bool
+	if rows.Next() {
+		err = rows.Scan(&foreignKeys)
+		if err != nil {
+			return fmt.Errorf("checking PRAGMA foreign_keys: %w", err)
+		}
+	}
+	err = rows.Close()
+	if err != nil {
+		return fmt.Errorf("checking PRAGMA foreign_keys: %w", err)
+	}
+	if foreignKeys {
+		return fmt.Errorf("refusing to rebuild tables with PRAGMA foreign_keys = ON: dropping the old table would fire the ON DELETE actions of the tables referencing it, turn foreign_keys off on a *sql.Conn (outside of any transaction) and pass that to Exec")
+	}
+	return nil
+}
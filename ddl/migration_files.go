@@ -0,0 +1,191 @@
+package ddl
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bokwoon95/sq"
+)
+
+// MigrationsTable is the name of the table that RunMigrations records applied
+// migration files in.
+const MigrationsTable = "sq_migrations"
+
+// ErrChecksumMismatch is returned by RunMigrations when a migration file that
+// has already been applied has been modified since.
+var ErrChecksumMismatch = errors.New("migration file has been modified after it was applied")
+
+var migrationNameRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// WriteFiles writes the migration into dir as a timestamped up/down file pair
+// e.g. 20210720153045_add_film_table.up.sql and
+// 20210720153045_add_film_table.down.sql. The down migration is usually
+// obtained from m.Reverse(). It may be nil, in which case the down file is
+// left empty.
+func (m *Migration) WriteFiles(dir, name string, down *Migration) (upFilename, downFilename string, err error) {
+	name = strings.Trim(migrationNameRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	prefix := time.Now().UTC().Format("20060102150405")
+	if name != "" {
+		prefix += "_" + name
+	}
+	upFilename = filepath.Join(dir, prefix+".up.sql")
+	downFilename = filepath.Join(dir, prefix+".down.sql")
+	err = writeMigrationFile(upFilename, m)
+	if err != nil {
+		return "", "", err
+	}
+	err = writeMigrationFile(downFilename, down)
+	if err != nil {
+		os.Remove(upFilename)
+		return "", "", err
+	}
+	return upFilename, downFilename, nil
+}
+
+func writeMigrationFile(filename string, m *Migration) error {
+	// O_EXCL so that an existing migration file is never overwritten.
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		err = m.WriteSQL(file)
+		if err != nil {
+			file.Close()
+			os.Remove(filename)
+			return fmt.Errorf("writing %s: %w", filename, err)
+		}
+	}
+	return file.Close()
+}
+
+func RunMigrations(dialect string, db sq.DB, fsys fs.FS) error {
+	return RunMigrationsContext(context.Background(), dialect, db, fsys)
+}
+
+// RunMigrationsContext applies the *.up.sql files in the root of fsys that
+// have not yet been applied, in filename order. Each applied file is recorded
+// in the sq_migrations table together with its checksum. If the checksum of
+// an already applied file no longer matches, no files are applied and an
+// error wrapping ErrChecksumMismatch is returned.
+//
+// Each file is executed as a single statement, so mysql DSNs need
+// multiStatements=true. If db is able to begin transactions, each file is
+// applied in its own transaction.
+func RunMigrationsContext(ctx context.Context, dialect string, db sq.DB, fsys fs.FS) error {
+	var timestampType string
+	switch dialect {
+	case sq.DialectSQLite, sq.DialectMySQL:
+		timestampType = "DATETIME"
+	case sq.DialectPostgres:
+		timestampType = "TIMESTAMPTZ"
+	default:
+		return fmt.Errorf("unsupported dialect: %s", dialect)
+	}
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+MigrationsTable+" ("+
+		"filename VARCHAR(255) NOT NULL PRIMARY KEY"+
+		", checksum VARCHAR(64) NOT NULL"+
+		", applied_at "+timestampType+" NOT NULL DEFAULT CURRENT_TIMESTAMP"+
+		")")
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", MigrationsTable, err)
+	}
+	rows, err := db.QueryContext(ctx, "SELECT filename, checksum FROM "+MigrationsTable)
+	if err != nil {
+		return fmt.Errorf("querying %s: %w", MigrationsTable, err)
+	}
+	defer rows.Close()
+	appliedChecksums := make(map[string]string)
+	for rows.Next() {
+		var filename, checksum string
+		err = rows.Scan(&filename, &checksum)
+		if err != nil {
+			return fmt.Errorf("scanning %s: %w", MigrationsTable, err)
+		}
+		appliedChecksums[filename] = checksum
+	}
+	err = rows.Close()
+	if err != nil {
+		return fmt.Errorf("querying %s: %w", MigrationsTable, err)
+	}
+	filenames, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(filenames)
+	type migrationFile struct {
+		filename string
+		contents string
+		checksum string
+	}
+	var pending []migrationFile
+	for _, filename := range filenames {
+		b, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(b)
+		checksum := hex.EncodeToString(sum[:])
+		appliedChecksum, ok := appliedChecksums[filename]
+		if !ok {
+			pending = append(pending, migrationFile{filename: filename, contents: string(b), checksum: checksum})
+			continue
+		}
+		if appliedChecksum != checksum {
+			return fmt.Errorf("%s: %w (applied checksum %s, current checksum %s)", filename, ErrChecksumMismatch, appliedChecksum, checksum)
+		}
+	}
+	insertQuery := "INSERT INTO " + MigrationsTable + " (filename, checksum) VALUES (?, ?)"
+	if dialect == sq.DialectPostgres {
+		insertQuery = "INSERT INTO " + MigrationsTable + " (filename, checksum) VALUES ($1, $2)"
+	}
+	for _, file := range pending {
+		err = applyMigrationFile(ctx, db, file.contents, insertQuery, file.filename, file.checksum)
+		if err != nil {
+			return fmt.Errorf("applying %s: %w", file.filename, err)
+		}
+	}
+	return nil
+}
+
+func applyMigrationFile(ctx context.Context, db sq.DB, contents, insertQuery, filename, checksum string) (err error) {
+	if txBeginner, ok := db.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+	}); ok {
+		var tx *sql.Tx
+		tx, err = txBeginner.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+			} else {
+				err = tx.Commit()
+			}
+		}()
+		db = tx
+	}
+	if strings.TrimSpace(contents) != "" {
+		_, err = db.ExecContext(ctx, contents)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = db.ExecContext(ctx, insertQuery, filename, checksum)
+	if err != nil {
+		return fmt.Errorf("recording migration in %s: %w", MigrationsTable, err)
+	}
+	return nil
+}
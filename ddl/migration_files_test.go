@@ -0,0 +1,147 @@
+package ddl
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_RunMigrations(t *testing.T) {
+	t.Run("WriteFiles", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectSQLite, WithTables(NEW_LANGUAGE(""), NEW_CATEGORY("")))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		up, err := Migrate(CreateMissing, DatabaseMetadata{}, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		down, err := Migrate(DropExtraneous, wantDBMetadata, DatabaseMetadata{Dialect: sq.DialectSQLite})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		upFilename, downFilename, err := up.WriteFiles(dir, "Add language & category", down)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if !regexp.MustCompile(`^\d{14}_add_language_category\.up\.sql$`).MatchString(filepath.Base(upFilename)) {
+			t.Errorf(testutil.Callers()+" unexpected up filename %s", upFilename)
+		}
+		if !regexp.MustCompile(`^\d{14}_add_language_category\.down\.sql$`).MatchString(filepath.Base(downFilename)) {
+			t.Errorf(testutil.Callers()+" unexpected down filename %s", downFilename)
+		}
+		db, err := sql.Open("sqlite3", filepath.Join(dir, "db.sqlite3"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		defer db.Close()
+		fsys := os.DirFS(dir)
+		// Running twice should only apply the migration once.
+		for i := 0; i < 2; i++ {
+			err = RunMigrations(sq.DialectSQLite, db, fsys)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+		}
+		gotDBMetadata, err := NewDatabaseMetadata(sq.DialectSQLite, WithDB(db, nil))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		for _, tableName := range []string{"language", "category", MigrationsTable} {
+			if gotDBMetadata.Schemas[0].CachedTablePosition(tableName) < 0 {
+				t.Errorf(testutil.Callers()+" table %s not found", tableName)
+			}
+		}
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM " + MigrationsTable).Scan(&count)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(count, 1); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("order and checksum", func(t *testing.T) {
+		t.Parallel()
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "db.sqlite3"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		defer db.Close()
+		fsys := fstest.MapFS{
+			"002_b.up.sql":   {Data: []byte("INSERT INTO a (id) VALUES (1);")},
+			"001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+			"001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		}
+		err = RunMigrations(sq.DialectSQLite, db, fsys)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		var filenames []string
+		rows, err := db.Query("SELECT filename FROM " + MigrationsTable + " ORDER BY filename")
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var filename string
+			err = rows.Scan(&filename)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			filenames = append(filenames, filename)
+		}
+		if diff := testutil.Diff(filenames, []string{"001_a.up.sql", "002_b.up.sql"}); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		fsys["001_a.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INT, name TEXT);")}
+		fsys["003_c.up.sql"] = &fstest.MapFile{Data: []byte("INSERT INTO a (id) VALUES (2);")}
+		err = RunMigrations(sq.DialectSQLite, db, fsys)
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Fatalf(testutil.Callers()+" expected ErrChecksumMismatch, got %v", err)
+		}
+		// 003_c.up.sql must not have been applied.
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM a").Scan(&count)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(count, 1); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("failed file is rolled back", func(t *testing.T) {
+		t.Parallel()
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "db.sqlite3"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		defer db.Close()
+		fsys := fstest.MapFS{
+			"001_a.up.sql": {Data: []byte("CREATE TABLE a (id INT); INSERT INTO nonexistent VALUES (1);")},
+		}
+		err = RunMigrations(sq.DialectSQLite, db, fsys)
+		if err == nil {
+			t.Fatal(testutil.Callers(), "expected error but got nil")
+		}
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM sqlite_schema WHERE name = 'a'").Scan(&count)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(count, 0); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+}
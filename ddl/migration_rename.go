@@ -0,0 +1,234 @@
+package ddl
+
+import (
+	"strings"
+
+	"github.com/bokwoon95/sq"
+)
+
+// renamedTablePosition returns the position of the table in gotSchema that
+// wantTable was renamed from, or -1 if there is none. A table is not
+// considered renamed if wantSchema still wants a table by its old name.
+func renamedTablePosition(gotSchema, wantSchema Schema, wantTable Table) int {
+	if wantTable.RenamedFrom == "" || wantTable.RenamedFrom == wantTable.TableName {
+		return -1
+	}
+	if n := wantSchema.CachedTablePosition(wantTable.RenamedFrom); n >= 0 {
+		return -1
+	}
+	return gotSchema.CachedTablePosition(wantTable.RenamedFrom)
+}
+
+// renamedColumnPosition returns the position of the column in gotTable that
+// wantColumn was renamed from, or -1 if there is none. A column is not
+// considered renamed if wantTable still wants a column by its old name.
+func renamedColumnPosition(gotTable, wantTable Table, wantColumn Column) int {
+	if wantColumn.RenamedFrom == "" || wantColumn.RenamedFrom == wantColumn.ColumnName {
+		return -1
+	}
+	if n := wantTable.CachedColumnPosition(wantColumn.RenamedFrom); n >= 0 {
+		return -1
+	}
+	return gotTable.CachedColumnPosition(wantColumn.RenamedFrom)
+}
+
+// renamedConstraintPosition returns the position of the constraint in
+// gotTable that is identical to wantConstraint except for its name, which
+// happens when the constraint name is derived from a renamed table or column.
+// Constraints that are already taken by another rename and constraints that
+// wantTable still wants by name are skipped. Returns -1 if there is no such
+// constraint, or if the dialect cannot rename it.
+func renamedConstraintPosition(dialect string, gotTable, wantTable Table, wantConstraint Constraint, columnRenames map[string]string, isTaken func(constraintName string) bool) int {
+	if dialect == sq.DialectMySQL && wantConstraint.ConstraintType != PRIMARY_KEY && wantConstraint.ConstraintType != UNIQUE {
+		// mysql cannot rename foreign keys or check constraints, they have
+		// to be dropped and added again.
+		return -1
+	}
+	for i, gotConstraint := range gotTable.Constraints {
+		if gotConstraint.Ignore || gotConstraint.ConstraintType != wantConstraint.ConstraintType {
+			continue
+		}
+		if n := wantTable.CachedConstraintPosition(gotConstraint.ConstraintName); n >= 0 || isTaken(gotConstraint.ConstraintName) {
+			continue
+		}
+		if !equalColumns(gotConstraint.Columns, wantConstraint.Columns, columnRenames) ||
+			!equalStrings(gotConstraint.Exprs, wantConstraint.Exprs) ||
+			!equalStrings(gotConstraint.ReferencesColumns, wantConstraint.ReferencesColumns) ||
+			gotConstraint.CheckExpr != wantConstraint.CheckExpr {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// renamedIndexPosition is the index counterpart of renamedConstraintPosition.
+func renamedIndexPosition(gotTable, wantTable Table, wantIndex Index, columnRenames map[string]string, isTaken func(indexName string) bool) int {
+	for i, gotIndex := range gotTable.Indexes {
+		if gotIndex.Ignore || gotIndex.IsUnique != wantIndex.IsUnique {
+			continue
+		}
+		if n := wantTable.CachedIndexPosition(gotIndex.IndexName); n >= 0 || isTaken(gotIndex.IndexName) {
+			continue
+		}
+		if !equalColumns(gotIndex.Columns, wantIndex.Columns, columnRenames) ||
+			!equalStrings(gotIndex.Exprs, wantIndex.Exprs) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// equalColumns reports whether gotColumns are the same as wantColumns after
+// applying columnRenames to gotColumns.
+func equalColumns(gotColumns, wantColumns []string, columnRenames map[string]string) bool {
+	if len(gotColumns) != len(wantColumns) {
+		return false
+	}
+	for i, columnName := range gotColumns {
+		if renameToName, ok := columnRenames[columnName]; ok {
+			columnName = renameToName
+		}
+		if columnName != wantColumns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(strings.TrimSpace(a[i]), strings.TrimSpace(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// rebuildTable returns the RebuildTableCommand that turns gotTable into
+// wantTable. It returns false if the rebuild would lose the data of a column
+// in gotTable that is not in wantTable, those are left to DropExtraneous.
+func rebuildTable(gotTable, wantTable Table, columnRenames map[string]string) (RebuildTableCommand, bool) {
+	rebuildTableCmd := RebuildTableCommand{Table: wantTable}
+	if wantTable.VirtualTable != "" {
+		return rebuildTableCmd, false
+	}
+	for _, gotColumn := range gotTable.Columns {
+		columnName := gotColumn.ColumnName
+		if renameToName, ok := columnRenames[columnName]; ok {
+			columnName = renameToName
+		}
+		n := wantTable.CachedColumnPosition(columnName)
+		if n < 0 || wantTable.Columns[n].Ignore {
+			return rebuildTableCmd, false
+		}
+		if gotColumn.GeneratedExpr != "" || wantTable.Columns[n].GeneratedExpr != "" {
+			// generated columns cannot be inserted into
+			continue
+		}
+		rebuildTableCmd.CopyColumns = append(rebuildTableCmd.CopyColumns, columnName)
+		rebuildTableCmd.CopyFromColumns = append(rebuildTableCmd.CopyFromColumns, gotColumn.ColumnName)
+	}
+	return rebuildTableCmd, true
+}
+
+// gotColumn looks up the current definition of a column that the migration
+// refers to by its new table and column name, following any renames back to
+// the old names.
+func (m *Migration) gotColumn(tableSchema, tableName, columnName string) (Column, bool) {
+	for _, cmd := range m.RenameColumnCmds {
+		if !cmd.Ignore && cmd.TableSchema == tableSchema && cmd.TableName == tableName && cmd.RenameToName == columnName {
+			columnName = cmd.ColumnName
+			break
+		}
+	}
+	for _, cmd := range m.RebuildTableCmds {
+		if cmd.Ignore || cmd.Table.TableSchema != tableSchema || cmd.Table.TableName != tableName {
+			continue
+		}
+		for i, copyColumn := range cmd.CopyColumns {
+			if copyColumn == columnName {
+				columnName = cmd.CopyFromColumns[i]
+				break
+			}
+		}
+	}
+	return m.gotDBMetadata.column(m.CurrentSchema, tableSchema, m.renamedTableName(tableSchema, tableName), columnName)
+}
+
+// renamedTableName returns the old name of a table renamed by the migration,
+// or tableName if it is not renamed.
+func (m *Migration) renamedTableName(tableSchema, tableName string) string {
+	for _, cmd := range m.RenameTableCmds {
+		if cmd.Ignore || len(cmd.RenameToNames) == 0 {
+			continue
+		}
+		if cmd.RenameToNames[0] == tableName && cmd.TableSchemas[0] == tableSchema {
+			return cmd.TableNames[0]
+		}
+	}
+	return tableName
+}
+
+// reverseRenames returns a copy of the DatabaseMetadata the migration was
+// built from, with RenamedFrom set on every table and column that the
+// migration renames. Migrating back to it renames them to their old names
+// instead of dropping and recreating them.
+func (m *Migration) reverseRenames() DatabaseMetadata {
+	dbm := m.gotDBMetadata
+	if len(m.RenameTableCmds) == 0 && len(m.RenameColumnCmds) == 0 && len(m.RebuildTableCmds) == 0 {
+		return dbm
+	}
+	dbm.Schemas = append([]Schema(nil), dbm.Schemas...)
+	// table returns a copy of the table that is safe to modify.
+	table := func(tableSchema, tableName string) *Table {
+		n1, n2 := dbm.tablePosition(m.CurrentSchema, tableSchema, tableName)
+		if n2 < 0 {
+			return nil
+		}
+		schema := &dbm.Schemas[n1]
+		schema.Tables = append([]Table(nil), schema.Tables...)
+		tbl := &schema.Tables[n2]
+		tbl.Columns = append([]Column(nil), tbl.Columns...)
+		return tbl
+	}
+	renameColumn := func(tableSchema, tableName, columnName, renameToName string) {
+		tbl := table(tableSchema, m.renamedTableName(tableSchema, tableName))
+		if tbl == nil {
+			return
+		}
+		if n := tbl.CachedColumnPosition(columnName); n >= 0 {
+			tbl.Columns[n].RenamedFrom = renameToName
+		}
+	}
+	for _, cmd := range m.RenameTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, tableName := range cmd.TableNames {
+			if tbl := table(cmd.TableSchemas[i], tableName); tbl != nil {
+				tbl.RenamedFrom = cmd.RenameToNames[i]
+			}
+		}
+	}
+	for _, cmd := range m.RenameColumnCmds {
+		if !cmd.Ignore {
+			renameColumn(cmd.TableSchema, cmd.TableName, cmd.ColumnName, cmd.RenameToName)
+		}
+	}
+	for _, cmd := range m.RebuildTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, columnName := range cmd.CopyFromColumns {
+			if columnName != cmd.CopyColumns[i] {
+				renameColumn(cmd.Table.TableSchema, cmd.Table.TableName, columnName, cmd.CopyColumns[i])
+			}
+		}
+	}
+	return dbm
+}
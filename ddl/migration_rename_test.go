@@ -0,0 +1,390 @@
+package ddl
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+type RENAME_AUTHOR struct {
+	sq.TableInfo
+	AUTHOR_ID sq.NumberField `ddl:"primarykey"`
+	NAME      sq.StringField `ddl:"index"`
+}
+
+type RENAME_WRITER struct {
+	sq.TableInfo `ddl:"renamedfrom=author"`
+	WRITER_ID    sq.NumberField `ddl:"primarykey renamedfrom=author_id"`
+	FULL_NAME    sq.StringField `ddl:"index renamedfrom=name"`
+}
+
+// RENAME_WRITER_T is RENAME_WRITER, but with the renames declared in the DDL
+// method.
+type RENAME_WRITER_T struct {
+	sq.TableInfo
+	WRITER_ID sq.NumberField `ddl:"primarykey"`
+	FULL_NAME sq.StringField `ddl:"index"`
+}
+
+func (tbl RENAME_WRITER_T) DDL(dialect string, t *T) {
+	t.RenamedFrom("author")
+	t.Column(tbl.WRITER_ID).RenamedFrom("author_id")
+	t.Column(tbl.FULL_NAME).RenamedFrom("name")
+}
+
+type REBUILD_V1 struct {
+	sq.TableInfo
+	BOOK_ID sq.NumberField `ddl:"primarykey"`
+	TITLE   sq.StringField `ddl:"type=VARCHAR(50) index"`
+	PAGES   sq.NumberField
+}
+
+type REBUILD_V2 struct {
+	sq.TableInfo
+	BOOK_ID sq.NumberField `ddl:"primarykey"`
+	NAME    sq.StringField `ddl:"notnull default='' index renamedfrom=title"`
+	PAGES   sq.NumberField
+}
+
+func NEW_RENAME_AUTHOR() RENAME_AUTHOR {
+	tbl := RENAME_AUTHOR{TableInfo: sq.TableInfo{TableName: "author"}}
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_RENAME_WRITER() RENAME_WRITER {
+	tbl := RENAME_WRITER{TableInfo: sq.TableInfo{TableName: "writer"}}
+	tbl.WRITER_ID = sq.NewNumberField("writer_id", tbl.TableInfo)
+	tbl.FULL_NAME = sq.NewStringField("full_name", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_RENAME_WRITER_T() RENAME_WRITER_T {
+	tbl := RENAME_WRITER_T{TableInfo: sq.TableInfo{TableName: "writer"}}
+	tbl.WRITER_ID = sq.NewNumberField("writer_id", tbl.TableInfo)
+	tbl.FULL_NAME = sq.NewStringField("full_name", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_REBUILD_V1() REBUILD_V1 {
+	tbl := REBUILD_V1{TableInfo: sq.TableInfo{TableName: "book"}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.PAGES = sq.NewNumberField("pages", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_REBUILD_V2() REBUILD_V2 {
+	tbl := REBUILD_V2{TableInfo: sq.TableInfo{TableName: "book"}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	tbl.PAGES = sq.NewNumberField("pages", tbl.TableInfo)
+	return tbl
+}
+
+func Test_MigrateRename(t *testing.T) {
+	type TT struct {
+		dialect        string
+		got, want      []sq.SchemaTable
+		wantSQL        string
+		wantReverseSQL string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotDBMetadata, err := NewDatabaseMetadata(tt.dialect, WithTables(tt.got...))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantDBMetadata, err := NewDatabaseMetadata(tt.dialect, WithTables(tt.want...))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		m, err := Migrate(CreateMissing|UpdateExisting|DropExtraneous, gotDBMetadata, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		reverse, _, err := m.Reverse()
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf.Reset()
+		err = reverse.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantReverseSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("postgres", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.got = []sq.SchemaTable{NEW_RENAME_AUTHOR()}
+		tt.want = []sq.SchemaTable{NEW_RENAME_WRITER()}
+		tt.wantSQL = "ALTER TABLE IF EXISTS author RENAME TO writer;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN author_id TO writer_id;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN name TO full_name;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME CONSTRAINT author_author_id_pkey TO writer_writer_id_pkey;" +
+			"\n" +
+			"\nALTER INDEX author_name_idx RENAME TO writer_full_name_idx;"
+		tt.wantReverseSQL = "ALTER TABLE IF EXISTS writer RENAME TO author;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN writer_id TO author_id;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN full_name TO name;" +
+			"\n" +
+			"\nALTER TABLE author RENAME CONSTRAINT writer_writer_id_pkey TO author_author_id_pkey;" +
+			"\n" +
+			"\nALTER INDEX writer_full_name_idx RENAME TO author_name_idx;"
+		assert(t, tt)
+	})
+
+	t.Run("postgres T methods", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.got = []sq.SchemaTable{NEW_RENAME_AUTHOR()}
+		tt.want = []sq.SchemaTable{NEW_RENAME_WRITER_T()}
+		tt.wantSQL = "ALTER TABLE IF EXISTS author RENAME TO writer;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN author_id TO writer_id;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN name TO full_name;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME CONSTRAINT author_author_id_pkey TO writer_writer_id_pkey;" +
+			"\n" +
+			"\nALTER INDEX author_name_idx RENAME TO writer_full_name_idx;"
+		tt.wantReverseSQL = "ALTER TABLE IF EXISTS writer RENAME TO author;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN writer_id TO author_id;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN full_name TO name;" +
+			"\n" +
+			"\nALTER TABLE author RENAME CONSTRAINT writer_writer_id_pkey TO author_author_id_pkey;" +
+			"\n" +
+			"\nALTER INDEX writer_full_name_idx RENAME TO author_name_idx;"
+		assert(t, tt)
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.got = []sq.SchemaTable{NEW_RENAME_AUTHOR()}
+		tt.want = []sq.SchemaTable{NEW_RENAME_WRITER()}
+		tt.wantSQL = "RENAME TABLE author TO writer;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN author_id TO writer_id;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN name TO full_name;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME INDEX author_name_idx TO writer_full_name_idx;"
+		tt.wantReverseSQL = "RENAME TABLE writer TO author;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN writer_id TO author_id;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN full_name TO name;" +
+			"\n" +
+			"\nALTER TABLE author RENAME INDEX writer_full_name_idx TO author_name_idx;"
+		assert(t, tt)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectSQLite
+		tt.got = []sq.SchemaTable{NEW_RENAME_AUTHOR()}
+		tt.want = []sq.SchemaTable{NEW_RENAME_WRITER()}
+		// sqlite cannot rename indexes, so they are recreated instead.
+		tt.wantSQL = "ALTER TABLE author RENAME TO writer;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN author_id TO writer_id;" +
+			"\n" +
+			"\nALTER TABLE writer RENAME COLUMN name TO full_name;" +
+			"\n" +
+			"\nCREATE INDEX IF NOT EXISTS writer_full_name_idx ON writer (full_name);" +
+			"\n" +
+			"\nDROP INDEX IF EXISTS author_name_idx;"
+		tt.wantReverseSQL = "ALTER TABLE writer RENAME TO author;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN writer_id TO author_id;" +
+			"\n" +
+			"\nALTER TABLE author RENAME COLUMN full_name TO name;" +
+			"\n" +
+			"\nCREATE INDEX IF NOT EXISTS author_name_idx ON author (name);" +
+			"\n" +
+			"\nDROP INDEX IF EXISTS writer_full_name_idx;"
+		assert(t, tt)
+	})
+}
+
+func Test_MigrateRenameSQLiteRebuild(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "db.sqlite3"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer db.Close()
+	err = AutoMigrate(sq.DialectSQLite, db, CreateMissing, WithTables(NEW_REBUILD_V1()))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	_, err = db.Exec("INSERT INTO book (book_id, title, pages) VALUES (1, 'the title', 100)")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	mode := CreateMissing | UpdateExisting | DropExtraneous
+	gotDBMetadata, err := NewDatabaseMetadata(sq.DialectSQLite, WithDB(db, nil))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	wantDBMetadata, err := NewDatabaseMetadata(sq.DialectSQLite, WithTables(NEW_REBUILD_V2()))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err := Migrate(mode, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	// The renamed column also becomes NOT NULL, which sqlite can only do by
+	// rebuilding the table.
+	if diff := testutil.Diff(len(m.RebuildTableCmds), 1); diff != "" {
+		t.Fatal(testutil.Callers(), diff)
+	}
+	err = m.Exec(db)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	var name string
+	var pages int
+	err = db.QueryRow("SELECT name, pages FROM book WHERE book_id = 1").Scan(&name, &pages)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(name, "the title"); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	if diff := testutil.Diff(pages, 100); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	// The database should now match REBUILD_V2 exactly, including the
+	// recreated index.
+	gotDBMetadata, err = NewDatabaseMetadata(sq.DialectSQLite, WithDB(db, nil))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err = Migrate(mode, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	buf := &bytes.Buffer{}
+	err = m.WriteSQL(buf)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(buf.String(), ""); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	_, err = db.Exec("INSERT INTO book (book_id, pages) VALUES (2, 200)")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	err = db.QueryRow("SELECT name FROM book WHERE book_id = 2").Scan(&name)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(name, ""); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+}
+
+func Test_MigrateRenameSQLiteRebuildForeignKeys(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "db.sqlite3")+"?_foreign_keys=1")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer db.Close()
+	err = AutoMigrate(sq.DialectSQLite, db, CreateMissing, WithTables(NEW_REBUILD_V1()))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	_, err = db.Exec("CREATE TABLE review (book_id INT REFERENCES book (book_id) ON DELETE CASCADE);" +
+		" INSERT INTO book (book_id, title, pages) VALUES (1, 'the title', 100);" +
+		" INSERT INTO review (book_id) VALUES (1);")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	gotDBMetadata, err := NewDatabaseMetadata(sq.DialectSQLite, WithDB(db, nil))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	wantDBMetadata, err := NewDatabaseMetadata(sq.DialectSQLite, WithTables(NEW_REBUILD_V2()))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err := Migrate(CreateMissing|UpdateExisting, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	err = MigrationPolicy{AllowLocking: true}.Check(m.Analyze())
+	if !errors.Is(err, ErrMigrationRefused) {
+		t.Errorf(testutil.Callers()+" expected ErrMigrationRefused, got %v", err)
+	}
+	err = m.Exec(db)
+	if err == nil || !strings.Contains(err.Error(), "PRAGMA foreign_keys = ON") {
+		t.Fatalf(testutil.Callers()+" expected a foreign_keys error, got %v", err)
+	}
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM review").Scan(&count)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(count, 1); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	// With foreign_keys turned off on the connection passed to Exec, the
+	// rebuild runs on that same connection and keeps the reviews.
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	err = m.ExecContext(ctx, conn)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM review").Scan(&count)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(count, 1); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+}
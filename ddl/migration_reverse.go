@@ -0,0 +1,155 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reverse returns the Migration that undoes m: objects created by m are
+// dropped, objects dropped by m are recreated from the metadata m was built
+// from, renamed objects get their old names back and altered columns are
+// restored to their previous definitions.
+//
+// Some effects of m cannot be undone, such as the data in dropped tables and
+// columns or values truncated by a column type change. These are described in
+// irreversible, and the caller should decide whether the reverse Migration is
+// good enough to ship as a rollback.
+//
+// Reverse only works on Migrations returned by Migrate.
+func (m *Migration) Reverse() (reverse *Migration, irreversible []string, err error) {
+	if m.mode == 0 {
+		return nil, nil, fmt.Errorf("Reverse: migration was not built by Migrate")
+	}
+	var mode MigrationMode
+	if m.mode&CreateMissing != 0 {
+		mode |= DropExtraneous
+	}
+	if m.mode&UpdateExisting != 0 {
+		mode |= UpdateExisting
+	}
+	if m.mode&DropExtraneous != 0 {
+		mode |= CreateMissing
+	}
+	mode |= m.mode & DropCascade
+	wantDBMetadata := m.reverseRenames()
+	if wantDBMetadata.Dialect == "" {
+		wantDBMetadata.Dialect = m.Dialect
+	}
+	reverse, err = Migrate(mode, m.wantDBMetadata, wantDBMetadata)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Reverse: %w", err)
+	}
+	return reverse, m.irreversibleChanges(), nil
+}
+
+// irreversibleChanges describes the effects of the migration that cannot be
+// undone by its reverse.
+func (m *Migration) irreversibleChanges() []string {
+	var changes []string
+	for _, cmd := range m.CreateSchemaCmds {
+		if cmd.Ignore {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("schema %s is created but will not be dropped", cmd.SchemaName))
+	}
+	for _, cmd := range m.AddEnumValueCmds {
+		if cmd.Ignore {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("enum %s gains the value '%s' which postgres cannot remove", qualifiedName(cmd.EnumSchema, cmd.EnumName), cmd.Value))
+	}
+	for _, cmd := range m.AlterTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for _, alterColumnCmd := range cmd.AlterColumnCmds {
+			if alterColumnCmd.Ignore {
+				continue
+			}
+			column := alterColumnCmd.Column
+			gotColumn, ok := m.gotColumn(cmd.TableSchema, cmd.TableName, column.ColumnName)
+			if !ok || column.ColumnType == "" || strings.EqualFold(gotColumn.ColumnType, column.ColumnType) {
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("column %s type changes from %s to %s, values may not convert back without loss",
+				qualifiedName(cmd.TableSchema, cmd.TableName, column.ColumnName), gotColumn.ColumnType, column.ColumnType))
+		}
+	}
+	for _, cmd := range m.DropTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, tableName := range cmd.TableNames {
+			changes = append(changes, fmt.Sprintf("table %s is dropped, its data cannot be restored", qualifiedName(cmd.TableSchemas[i], tableName)))
+		}
+	}
+	for _, cmd := range m.AlterTableDropCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for _, dropColumnCmd := range cmd.DropColumnCmds {
+			if dropColumnCmd.Ignore {
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("column %s is dropped, its data cannot be restored", qualifiedName(cmd.TableSchema, cmd.TableName, dropColumnCmd.ColumnName)))
+		}
+	}
+	for _, cmd := range m.AlterCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for _, attributeName := range cmd.DropAttributes {
+			changes = append(changes, fmt.Sprintf("attribute %s is dropped, its data cannot be restored", qualifiedName(cmd.TypeSchema, cmd.TypeName, attributeName)))
+		}
+	}
+	for _, cmd := range m.DropSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, sequenceName := range cmd.SequenceNames {
+			changes = append(changes, fmt.Sprintf("sequence %s is dropped, its current value cannot be restored", qualifiedName(cmd.SequenceSchemas[i], sequenceName)))
+		}
+	}
+	return changes
+}
+
+func (dbm *DatabaseMetadata) column(currentSchema, tableSchema, tableName, columnName string) (Column, bool) {
+	n1, n2 := dbm.tablePosition(currentSchema, tableSchema, tableName)
+	if n2 < 0 {
+		return Column{}, false
+	}
+	tbl := dbm.Schemas[n1].Tables[n2]
+	n3 := tbl.CachedColumnPosition(columnName)
+	if n3 < 0 {
+		return Column{}, false
+	}
+	return tbl.Columns[n3], true
+}
+
+// tablePosition returns the schema and table position of a table, or -1 for
+// both if the table does not exist. An empty tableSchema also matches the
+// currentSchema.
+func (dbm *DatabaseMetadata) tablePosition(currentSchema, tableSchema, tableName string) (schemaPosition, tablePosition int) {
+	n1 := dbm.CachedSchemaPosition(tableSchema)
+	if n1 < 0 && tableSchema == "" {
+		n1 = dbm.CachedSchemaPosition(currentSchema)
+	}
+	if n1 < 0 {
+		return -1, -1
+	}
+	n2 := dbm.Schemas[n1].CachedTablePosition(tableName)
+	if n2 < 0 {
+		return -1, -1
+	}
+	return n1, n2
+}
+
+func qualifiedName(names ...string) string {
+	var parts []string
+	for _, name := range names {
+		if name != "" {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, ".")
+}
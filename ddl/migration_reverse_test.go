@@ -0,0 +1,167 @@
+package ddl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+type REVERSE_AUTHOR struct {
+	sq.TableInfo
+	AUTHOR_ID sq.NumberField `ddl:"primarykey"`
+	NAME      sq.StringField `ddl:"notnull"`
+}
+
+type REVERSE_V1 struct {
+	sq.TableInfo
+	BOOK_ID  sq.NumberField `ddl:"primarykey"`
+	TITLE    sq.StringField `ddl:"type=VARCHAR(50)"`
+	SUBTITLE sq.StringField
+}
+
+type REVERSE_V2 struct {
+	sq.TableInfo
+	BOOK_ID sq.NumberField `ddl:"primarykey"`
+	TITLE   sq.StringField `ddl:"type=VARCHAR(255) notnull default=''"`
+	ISBN    sq.StringField `ddl:"unique"`
+}
+
+func NEW_REVERSE_AUTHOR() REVERSE_AUTHOR {
+	tbl := REVERSE_AUTHOR{TableInfo: sq.TableInfo{TableName: "author"}}
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_REVERSE_V1() REVERSE_V1 {
+	tbl := REVERSE_V1{TableInfo: sq.TableInfo{TableName: "book"}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.SUBTITLE = sq.NewStringField("subtitle", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_REVERSE_V2() REVERSE_V2 {
+	tbl := REVERSE_V2{TableInfo: sq.TableInfo{TableName: "book"}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.ISBN = sq.NewStringField("isbn", tbl.TableInfo)
+	return tbl
+}
+
+func Test_MigrationReverse(t *testing.T) {
+	const dialect = sq.DialectPostgres
+	type TT struct {
+		mode             MigrationMode
+		got, want        []sq.SchemaTable
+		wantSQL          string
+		wantReverseSQL   string
+		wantIrreversible []string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotDBMetadata, err := NewDatabaseMetadata(dialect, WithTables(tt.got...))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantDBMetadata, err := NewDatabaseMetadata(dialect, WithTables(tt.want...))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		m, err := Migrate(tt.mode, gotDBMetadata, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		reverse, irreversible, err := m.Reverse()
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf.Reset()
+		err = reverse.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantReverseSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(irreversible, tt.wantIrreversible); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("create and drop tables", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.mode = CreateMissing | DropExtraneous
+		tt.got = []sq.SchemaTable{NEW_REVERSE_AUTHOR()}
+		tt.want = []sq.SchemaTable{NEW_REVERSE_V1()}
+		tt.wantSQL = "CREATE TABLE IF NOT EXISTS book (" +
+			"\n    book_id INT" +
+			"\n    ,title VARCHAR(50)" +
+			"\n    ,subtitle TEXT" +
+			"\n" +
+			"\n    ,CONSTRAINT book_book_id_pkey PRIMARY KEY (book_id)" +
+			"\n);" +
+			"\n" +
+			"\nDROP TABLE IF EXISTS author;"
+		tt.wantReverseSQL = "CREATE TABLE IF NOT EXISTS author (" +
+			"\n    author_id INT" +
+			"\n    ,name TEXT NOT NULL" +
+			"\n" +
+			"\n    ,CONSTRAINT author_author_id_pkey PRIMARY KEY (author_id)" +
+			"\n);" +
+			"\n" +
+			"\nDROP TABLE IF EXISTS book;"
+		tt.wantIrreversible = []string{"table author is dropped, its data cannot be restored"}
+		assert(t, tt)
+	})
+
+	t.Run("add, alter and drop columns", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.mode = CreateMissing | UpdateExisting | DropExtraneous
+		tt.got = []sq.SchemaTable{NEW_REVERSE_V1()}
+		tt.want = []sq.SchemaTable{NEW_REVERSE_V2()}
+		tt.wantSQL = "ALTER TABLE IF EXISTS book" +
+			"\n    ADD COLUMN IF NOT EXISTS isbn TEXT" +
+			"\n    ,ALTER COLUMN title SET DATA TYPE VARCHAR(255)" +
+			"\n    ,ALTER COLUMN title SET NOT NULL" +
+			"\n    ,ALTER COLUMN title SET DEFAULT ''" +
+			"\n    ,ADD CONSTRAINT book_isbn_key UNIQUE (isbn);" +
+			"\n" +
+			"\nALTER TABLE book" +
+			"\n    DROP COLUMN IF EXISTS subtitle CASCADE;"
+		tt.wantReverseSQL = "ALTER TABLE IF EXISTS book" +
+			"\n    ADD COLUMN IF NOT EXISTS subtitle TEXT" +
+			"\n    ,ALTER COLUMN title SET DATA TYPE VARCHAR(50)" +
+			"\n    ,ALTER COLUMN title DROP NOT NULL" +
+			"\n    ,ALTER COLUMN title DROP DEFAULT;" +
+			"\n" +
+			"\nALTER TABLE book" +
+			"\n    DROP COLUMN IF EXISTS isbn CASCADE" +
+			"\n    ,DROP CONSTRAINT IF EXISTS book_isbn_key CASCADE;"
+		tt.wantIrreversible = []string{
+			"column book.title type changes from VARCHAR(50) to VARCHAR(255), values may not convert back without loss",
+			"column book.subtitle is dropped, its data cannot be restored",
+		}
+		assert(t, tt)
+	})
+
+	t.Run("not built by Migrate", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := (&Migration{Dialect: dialect}).Reverse()
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+}
@@ -0,0 +1,672 @@
+package ddl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bokwoon95/sq"
+)
+
+// ChangeSafety classifies how risky it is to run a change against a live
+// database.
+type ChangeSafety int
+
+const (
+	// Safe changes neither lose data nor block concurrent reads and writes
+	// for longer than a brief metadata lock.
+	Safe ChangeSafety = iota
+	// Locking changes keep data intact but scan, rewrite or copy the table
+	// while blocking writes (and possibly reads), fail outright if the table
+	// is not empty, or leave a routine missing while it is replaced.
+	Locking
+	// Destructive changes lose or may lose data, e.g. dropping a column or
+	// narrowing its type.
+	Destructive
+)
+
+func (s ChangeSafety) String() string {
+	switch s {
+	case Safe:
+		return "safe"
+	case Locking:
+		return "locking"
+	case Destructive:
+		return "destructive"
+	}
+	return "ChangeSafety(" + strconv.Itoa(int(s)) + ")"
+}
+
+// MigrationChange is a single change made by a Migration. Commands that make
+// several changes, such as an AlterTableCommand, are split into one
+// MigrationChange per subcommand.
+type MigrationChange struct {
+	Safety ChangeSafety
+	// Command is the command (or subcommand) that makes the change.
+	Command Command
+	// Description describes the change e.g. "DROP COLUMN film.title".
+	Description string
+	// Reason explains why the change is not Safe. It is empty for Safe
+	// changes.
+	Reason string
+}
+
+func (c MigrationChange) String() string {
+	if c.Reason == "" {
+		return c.Safety.String() + ": " + c.Description
+	}
+	return c.Safety.String() + ": " + c.Description + " (" + c.Reason + ")"
+}
+
+// ErrMigrationRefused is returned by MigrationPolicy.Check when a migration
+// contains changes that the policy does not allow.
+var ErrMigrationRefused = errors.New("migration refused by policy")
+
+// MigrationPolicy decides which changes a migration may make. The zero value
+// only allows Safe changes.
+type MigrationPolicy struct {
+	AllowLocking     bool
+	AllowDestructive bool
+	// Allow, if non-nil, is consulted for every change that would otherwise
+	// be refused. Returning true allows the change. It can be used to allow
+	// specific destructive changes (e.g. dropping one particular table)
+	// without allowing all of them.
+	Allow func(change MigrationChange) bool
+}
+
+// Check returns an error wrapping ErrMigrationRefused that lists every change
+// the policy does not allow, or nil if all changes are allowed.
+func (policy MigrationPolicy) Check(changes []MigrationChange) error {
+	var refused []string
+	for _, change := range changes {
+		switch change.Safety {
+		case Safe:
+			continue
+		case Locking:
+			if policy.AllowLocking {
+				continue
+			}
+		case Destructive:
+			if policy.AllowDestructive {
+				continue
+			}
+		}
+		if policy.Allow != nil && policy.Allow(change) {
+			continue
+		}
+		refused = append(refused, change.String())
+	}
+	if len(refused) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w:\n%s", ErrMigrationRefused, strings.Join(refused, "\n"))
+}
+
+// Analyze classifies every change made by the migration as Safe, Locking or
+// Destructive, in the order the changes would be executed. The rules depend on
+// the dialect e.g. CREATE INDEX without CONCURRENTLY is Locking on postgres
+// but Safe on mysql, which builds indexes online.
+func (m *Migration) Analyze() []MigrationChange {
+	a := migrationAnalyzer{
+		m:             m,
+		createdTables: make(map[[2]string]bool),
+	}
+	for _, cmd := range m.CreateTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		a.createdTables[a.tableKey(cmd.Table.TableSchema, cmd.Table.TableName)] = true
+	}
+	for _, cmd := range m.CreateSchemaCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE SCHEMA "+cmd.SchemaName, "")
+		}
+	}
+	for _, cmd := range m.CreateExtensionCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE EXTENSION "+cmd.Extension, "")
+		}
+	}
+	for _, cmd := range m.CreateEnumCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE TYPE "+qualifiedName(cmd.Enum.EnumSchema, cmd.Enum.EnumName)+" AS ENUM", "")
+		}
+	}
+	for _, cmd := range m.AddEnumValueCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "ALTER TYPE "+qualifiedName(cmd.EnumSchema, cmd.EnumName)+" ADD VALUE '"+cmd.Value+"'", "")
+		}
+	}
+	for _, cmd := range m.CreateSequenceCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE SEQUENCE "+qualifiedName(cmd.Sequence.SequenceSchema, cmd.Sequence.SequenceName), "")
+		}
+	}
+	for _, cmd := range m.AlterSequenceCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "ALTER SEQUENCE "+qualifiedName(cmd.Sequence.SequenceSchema, cmd.Sequence.SequenceName), "")
+		}
+	}
+	for _, cmd := range m.CreateDomainCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE DOMAIN "+qualifiedName(cmd.Domain.DomainSchema, cmd.Domain.DomainName), "")
+		}
+	}
+	for _, cmd := range m.AlterDomainCmds {
+		if !cmd.Ignore {
+			a.alterDomain(cmd)
+		}
+	}
+	for _, cmd := range m.CreateCompositeTypeCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE TYPE "+qualifiedName(cmd.CompositeType.TypeSchema, cmd.CompositeType.TypeName), "")
+		}
+	}
+	for _, cmd := range m.AlterCompositeTypeCmds {
+		if !cmd.Ignore {
+			a.alterCompositeType(cmd)
+		}
+	}
+	for _, cmd := range m.DropReplacedFunctionCmds {
+		if !cmd.Ignore {
+			a.add(Locking, cmd, "DROP "+routineKind(cmd.Function)+" "+qualifiedName(cmd.Function.FunctionSchema, cmd.Function.FunctionName), "it changed and is created again, calls to it fail until then")
+		}
+	}
+	for _, cmd := range m.CreateFunctionCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE "+routineKind(cmd.Function)+" "+qualifiedName(cmd.Function.FunctionSchema, cmd.Function.FunctionName), "")
+		}
+	}
+	for _, cmd := range m.RenameTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, tableName := range cmd.TableNames {
+			cmd := cmd
+			a.add(Safe, &cmd, "RENAME TABLE "+qualifiedName(cmd.TableSchemas[i], tableName)+" TO "+cmd.RenameToNames[i], "")
+		}
+	}
+	for _, cmd := range m.RenameColumnCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "RENAME COLUMN "+qualifiedName(cmd.TableSchema, cmd.TableName, cmd.ColumnName)+" TO "+cmd.RenameToName, "")
+		}
+	}
+	for _, cmd := range m.RenameConstraintCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "RENAME CONSTRAINT "+qualifiedName(cmd.TableSchema, cmd.TableName, cmd.ConstraintName)+" TO "+cmd.RenameToName, "")
+		}
+	}
+	for _, cmd := range m.RenameIndexCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "RENAME INDEX "+qualifiedName(cmd.TableSchema, cmd.IndexName)+" TO "+cmd.RenameToName, "")
+		}
+	}
+	for _, cmd := range m.CreateTableCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE TABLE "+qualifiedName(cmd.Table.TableSchema, cmd.Table.TableName), "")
+		}
+	}
+	for _, cmd := range m.RebuildTableCmds {
+		if !cmd.Ignore {
+			a.add(Destructive, cmd, "REBUILD TABLE "+qualifiedName(cmd.Table.TableSchema, cmd.Table.TableName), "dropping the old table fires the ON DELETE actions of the tables referencing it unless PRAGMA foreign_keys = OFF")
+		}
+	}
+	for _, cmd := range m.AlterTableCmds {
+		if !cmd.Ignore {
+			a.alterTable(cmd)
+		}
+	}
+	for _, cmd := range m.CreateViewCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE VIEW "+qualifiedName(cmd.View.ViewSchema, cmd.View.ViewName), "")
+		}
+	}
+	for _, cmd := range m.CreateIndexCmds {
+		if !cmd.Ignore {
+			a.createIndex(cmd)
+		}
+	}
+	for _, cmd := range m.CreateTriggerCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "CREATE TRIGGER "+qualifiedName(cmd.Trigger.TableSchema, cmd.Trigger.TableName, cmd.Trigger.TriggerName), "")
+		}
+	}
+	for _, cmd := range m.AddForeignKeyCmds {
+		if !cmd.Ignore {
+			a.alterTable(cmd)
+		}
+	}
+	for _, cmd := range m.DropViewCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, viewName := range cmd.ViewNames {
+			a.add(Safe, cmd, "DROP VIEW "+qualifiedName(cmd.ViewSchemas[i], viewName), "")
+		}
+	}
+	for _, cmd := range m.DropTableCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, tableName := range cmd.TableNames {
+			a.add(Destructive, cmd, "DROP TABLE "+qualifiedName(cmd.TableSchemas[i], tableName), "the table's data is lost")
+		}
+	}
+	for _, cmd := range m.DropTriggerCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "DROP TRIGGER "+qualifiedName(cmd.TableSchema, cmd.TableName, cmd.TriggerName), "")
+		}
+	}
+	for _, cmd := range m.DropIndexCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "DROP INDEX "+qualifiedName(cmd.TableSchema, cmd.IndexName), "")
+		}
+	}
+	for _, cmd := range m.AlterTableDropCmds {
+		if !cmd.Ignore {
+			a.alterTable(cmd)
+		}
+	}
+	for _, cmd := range m.DropFunctionCmds {
+		if !cmd.Ignore {
+			a.add(Safe, cmd, "DROP "+routineKind(cmd.Function)+" "+qualifiedName(cmd.Function.FunctionSchema, cmd.Function.FunctionName), "")
+		}
+	}
+	for _, cmd := range m.DropCompositeTypeCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, typeName := range cmd.TypeNames {
+			if cmd.DropCascade {
+				a.add(Destructive, cmd, "DROP TYPE "+qualifiedName(cmd.TypeSchemas[i], typeName)+" CASCADE", "columns of the type are dropped as well")
+			} else {
+				a.add(Safe, cmd, "DROP TYPE "+qualifiedName(cmd.TypeSchemas[i], typeName), "")
+			}
+		}
+	}
+	for _, cmd := range m.DropDomainCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, domainName := range cmd.DomainNames {
+			if cmd.DropCascade {
+				a.add(Destructive, cmd, "DROP DOMAIN "+qualifiedName(cmd.DomainSchemas[i], domainName)+" CASCADE", "columns of the domain are dropped as well")
+			} else {
+				a.add(Safe, cmd, "DROP DOMAIN "+qualifiedName(cmd.DomainSchemas[i], domainName), "")
+			}
+		}
+	}
+	for _, cmd := range m.DropEnumCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, enumName := range cmd.EnumNames {
+			if cmd.DropCascade {
+				a.add(Destructive, cmd, "DROP TYPE "+qualifiedName(cmd.EnumSchemas[i], enumName)+" CASCADE", "columns of the enum type are dropped as well")
+			} else {
+				a.add(Safe, cmd, "DROP TYPE "+qualifiedName(cmd.EnumSchemas[i], enumName), "")
+			}
+		}
+	}
+	for _, cmd := range m.DropSequenceCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for i, sequenceName := range cmd.SequenceNames {
+			a.add(Destructive, cmd, "DROP SEQUENCE "+qualifiedName(cmd.SequenceSchemas[i], sequenceName), "the sequence's current value is lost")
+		}
+	}
+	for _, cmd := range m.DropExtensionCmds {
+		if cmd.Ignore {
+			continue
+		}
+		for _, extension := range cmd.Extensions {
+			if cmd.DropCascade {
+				a.add(Destructive, cmd, "DROP EXTENSION "+extension+" CASCADE", "objects depending on the extension, such as columns of its types, are dropped as well")
+			} else {
+				a.add(Safe, cmd, "DROP EXTENSION "+extension, "")
+			}
+		}
+	}
+	return a.changes
+}
+
+type migrationAnalyzer struct {
+	m             *Migration
+	createdTables map[[2]string]bool
+	changes       []MigrationChange
+}
+
+func (a *migrationAnalyzer) add(safety ChangeSafety, cmd Command, description, reason string) {
+	a.changes = append(a.changes, MigrationChange{
+		Safety:      safety,
+		Command:     cmd,
+		Description: description,
+		Reason:      reason,
+	})
+}
+
+func (a *migrationAnalyzer) tableKey(tableSchema, tableName string) [2]string {
+	if tableSchema == "" {
+		tableSchema = a.m.CurrentSchema
+	}
+	return [2]string{tableSchema, tableName}
+}
+
+func (a *migrationAnalyzer) alterTable(cmd AlterTableCommand) {
+	dialect := a.m.Dialect
+	isNewTable := a.createdTables[a.tableKey(cmd.TableSchema, cmd.TableName)]
+	for _, addColumnCmd := range cmd.AddColumnCmds {
+		if addColumnCmd.Ignore {
+			continue
+		}
+		column := addColumnCmd.Column
+		description := "ADD COLUMN " + qualifiedName(cmd.TableSchema, cmd.TableName, column.ColumnName)
+		if isNewTable {
+			a.add(Safe, addColumnCmd, description, "")
+			continue
+		}
+		switch dialect {
+		case sq.DialectPostgres:
+			switch {
+			case column.IsNotNull && column.ColumnDefault == "" && column.Identity == "" && column.GeneratedExpr == "":
+				a.add(Locking, addColumnCmd, description, "adding a NOT NULL column without a default fails if the table has rows")
+			case column.Identity != "":
+				a.add(Locking, addColumnCmd, description, "adding an identity column rewrites the table under an ACCESS EXCLUSIVE lock")
+			case column.GeneratedExpr != "":
+				a.add(Locking, addColumnCmd, description, "adding a stored generated column rewrites the table under an ACCESS EXCLUSIVE lock")
+			case isVolatileDefault(column.ColumnDefault):
+				a.add(Locking, addColumnCmd, description, "adding a column with a volatile default rewrites the table under an ACCESS EXCLUSIVE lock")
+			default:
+				a.add(Safe, addColumnCmd, description, "")
+			}
+		case sq.DialectMySQL:
+			switch {
+			case column.IsAutoincrement:
+				a.add(Locking, addColumnCmd, description, "adding an AUTO_INCREMENT column copies the table")
+			case column.GeneratedExpr != "" && column.GeneratedExprStored:
+				a.add(Locking, addColumnCmd, description, "adding a STORED generated column copies the table")
+			default:
+				a.add(Safe, addColumnCmd, description, "")
+			}
+		default:
+			a.add(Safe, addColumnCmd, description, "")
+		}
+	}
+	for _, alterColumnCmd := range cmd.AlterColumnCmds {
+		// SQLite cannot alter a column so the change is skipped, only a
+		// RebuildTableCommand actually changes the column.
+		if alterColumnCmd.Ignore || dialect == sq.DialectSQLite {
+			continue
+		}
+		a.alterColumn(cmd, alterColumnCmd, isNewTable)
+	}
+	for _, dropColumnCmd := range cmd.DropColumnCmds {
+		if dropColumnCmd.Ignore {
+			continue
+		}
+		description := "DROP COLUMN " + qualifiedName(cmd.TableSchema, cmd.TableName, dropColumnCmd.ColumnName)
+		reason := "the column's data is lost"
+		if dialect == sq.DialectSQLite {
+			reason += " and sqlite rewrites the whole table"
+		}
+		a.add(Destructive, dropColumnCmd, description, reason)
+	}
+	for _, addConstraintCmd := range cmd.AddConstraintCmds {
+		if addConstraintCmd.Ignore {
+			continue
+		}
+		constraint := addConstraintCmd.Constraint
+		description := "ADD CONSTRAINT " + qualifiedName(cmd.TableSchema, cmd.TableName, constraint.ConstraintName)
+		if isNewTable {
+			a.add(Safe, addConstraintCmd, description, "")
+			continue
+		}
+		switch dialect {
+		case sq.DialectPostgres:
+			switch constraint.ConstraintType {
+			case FOREIGN_KEY, CHECK:
+				if addConstraintCmd.IsNotValid {
+					a.add(Safe, addConstraintCmd, description, "")
+				} else {
+					a.add(Locking, addConstraintCmd, description, "validating the constraint scans the whole table while blocking writes, consider NOT VALID")
+				}
+			case PRIMARY_KEY, UNIQUE:
+				if addConstraintCmd.IndexName != "" {
+					a.add(Safe, addConstraintCmd, description, "")
+				} else {
+					a.add(Locking, addConstraintCmd, description, "building the constraint's index blocks writes, consider USING INDEX with an index built CONCURRENTLY")
+				}
+			default:
+				a.add(Locking, addConstraintCmd, description, "building the constraint's index blocks writes")
+			}
+		case sq.DialectMySQL:
+			switch constraint.ConstraintType {
+			case FOREIGN_KEY:
+				a.add(Locking, addConstraintCmd, description, "adding a foreign key with foreign_key_checks enabled copies the table")
+			case CHECK:
+				a.add(Locking, addConstraintCmd, description, "adding a CHECK constraint copies the table")
+			case PRIMARY_KEY:
+				a.add(Locking, addConstraintCmd, description, "adding a primary key rebuilds the table")
+			default:
+				a.add(Safe, addConstraintCmd, description, "")
+			}
+		default:
+			a.add(Safe, addConstraintCmd, description, "")
+		}
+	}
+	for _, alterConstraintCmd := range cmd.AlterConstraintCmds {
+		if !alterConstraintCmd.Ignore {
+			a.add(Safe, alterConstraintCmd, "ALTER CONSTRAINT "+qualifiedName(cmd.TableSchema, cmd.TableName, alterConstraintCmd.ConstraintName), "")
+		}
+	}
+	for _, dropConstraintCmd := range cmd.DropConstraintCmds {
+		if !dropConstraintCmd.Ignore {
+			a.add(Safe, dropConstraintCmd, "DROP CONSTRAINT "+qualifiedName(cmd.TableSchema, cmd.TableName, dropConstraintCmd.ConstraintName), "")
+		}
+	}
+	for _, createIndexCmd := range cmd.CreateIndexCmds {
+		if !createIndexCmd.Ignore {
+			a.createIndex(createIndexCmd)
+		}
+	}
+	for _, dropIndexCmd := range cmd.DropIndexCmds {
+		if !dropIndexCmd.Ignore {
+			a.add(Safe, dropIndexCmd, "DROP INDEX "+qualifiedName(cmd.TableSchema, dropIndexCmd.IndexName), "")
+		}
+	}
+}
+
+func (a *migrationAnalyzer) alterColumn(cmd AlterTableCommand, alterColumnCmd AlterColumnCommand, isNewTable bool) {
+	dialect := a.m.Dialect
+	column := alterColumnCmd.Column
+	description := "ALTER COLUMN " + qualifiedName(cmd.TableSchema, cmd.TableName, column.ColumnName)
+	if isNewTable {
+		a.add(Safe, alterColumnCmd, description, "")
+		return
+	}
+	gotColumn, ok := a.m.gotColumn(cmd.TableSchema, cmd.TableName, column.ColumnName)
+	typeChanged := column.ColumnType != "" && (!ok || !strings.EqualFold(gotColumn.ColumnType, column.ColumnType))
+	if typeChanged && ok && isWideningVarchar(dialect, gotColumn.ColumnType, column.ColumnType) {
+		typeChanged = false
+	}
+	// A type change that is not known to widen the column may truncate or
+	// reject the existing values (mysql in non-strict mode truncates them
+	// silently).
+	typeNarrowed := typeChanged && (!ok || !isWideningType(gotColumn.ColumnType, column.ColumnType))
+	// mysql's MODIFY COLUMN restates the whole column definition, so
+	// IsNotNull is only a change if the column was previously nullable.
+	setNotNull := column.IsNotNull && !alterColumnCmd.DropNotNull && (dialect != sq.DialectMySQL || (ok && !gotColumn.IsNotNull))
+	switch dialect {
+	case sq.DialectPostgres:
+		switch {
+		case typeNarrowed:
+			a.add(Destructive, alterColumnCmd, description, "the existing values may not fit the new type and are truncated or rejected")
+		case typeChanged:
+			a.add(Locking, alterColumnCmd, description, "changing the column type rewrites the table under an ACCESS EXCLUSIVE lock")
+		case setNotNull:
+			a.add(Locking, alterColumnCmd, description, "SET NOT NULL scans the whole table under an ACCESS EXCLUSIVE lock")
+		default:
+			a.add(Safe, alterColumnCmd, description, "")
+		}
+	case sq.DialectMySQL:
+		switch {
+		case typeNarrowed:
+			a.add(Destructive, alterColumnCmd, description, "the existing values may not fit the new type and are truncated or rejected")
+		case typeChanged:
+			a.add(Locking, alterColumnCmd, description, "changing the column type copies the table (ALGORITHM=COPY)")
+		case setNotNull:
+			a.add(Locking, alterColumnCmd, description, "making the column NOT NULL rebuilds the table")
+		default:
+			a.add(Safe, alterColumnCmd, description, "")
+		}
+	default:
+		a.add(Locking, alterColumnCmd, description, dialect+" can only change a column by rebuilding the table")
+	}
+}
+
+func (a *migrationAnalyzer) createIndex(cmd CreateIndexCommand) {
+	index := cmd.Index
+	description := "CREATE INDEX " + qualifiedName(index.TableSchema, index.TableName, index.IndexName)
+	if a.createdTables[a.tableKey(index.TableSchema, index.TableName)] {
+		a.add(Safe, cmd, description, "")
+		return
+	}
+	switch a.m.Dialect {
+	case sq.DialectPostgres:
+		if cmd.CreateConcurrently {
+			a.add(Safe, cmd, description, "")
+		} else {
+			a.add(Locking, cmd, description, "CREATE INDEX without CONCURRENTLY blocks writes to the table until the index is built")
+		}
+	case sq.DialectSQLite:
+		a.add(Locking, cmd, description, "sqlite blocks writes to the database until the index is built")
+	default:
+		a.add(Safe, cmd, description, "")
+	}
+}
+
+func (a *migrationAnalyzer) alterDomain(cmd AlterDomainCommand) {
+	description := "ALTER DOMAIN " + qualifiedName(cmd.DomainSchema, cmd.DomainName)
+	if cmd.SetNotNull || len(cmd.AddChecks) > 0 {
+		a.add(Locking, cmd, description, "postgres checks every column of the domain type, and fails if any value violates the constraint")
+		return
+	}
+	a.add(Safe, cmd, description, "")
+}
+
+func (a *migrationAnalyzer) alterCompositeType(cmd AlterCompositeTypeCommand) {
+	description := "ALTER TYPE " + qualifiedName(cmd.TypeSchema, cmd.TypeName)
+	if len(cmd.DropAttributes) > 0 {
+		a.add(Destructive, cmd, description, "the data of the dropped attributes is lost: "+strings.Join(cmd.DropAttributes, ", "))
+		return
+	}
+	a.add(Safe, cmd, description, "")
+}
+
+var volatileFunctionRegexp = regexp.MustCompile(`(?i)\b(random|gen_random_uuid|uuid_generate_v[14]|clock_timestamp|timeofday|nextval)\s*\(`)
+
+// isVolatileDefault reports whether a postgres column default calls a volatile
+// function. Postgres 11+ adds columns with non-volatile defaults without
+// rewriting the table.
+func isVolatileDefault(columnDefault string) bool {
+	return volatileFunctionRegexp.MatchString(columnDefault)
+}
+
+var varcharRegexp = regexp.MustCompile(`(?i)^(?:VARCHAR|CHARACTER VARYING)\s*\(\s*(\d+)\s*\)$`)
+
+// isWideningVarchar reports whether changing a column from gotType to wantType
+// only increases the VARCHAR length limit, which neither postgres nor mysql
+// need to rewrite the table for. mysql only does this in place when the length
+// prefix stays the same size (both under or both over 256 bytes).
+func isWideningVarchar(dialect, gotType, wantType string) bool {
+	gotMatch := varcharRegexp.FindStringSubmatch(strings.TrimSpace(gotType))
+	if gotMatch == nil {
+		return false
+	}
+	gotLength, _ := strconv.Atoi(gotMatch[1])
+	if dialect == sq.DialectPostgres && (strings.EqualFold(wantType, "TEXT") || strings.EqualFold(wantType, "VARCHAR")) {
+		return true
+	}
+	wantMatch := varcharRegexp.FindStringSubmatch(strings.TrimSpace(wantType))
+	if wantMatch == nil {
+		return false
+	}
+	wantLength, _ := strconv.Atoi(wantMatch[1])
+	if wantLength < gotLength {
+		return false
+	}
+	if dialect == sq.DialectMySQL {
+		return (gotLength < 256) == (wantLength < 256)
+	}
+	return true
+}
+
+var (
+	integerTypeSizes = map[string]int{
+		"TINYINT": 1, "SMALLINT": 2, "INT2": 2, "MEDIUMINT": 3,
+		"INT": 4, "INTEGER": 4, "INT4": 4, "BIGINT": 8, "INT8": 8,
+	}
+	floatTypeSizes = map[string]int{
+		"REAL": 4, "FLOAT4": 4, "DOUBLE": 8, "DOUBLE PRECISION": 8, "FLOAT8": 8,
+	}
+	numericRegexp = regexp.MustCompile(`(?i)^(?:NUMERIC|DECIMAL)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?$`)
+)
+
+// isWideningType reports whether every value of gotType is known to fit into
+// wantType without loss: a larger integer or floating point type, a longer
+// VARCHAR or a NUMERIC with at least as many integer and fractional digits.
+func isWideningType(gotType, wantType string) bool {
+	gotType = strings.ToUpper(strings.Join(strings.Fields(gotType), " "))
+	wantType = strings.ToUpper(strings.Join(strings.Fields(wantType), " "))
+	if gotSize, ok := integerTypeSizes[gotType]; ok {
+		if wantSize, ok := integerTypeSizes[wantType]; ok {
+			return wantSize >= gotSize
+		}
+		return false
+	}
+	if gotSize, ok := floatTypeSizes[gotType]; ok {
+		wantSize, ok := floatTypeSizes[wantType]
+		return ok && wantSize >= gotSize
+	}
+	if gotMatch := varcharRegexp.FindStringSubmatch(gotType); gotMatch != nil {
+		if wantType == "TEXT" || wantType == "VARCHAR" || wantType == "CHARACTER VARYING" {
+			return true
+		}
+		wantMatch := varcharRegexp.FindStringSubmatch(wantType)
+		if wantMatch == nil {
+			return false
+		}
+		gotLength, _ := strconv.Atoi(gotMatch[1])
+		wantLength, _ := strconv.Atoi(wantMatch[1])
+		return wantLength >= gotLength
+	}
+	if gotMatch := numericRegexp.FindStringSubmatch(gotType); gotMatch != nil {
+		wantMatch := numericRegexp.FindStringSubmatch(wantType)
+		if wantMatch == nil {
+			return false
+		}
+		if wantMatch[1] == "" {
+			// an unconstrained NUMERIC holds any value
+			return true
+		}
+		if gotMatch[1] == "" {
+			return false
+		}
+		gotPrecision, _ := strconv.Atoi(gotMatch[1])
+		gotScale, _ := strconv.Atoi(gotMatch[2])
+		wantPrecision, _ := strconv.Atoi(wantMatch[1])
+		wantScale, _ := strconv.Atoi(wantMatch[2])
+		return wantScale >= gotScale && wantPrecision-wantScale >= gotPrecision-gotScale
+	}
+	return false
+}
+
+func routineKind(fun Function) string {
+	if fun.IsProcedure {
+		return "PROCEDURE"
+	}
+	return "FUNCTION"
+}
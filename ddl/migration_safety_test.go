@@ -0,0 +1,338 @@
+package ddl
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+type SAFETY_AUTHOR struct {
+	sq.TableInfo
+	AUTHOR_ID sq.NumberField `ddl:"primarykey"`
+	NAME      sq.StringField
+}
+
+type SAFETY_V1 struct {
+	sq.TableInfo
+	BOOK_ID  sq.NumberField `ddl:"primarykey"`
+	TITLE    sq.StringField `ddl:"type=VARCHAR(50)"`
+	SUBTITLE sq.StringField
+	PAGES    sq.NumberField
+}
+
+type SAFETY_V2 struct {
+	sq.TableInfo
+	BOOK_ID   sq.NumberField `ddl:"primarykey"`
+	TITLE     sq.StringField `ddl:"type=VARCHAR(255) notnull"`
+	PAGES     sq.NumberField `ddl:"type=BIGINT"`
+	ISBN      sq.StringField `ddl:"notnull"`
+	AUTHOR_ID sq.NumberField `ddl:"references={author.author_id} index"`
+}
+
+type SAFETY_V3 struct {
+	sq.TableInfo
+	BOOK_ID sq.NumberField `ddl:"primarykey"`
+	TITLE   sq.StringField `ddl:"type=VARCHAR(50)"`
+	PAGES   sq.NumberField `ddl:"index"`
+}
+
+type SAFETY_TYPES_WIDE struct {
+	sq.TableInfo
+	PRICE sq.NumberField `ddl:"type=NUMERIC(10,2)"`
+	NAME  sq.StringField `ddl:"type=VARCHAR(255)"`
+	TOTAL sq.NumberField `ddl:"type=BIGINT"`
+	RATIO sq.NumberField `ddl:"type={DOUBLE PRECISION}"`
+}
+
+type SAFETY_TYPES_NARROW struct {
+	sq.TableInfo
+	PRICE sq.NumberField `ddl:"type=NUMERIC(5,2)"`
+	NAME  sq.StringField `ddl:"type=VARCHAR(50)"`
+	TOTAL sq.NumberField `ddl:"type=INT"`
+	RATIO sq.NumberField `ddl:"type=REAL"`
+}
+
+func NEW_SAFETY_AUTHOR() SAFETY_AUTHOR {
+	tbl := SAFETY_AUTHOR{TableInfo: sq.TableInfo{TableName: "author"}}
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_SAFETY_V1() SAFETY_V1 {
+	tbl := SAFETY_V1{TableInfo: sq.TableInfo{TableName: "book"}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.SUBTITLE = sq.NewStringField("subtitle", tbl.TableInfo)
+	tbl.PAGES = sq.NewNumberField("pages", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_SAFETY_V2() SAFETY_V2 {
+	tbl := SAFETY_V2{TableInfo: sq.TableInfo{TableName: "book"}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.PAGES = sq.NewNumberField("pages", tbl.TableInfo)
+	tbl.ISBN = sq.NewStringField("isbn", tbl.TableInfo)
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_SAFETY_V3() SAFETY_V3 {
+	tbl := SAFETY_V3{TableInfo: sq.TableInfo{TableName: "book"}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.PAGES = sq.NewNumberField("pages", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_SAFETY_TYPES_WIDE() SAFETY_TYPES_WIDE {
+	tbl := SAFETY_TYPES_WIDE{TableInfo: sq.TableInfo{TableName: "item"}}
+	tbl.PRICE = sq.NewNumberField("price", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	tbl.TOTAL = sq.NewNumberField("total", tbl.TableInfo)
+	tbl.RATIO = sq.NewNumberField("ratio", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_SAFETY_TYPES_NARROW() SAFETY_TYPES_NARROW {
+	tbl := SAFETY_TYPES_NARROW{TableInfo: sq.TableInfo{TableName: "item"}}
+	tbl.PRICE = sq.NewNumberField("price", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	tbl.TOTAL = sq.NewNumberField("total", tbl.TableInfo)
+	tbl.RATIO = sq.NewNumberField("ratio", tbl.TableInfo)
+	return tbl
+}
+
+func Test_MigrationAnalyze(t *testing.T) {
+	type TT struct {
+		dialect     string
+		got, want   []sq.SchemaTable
+		wantChanges []string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotDBMetadata, err := NewDatabaseMetadata(tt.dialect, WithTables(tt.got...))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantDBMetadata, err := NewDatabaseMetadata(tt.dialect, WithTables(tt.want...))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		m, err := Migrate(CreateMissing|UpdateExisting|DropExtraneous, gotDBMetadata, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		var changes []string
+		for _, change := range m.Analyze() {
+			changes = append(changes, change.String())
+		}
+		if diff := testutil.Diff(changes, tt.wantChanges); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("postgres", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.got = []sq.SchemaTable{NEW_SAFETY_V1()}
+		tt.want = []sq.SchemaTable{NEW_SAFETY_AUTHOR(), NEW_SAFETY_V2()}
+		tt.wantChanges = []string{
+			"safe: CREATE TABLE author",
+			"locking: ADD COLUMN book.isbn (adding a NOT NULL column without a default fails if the table has rows)",
+			"safe: ADD COLUMN book.author_id",
+			"locking: ALTER COLUMN book.title (SET NOT NULL scans the whole table under an ACCESS EXCLUSIVE lock)",
+			"locking: ALTER COLUMN book.pages (changing the column type rewrites the table under an ACCESS EXCLUSIVE lock)",
+			"locking: CREATE INDEX book.book_author_id_idx (CREATE INDEX without CONCURRENTLY blocks writes to the table until the index is built)",
+			"locking: ADD CONSTRAINT book.book_author_id_fkey (validating the constraint scans the whole table while blocking writes, consider NOT VALID)",
+			"destructive: DROP COLUMN book.subtitle (the column's data is lost)",
+		}
+		assert(t, tt)
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.got = []sq.SchemaTable{NEW_SAFETY_V1()}
+		tt.want = []sq.SchemaTable{NEW_SAFETY_AUTHOR(), NEW_SAFETY_V2()}
+		tt.wantChanges = []string{
+			"safe: CREATE TABLE author",
+			"safe: ADD COLUMN book.isbn",
+			"safe: ADD COLUMN book.author_id",
+			"locking: ALTER COLUMN book.title (making the column NOT NULL rebuilds the table)",
+			"locking: ALTER COLUMN book.pages (changing the column type copies the table (ALGORITHM=COPY))",
+			"safe: CREATE INDEX book.book_author_id_idx",
+			"locking: ADD CONSTRAINT book.book_author_id_fkey (adding a foreign key with foreign_key_checks enabled copies the table)",
+			"destructive: DROP COLUMN book.subtitle (the column's data is lost)",
+		}
+		assert(t, tt)
+	})
+
+	t.Run("postgres narrowing types", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.got = []sq.SchemaTable{NEW_SAFETY_TYPES_WIDE()}
+		tt.want = []sq.SchemaTable{NEW_SAFETY_TYPES_NARROW()}
+		tt.wantChanges = []string{
+			"destructive: ALTER COLUMN item.price (the existing values may not fit the new type and are truncated or rejected)",
+			"destructive: ALTER COLUMN item.name (the existing values may not fit the new type and are truncated or rejected)",
+			"destructive: ALTER COLUMN item.total (the existing values may not fit the new type and are truncated or rejected)",
+			"destructive: ALTER COLUMN item.ratio (the existing values may not fit the new type and are truncated or rejected)",
+		}
+		assert(t, tt)
+	})
+
+	t.Run("postgres widening types", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.got = []sq.SchemaTable{NEW_SAFETY_TYPES_NARROW()}
+		tt.want = []sq.SchemaTable{NEW_SAFETY_TYPES_WIDE()}
+		tt.wantChanges = []string{
+			"locking: ALTER COLUMN item.price (changing the column type rewrites the table under an ACCESS EXCLUSIVE lock)",
+			"safe: ALTER COLUMN item.name",
+			"locking: ALTER COLUMN item.total (changing the column type rewrites the table under an ACCESS EXCLUSIVE lock)",
+			"locking: ALTER COLUMN item.ratio (changing the column type rewrites the table under an ACCESS EXCLUSIVE lock)",
+		}
+		assert(t, tt)
+	})
+
+	t.Run("mysql narrowing types", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.got = []sq.SchemaTable{NEW_SAFETY_TYPES_WIDE()}
+		tt.want = []sq.SchemaTable{NEW_SAFETY_TYPES_NARROW()}
+		tt.wantChanges = []string{
+			"destructive: ALTER COLUMN item.price (the existing values may not fit the new type and are truncated or rejected)",
+			"destructive: ALTER COLUMN item.name (the existing values may not fit the new type and are truncated or rejected)",
+			"destructive: ALTER COLUMN item.total (the existing values may not fit the new type and are truncated or rejected)",
+			"destructive: ALTER COLUMN item.ratio (the existing values may not fit the new type and are truncated or rejected)",
+		}
+		assert(t, tt)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectSQLite
+		tt.got = []sq.SchemaTable{NEW_SAFETY_V1()}
+		tt.want = []sq.SchemaTable{NEW_SAFETY_AUTHOR(), NEW_SAFETY_V2()}
+		tt.wantChanges = []string{
+			"safe: CREATE TABLE author",
+			"safe: ADD COLUMN book.isbn",
+			"safe: ADD COLUMN book.author_id",
+			"locking: CREATE INDEX book.book_author_id_idx (sqlite blocks writes to the database until the index is built)",
+			"destructive: DROP COLUMN book.subtitle (the column's data is lost and sqlite rewrites the whole table)",
+		}
+		assert(t, tt)
+	})
+
+	t.Run("sqlite alter column is skipped", func(t *testing.T) {
+		t.Parallel()
+		m := Migration{
+			Dialect: sq.DialectSQLite,
+			AlterTableCmds: []AlterTableCommand{{
+				TableName: "book",
+				AlterColumnCmds: []AlterColumnCommand{{
+					Column: Column{TableName: "book", ColumnName: "title", ColumnType: "INT"},
+				}},
+			}},
+		}
+		if changes := m.Analyze(); len(changes) > 0 {
+			t.Errorf(testutil.Callers()+" expected no changes, got %v", changes)
+		}
+	})
+}
+
+func Test_AutoMigratePolicy(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "db.sqlite3"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer db.Close()
+	err = AutoMigratePolicy(sq.DialectSQLite, db, CreateMissing, MigrationPolicy{}, WithTables(NEW_SAFETY_V1()))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	_, err = db.Exec("INSERT INTO book (book_id, title, subtitle) VALUES (1, 'title', 'subtitle')")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	mode := CreateMissing | UpdateExisting | DropExtraneous
+	// Dropping book.subtitle is destructive and creating the book.pages index
+	// is locking, so the default policy must refuse the migration.
+	err = AutoMigratePolicy(sq.DialectSQLite, db, mode, MigrationPolicy{}, WithTables(NEW_SAFETY_V3()))
+	if !errors.Is(err, ErrMigrationRefused) {
+		t.Fatalf(testutil.Callers()+" expected ErrMigrationRefused, got %v", err)
+	}
+	var subtitle string
+	err = db.QueryRow("SELECT subtitle FROM book WHERE book_id = 1").Scan(&subtitle)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(subtitle, "subtitle"); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+	// Allowing locking changes is not enough, the drop has to be allowed
+	// explicitly.
+	policy := MigrationPolicy{AllowLocking: true}
+	err = AutoMigratePolicy(sq.DialectSQLite, db, mode, policy, WithTables(NEW_SAFETY_V3()))
+	if !errors.Is(err, ErrMigrationRefused) {
+		t.Fatalf(testutil.Callers()+" expected ErrMigrationRefused, got %v", err)
+	}
+	policy.Allow = func(change MigrationChange) bool {
+		cmd, ok := change.Command.(DropColumnCommand)
+		return ok && cmd.ColumnName == "subtitle"
+	}
+	err = AutoMigratePolicy(sq.DialectSQLite, db, mode, policy, WithTables(NEW_SAFETY_V3()))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	gotDBMetadata, err := NewDatabaseMetadata(sq.DialectSQLite, WithDB(db, nil))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if _, ok := gotDBMetadata.column("", "", "book", "subtitle"); ok {
+		t.Error(testutil.Callers(), " book.subtitle was not dropped")
+	}
+}
+
+func Test_isWideningType(t *testing.T) {
+	tests := []struct {
+		gotType, wantType string
+		want              bool
+	}{
+		{"INT", "BIGINT", true},
+		{"BIGINT", "INT", false},
+		{"SMALLINT", "INTEGER", true},
+		{"INT", "TEXT", false},
+		{"REAL", "DOUBLE PRECISION", true},
+		{"DOUBLE PRECISION", "REAL", false},
+		{"VARCHAR(50)", "VARCHAR(255)", true},
+		{"VARCHAR(255)", "VARCHAR(50)", false},
+		{"CHARACTER VARYING(50)", "TEXT", true},
+		{"NUMERIC(5,2)", "NUMERIC(10,2)", true},
+		{"NUMERIC(5,2)", "NUMERIC(6,3)", true},
+		{"NUMERIC(10,2)", "NUMERIC(5,2)", false},
+		{"NUMERIC(5,2)", "NUMERIC(5,1)", false},
+		{"NUMERIC(5,2)", "NUMERIC", true},
+		{"NUMERIC", "NUMERIC(10,2)", false},
+		{"TIMESTAMP", "DATE", false},
+		{"TEXT", "JSONB", false},
+	}
+	for _, tt := range tests {
+		if got := isWideningType(tt.gotType, tt.wantType); got != tt.want {
+			t.Errorf(testutil.Callers()+" isWideningType(%q, %q): got %v, want %v", tt.gotType, tt.wantType, got, tt.want)
+		}
+	}
+}
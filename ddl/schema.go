@@ -3,20 +3,29 @@ package ddl
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/bokwoon95/sq"
 )
 
 type Schema struct {
-	SchemaName    string     `json:",omitempty"`
-	Tables        []Table    `json:",omitempty"`
-	Views         []View     `json:",omitempty"`
-	Functions     []Function `json:",omitempty"`
-	Comment       string     `json:",omitempty"`
-	Ignore        bool       `json:",omitempty"`
-	tableCache    map[string]int
-	viewCache     map[string]int
-	functionCache map[string][]int
+	SchemaName         string          `json:",omitempty"`
+	Tables             []Table         `json:",omitempty"`
+	Views              []View          `json:",omitempty"`
+	Functions          []Function      `json:",omitempty"`
+	Enums              []Enum          `json:",omitempty"`
+	Sequences          []Sequence      `json:",omitempty"`
+	Domains            []Domain        `json:",omitempty"`
+	CompositeTypes     []CompositeType `json:",omitempty"`
+	Comment            string          `json:",omitempty"`
+	Ignore             bool            `json:",omitempty"`
+	tableCache         map[string]int
+	viewCache          map[string]int
+	functionCache      map[string][]int
+	enumCache          map[string]int
+	sequenceCache      map[string]int
+	domainCache        map[string]int
+	compositeTypeCache map[string]int
 }
 
 func (s *Schema) CachedTablePosition(tableName string) (tablePosition int) {
@@ -154,6 +163,201 @@ func (s *Schema) RefreshFunctionCache() {
 	}
 }
 
+func (s *Schema) CachedEnumPosition(enumName string) (enumPosition int) {
+	if enumName == "" {
+		return -1
+	}
+	enumPosition, ok := s.enumCache[enumName]
+	if !ok {
+		return -1
+	}
+	if enumPosition < 0 || enumPosition >= len(s.Enums) {
+		delete(s.enumCache, enumName)
+		return -1
+	}
+	enum := s.Enums[enumPosition]
+	if enum.EnumName != enumName || enum.Ignore {
+		delete(s.enumCache, enumName)
+		return -1
+	}
+	return enumPosition
+}
+
+func (s *Schema) AppendEnum(enum Enum) (enumPosition int) {
+	s.Enums = append(s.Enums, enum)
+	if s.enumCache == nil {
+		s.enumCache = make(map[string]int)
+	}
+	enumPosition = len(s.Enums) - 1
+	s.enumCache[enum.EnumName] = enumPosition
+	return enumPosition
+}
+
+// loadEnum adds enum to the schema. An enum that is already in the schema must
+// have the same labels, since two Go types cannot share one postgres type.
+func (s *Schema) loadEnum(enum Enum) error {
+	n := s.CachedEnumPosition(enum.EnumName)
+	if n < 0 {
+		s.AppendEnum(enum)
+		return nil
+	}
+	if !equalLabels(s.Enums[n].EnumLabels, enum.EnumLabels) {
+		return fmt.Errorf("enum %s declared with different labels: (%s) and (%s)", enum.EnumName,
+			strings.Join(s.Enums[n].EnumLabels, ", "), strings.Join(enum.EnumLabels, ", "))
+	}
+	return nil
+}
+
+func equalLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Schema) RefreshEnumCache() {
+	if s.enumCache == nil && len(s.Enums) > 0 {
+		s.enumCache = make(map[string]int)
+	}
+	for n, enum := range s.Enums {
+		if enum.Ignore {
+			continue
+		}
+		s.enumCache[enum.EnumName] = n
+	}
+}
+
+func (s *Schema) CachedSequencePosition(sequenceName string) (sequencePosition int) {
+	if sequenceName == "" {
+		return -1
+	}
+	sequencePosition, ok := s.sequenceCache[sequenceName]
+	if !ok {
+		return -1
+	}
+	if sequencePosition < 0 || sequencePosition >= len(s.Sequences) {
+		delete(s.sequenceCache, sequenceName)
+		return -1
+	}
+	sequence := s.Sequences[sequencePosition]
+	if sequence.SequenceName != sequenceName || sequence.Ignore {
+		delete(s.sequenceCache, sequenceName)
+		return -1
+	}
+	return sequencePosition
+}
+
+func (s *Schema) AppendSequence(sequence Sequence) (sequencePosition int) {
+	s.Sequences = append(s.Sequences, sequence)
+	if s.sequenceCache == nil {
+		s.sequenceCache = make(map[string]int)
+	}
+	sequencePosition = len(s.Sequences) - 1
+	s.sequenceCache[sequence.SequenceName] = sequencePosition
+	return sequencePosition
+}
+
+func (s *Schema) RefreshSequenceCache() {
+	if s.sequenceCache == nil && len(s.Sequences) > 0 {
+		s.sequenceCache = make(map[string]int)
+	}
+	for n, sequence := range s.Sequences {
+		if sequence.Ignore {
+			continue
+		}
+		s.sequenceCache[sequence.SequenceName] = n
+	}
+}
+
+func (s *Schema) CachedDomainPosition(domainName string) (domainPosition int) {
+	if domainName == "" {
+		return -1
+	}
+	domainPosition, ok := s.domainCache[domainName]
+	if !ok {
+		return -1
+	}
+	if domainPosition < 0 || domainPosition >= len(s.Domains) {
+		delete(s.domainCache, domainName)
+		return -1
+	}
+	domain := s.Domains[domainPosition]
+	if domain.DomainName != domainName || domain.Ignore {
+		delete(s.domainCache, domainName)
+		return -1
+	}
+	return domainPosition
+}
+
+func (s *Schema) AppendDomain(domain Domain) (domainPosition int) {
+	s.Domains = append(s.Domains, domain)
+	if s.domainCache == nil {
+		s.domainCache = make(map[string]int)
+	}
+	domainPosition = len(s.Domains) - 1
+	s.domainCache[domain.DomainName] = domainPosition
+	return domainPosition
+}
+
+func (s *Schema) RefreshDomainCache() {
+	if s.domainCache == nil && len(s.Domains) > 0 {
+		s.domainCache = make(map[string]int)
+	}
+	for n, domain := range s.Domains {
+		if domain.Ignore {
+			continue
+		}
+		s.domainCache[domain.DomainName] = n
+	}
+}
+
+func (s *Schema) CachedCompositeTypePosition(compositeTypeName string) (compositeTypePosition int) {
+	if compositeTypeName == "" {
+		return -1
+	}
+	compositeTypePosition, ok := s.compositeTypeCache[compositeTypeName]
+	if !ok {
+		return -1
+	}
+	if compositeTypePosition < 0 || compositeTypePosition >= len(s.CompositeTypes) {
+		delete(s.compositeTypeCache, compositeTypeName)
+		return -1
+	}
+	compositeType := s.CompositeTypes[compositeTypePosition]
+	if compositeType.TypeName != compositeTypeName || compositeType.Ignore {
+		delete(s.compositeTypeCache, compositeTypeName)
+		return -1
+	}
+	return compositeTypePosition
+}
+
+func (s *Schema) AppendCompositeType(compositeType CompositeType) (compositeTypePosition int) {
+	s.CompositeTypes = append(s.CompositeTypes, compositeType)
+	if s.compositeTypeCache == nil {
+		s.compositeTypeCache = make(map[string]int)
+	}
+	compositeTypePosition = len(s.CompositeTypes) - 1
+	s.compositeTypeCache[compositeType.TypeName] = compositeTypePosition
+	return compositeTypePosition
+}
+
+func (s *Schema) RefreshCompositeTypeCache() {
+	if s.compositeTypeCache == nil && len(s.CompositeTypes) > 0 {
+		s.compositeTypeCache = make(map[string]int)
+	}
+	for n, compositeType := range s.CompositeTypes {
+		if compositeType.Ignore {
+			continue
+		}
+		s.compositeTypeCache[compositeType.TypeName] = n
+	}
+}
+
 type CreateSchemaCommand struct {
 	CreateIfNotExists bool
 	SchemaName        string
@@ -0,0 +1,148 @@
+package ddl
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/bokwoon95/sq"
+)
+
+// Sequence is a standalone postgres sequence. Sequences owned by a column
+// (SERIAL and identity columns) belong to their table and are not introspected.
+// Zero values are left to the postgres defaults.
+type Sequence struct {
+	SequenceSchema string `json:",omitempty"`
+	SequenceName   string `json:",omitempty"`
+	DataType       string `json:",omitempty"`
+	StartValue     int64  `json:",omitempty"`
+	Increment      int64  `json:",omitempty"`
+	MinValue       int64  `json:",omitempty"`
+	MaxValue       int64  `json:",omitempty"`
+	Cycle          bool   `json:",omitempty"`
+	Ignore         bool   `json:",omitempty"`
+}
+
+func writeSequenceOptions(buf *bytes.Buffer, sequence Sequence, isCreate bool) {
+	if sequence.DataType != "" {
+		buf.WriteString(" AS " + sequence.DataType)
+	}
+	if sequence.Increment != 0 {
+		buf.WriteString(" INCREMENT BY " + strconv.FormatInt(sequence.Increment, 10))
+	}
+	if sequence.MinValue != 0 {
+		buf.WriteString(" MINVALUE " + strconv.FormatInt(sequence.MinValue, 10))
+	}
+	if sequence.MaxValue != 0 {
+		buf.WriteString(" MAXVALUE " + strconv.FormatInt(sequence.MaxValue, 10))
+	}
+	if isCreate && sequence.StartValue != 0 {
+		buf.WriteString(" START WITH " + strconv.FormatInt(sequence.StartValue, 10))
+	}
+	if sequence.Cycle {
+		buf.WriteString(" CYCLE")
+	} else if !isCreate {
+		buf.WriteString(" NO CYCLE")
+	}
+}
+
+type CreateSequenceCommand struct {
+	CreateIfNotExists bool
+	Sequence          Sequence
+	Ignore            bool
+}
+
+func (cmd CreateSequenceCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=sequences", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("CREATE SEQUENCE ")
+	if cmd.CreateIfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	writeQualifiedName(dialect, buf, cmd.Sequence.SequenceSchema, cmd.Sequence.SequenceName)
+	writeSequenceOptions(buf, cmd.Sequence, true)
+	return nil
+}
+
+// AlterSequenceCommand sets the options of an existing sequence to those of
+// Sequence. The StartValue is not changed since it only matters when the
+// sequence is created.
+type AlterSequenceCommand struct {
+	AlterIfExists bool
+	Sequence      Sequence
+	Ignore        bool
+}
+
+func (cmd AlterSequenceCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=sequences", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("ALTER SEQUENCE ")
+	if cmd.AlterIfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	writeQualifiedName(dialect, buf, cmd.Sequence.SequenceSchema, cmd.Sequence.SequenceName)
+	writeSequenceOptions(buf, cmd.Sequence, false)
+	return nil
+}
+
+type DropSequenceCommand struct {
+	DropIfExists    bool
+	SequenceSchemas []string
+	SequenceNames   []string
+	DropCascade     bool
+	Ignore          bool
+}
+
+func (cmd DropSequenceCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectPostgres {
+		return fmt.Errorf("%w dialect=%s feature=sequences", ErrUnsupportedFeature, dialect)
+	}
+	buf.WriteString("DROP SEQUENCE ")
+	if cmd.DropIfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	for i, sequenceName := range cmd.SequenceNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeQualifiedName(dialect, buf, cmd.SequenceSchemas[i], sequenceName)
+	}
+	if cmd.DropCascade {
+		buf.WriteString(" CASCADE")
+	}
+	return nil
+}
+
+// diffSequence returns the AlterSequenceCommand that turns gotSequence into
+// wantSequence. Options left at their zero value in wantSequence are not
+// compared.
+func diffSequence(gotSequence, wantSequence Sequence) (alterSequenceCmd AlterSequenceCommand, isDifferent bool) {
+	alterSequenceCmd.Sequence = wantSequence
+	if wantSequence.DataType != "" && !sameDataType(sq.DialectPostgres, gotSequence.DataType, wantSequence.DataType) {
+		isDifferent = true
+	}
+	if wantSequence.Increment != 0 && gotSequence.Increment != wantSequence.Increment {
+		isDifferent = true
+	}
+	if wantSequence.MinValue != 0 && gotSequence.MinValue != wantSequence.MinValue {
+		isDifferent = true
+	}
+	if wantSequence.MaxValue != 0 && gotSequence.MaxValue != wantSequence.MaxValue {
+		isDifferent = true
+	}
+	if gotSequence.Cycle != wantSequence.Cycle {
+		isDifferent = true
+	}
+	return alterSequenceCmd, isDifferent
+}
@@ -0,0 +1,290 @@
+package ddl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+type INVOICE struct {
+	sq.TableInfo
+	INVOICE_ID sq.NumberField `ddl:"primarykey"`
+	INVOICE_NO sq.NumberField `ddl:"type=BIGINT notnull"`
+	AMOUNT     sq.NumberField `ddl:"type=positive_amount"`
+	BILL_TO    sq.CustomField `ddl:"type=mailing_address"`
+}
+
+func (tbl INVOICE) DDL(dialect string, t *T) {
+	t.Column(tbl.INVOICE_NO).Default("nextval('invoice_no_seq')")
+}
+
+func NEW_INVOICE() INVOICE {
+	tbl := INVOICE{TableInfo: sq.TableInfo{TableName: "invoice"}}
+	tbl.INVOICE_ID = sq.NewNumberField("invoice_id", tbl.TableInfo)
+	tbl.INVOICE_NO = sq.NewNumberField("invoice_no", tbl.TableInfo)
+	tbl.AMOUNT = sq.NewNumberField("amount", tbl.TableInfo)
+	tbl.BILL_TO = sq.NewCustomField("bill_to", tbl.TableInfo)
+	return tbl
+}
+
+var (
+	invoiceNoSeq = Sequence{
+		SequenceName: "invoice_no_seq",
+		DataType:     "BIGINT",
+		StartValue:   1000,
+		Increment:    1,
+	}
+	positiveAmount = Domain{
+		DomainName:     "positive_amount",
+		UnderlyingType: "NUMERIC(12,2)",
+		IsNotNull:      true,
+		Checks:         []DomainCheck{{CheckExpr: "VALUE > 0"}},
+	}
+	mailingAddress = CompositeType{
+		TypeName: "mailing_address",
+		Attributes: []CompositeTypeAttribute{
+			{AttributeName: "street", AttributeType: "TEXT"},
+			{AttributeName: "city", AttributeType: "TEXT"},
+			{AttributeName: "postal_code", AttributeType: "VARCHAR(10)"},
+		},
+	}
+)
+
+func Test_SequenceCommands(t *testing.T) {
+	type TT struct {
+		dialect string
+		item    Command
+		wantSQL string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		buf := &bytes.Buffer{}
+		err := tt.item.AppendSQL(tt.dialect, buf, nil, nil, nil)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.item = CreateSequenceCommand{
+			CreateIfNotExists: true,
+			Sequence:          Sequence{SequenceSchema: "billing", SequenceName: "invoice_no_seq", DataType: "BIGINT", StartValue: 1000, Increment: 10, MaxValue: 999999, Cycle: true},
+		}
+		tt.wantSQL = "CREATE SEQUENCE IF NOT EXISTS billing.invoice_no_seq AS BIGINT INCREMENT BY 10 MAXVALUE 999999 START WITH 1000 CYCLE"
+		assert(t, tt)
+	})
+
+	t.Run("alter", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.item = AlterSequenceCommand{
+			AlterIfExists: true,
+			Sequence:      Sequence{SequenceName: "invoice_no_seq", StartValue: 1000, Increment: 5},
+		}
+		tt.wantSQL = "ALTER SEQUENCE IF EXISTS invoice_no_seq INCREMENT BY 5 NO CYCLE"
+		assert(t, tt)
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.item = DropSequenceCommand{
+			DropIfExists:    true,
+			SequenceSchemas: []string{"", "billing"},
+			SequenceNames:   []string{"invoice_no_seq", "receipt_no_seq"},
+			DropCascade:     true,
+		}
+		tt.wantSQL = "DROP SEQUENCE IF EXISTS invoice_no_seq, billing.receipt_no_seq CASCADE"
+		assert(t, tt)
+	})
+
+	t.Run("mysql unsupported", func(t *testing.T) {
+		t.Parallel()
+		err := CreateSequenceCommand{Sequence: invoiceNoSeq}.AppendSQL(sq.DialectMySQL, &bytes.Buffer{}, nil, nil, nil)
+		if !errors.Is(err, ErrUnsupportedFeature) {
+			t.Errorf(testutil.Callers()+" expected ErrUnsupportedFeature, got %v", err)
+		}
+	})
+}
+
+func Test_diffSequence(t *testing.T) {
+	gotSequence := Sequence{SequenceName: "invoice_no_seq", DataType: "BIGINT", StartValue: 1000, Increment: 1, MinValue: 1, MaxValue: 9223372036854775807}
+	if _, isDifferent := diffSequence(gotSequence, invoiceNoSeq); isDifferent {
+		t.Error(testutil.Callers(), "expected options left at their zero value to be ignored")
+	}
+	wantSequence := invoiceNoSeq
+	wantSequence.Increment = 2
+	alterSequenceCmd, isDifferent := diffSequence(gotSequence, wantSequence)
+	if !isDifferent {
+		t.Fatal(testutil.Callers(), "expected the increment to be changed")
+	}
+	if diff := testutil.Diff(alterSequenceCmd, AlterSequenceCommand{Sequence: wantSequence}); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+}
+
+func Test_MigratePostgresTypes(t *testing.T) {
+	type TT struct {
+		gotDBMetadata DatabaseMetadata
+		mode          MigrationMode
+		wantSQL       string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres,
+			WithSequences(invoiceNoSeq),
+			WithDomains(positiveAmount),
+			WithCompositeTypes(mailingAddress),
+			WithTables(NEW_INVOICE()),
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		tt.gotDBMetadata.Dialect = sq.DialectPostgres
+		m, err := Migrate(tt.mode, tt.gotDBMetadata, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("create before tables", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.mode = CreateMissing
+		tt.wantSQL = "CREATE SEQUENCE IF NOT EXISTS invoice_no_seq AS BIGINT INCREMENT BY 1 START WITH 1000;" +
+			"\n\nCREATE DOMAIN positive_amount AS NUMERIC(12,2) NOT NULL CONSTRAINT positive_amount_check CHECK (VALUE > 0);" +
+			"\n\nCREATE TYPE mailing_address AS (street TEXT, city TEXT, postal_code VARCHAR(10));" +
+			"\n\nCREATE TABLE IF NOT EXISTS invoice (" +
+			"\n    invoice_id INT" +
+			"\n    ,invoice_no BIGINT NOT NULL DEFAULT nextval('invoice_no_seq')" +
+			"\n    ,amount positive_amount" +
+			"\n    ,bill_to mailing_address" +
+			"\n" +
+			"\n    ,CONSTRAINT invoice_invoice_id_pkey PRIMARY KEY (invoice_id)" +
+			"\n);"
+		assert(t, tt)
+	})
+
+	t.Run("update existing", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.mode = CreateMissing | UpdateExisting | DropExtraneous
+		gotDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(NEW_INVOICE()))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		gotDBMetadata.Schemas[0].AppendSequence(Sequence{SequenceName: "invoice_no_seq", DataType: "BIGINT", StartValue: 1, Increment: 2, MinValue: 1, MaxValue: 9223372036854775807})
+		gotDBMetadata.Schemas[0].AppendDomain(Domain{
+			DomainName:     "positive_amount",
+			UnderlyingType: "NUMERIC(12,2)",
+			DomainDefault:  "0",
+			Checks:         []DomainCheck{{ConstraintName: "amount_nonzero", CheckExpr: "VALUE <> 0"}},
+		})
+		gotDBMetadata.Schemas[0].AppendCompositeType(CompositeType{
+			TypeName: "mailing_address",
+			Attributes: []CompositeTypeAttribute{
+				{AttributeName: "street", AttributeType: "TEXT"},
+				{AttributeName: "city", AttributeType: "VARCHAR(50)"},
+				{AttributeName: "country", AttributeType: "TEXT"},
+			},
+		})
+		tt.gotDBMetadata = gotDBMetadata
+		tt.wantSQL = "ALTER SEQUENCE invoice_no_seq AS BIGINT INCREMENT BY 1 NO CYCLE;" +
+			"\n\nALTER DOMAIN positive_amount DROP CONSTRAINT IF EXISTS amount_nonzero;" +
+			"\n\nALTER DOMAIN positive_amount DROP DEFAULT;" +
+			"\n\nALTER DOMAIN positive_amount SET NOT NULL;" +
+			"\n\nALTER DOMAIN positive_amount ADD CONSTRAINT positive_amount_check CHECK (VALUE > 0);" +
+			"\n\nALTER TYPE mailing_address" +
+			"\n    ADD ATTRIBUTE postal_code VARCHAR(10)" +
+			"\n    ,ALTER ATTRIBUTE city SET DATA TYPE TEXT" +
+			"\n    ,DROP ATTRIBUTE IF EXISTS country;"
+		assert(t, tt)
+	})
+
+	t.Run("drop after tables", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.mode = DropExtraneous | DropCascade
+		gotDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres,
+			WithTables(NEW_INVOICE()),
+			WithSequences(invoiceNoSeq, Sequence{SequenceName: "receipt_no_seq"}),
+			WithDomains(positiveAmount, Domain{DomainName: "email", UnderlyingType: "TEXT"}),
+			WithCompositeTypes(mailingAddress, CompositeType{TypeName: "point3d", Attributes: []CompositeTypeAttribute{{AttributeName: "x", AttributeType: "FLOAT8"}}}),
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		gotDBMetadata.Schemas[0].AppendTable(Table{TableName: "receipt"})
+		tt.gotDBMetadata = gotDBMetadata
+		tt.wantSQL = "DROP TABLE IF EXISTS receipt CASCADE;" +
+			"\n\nDROP TYPE IF EXISTS point3d CASCADE;" +
+			"\n\nDROP DOMAIN IF EXISTS email CASCADE;" +
+			"\n\nDROP SEQUENCE IF EXISTS receipt_no_seq CASCADE;"
+		assert(t, tt)
+	})
+}
+
+func Test_MigratePostgresTypeAliases(t *testing.T) {
+	// the types in gotDBMetadata are spelled the way format_type reports them
+	gotDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres,
+		WithSequences(Sequence{SequenceName: "counter_seq", DataType: "BIGINT", Increment: 1}),
+		WithDomains(Domain{DomainName: "price", UnderlyingType: "NUMERIC(12,2)"}),
+		WithCompositeTypes(CompositeType{
+			TypeName: "addr",
+			Attributes: []CompositeTypeAttribute{
+				{AttributeName: "street", AttributeType: "CHARACTER VARYING(255)"},
+				{AttributeName: "n", AttributeType: "INTEGER"},
+				{AttributeName: "updated_at", AttributeType: "TIMESTAMP(3) WITH TIME ZONE"},
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres,
+		WithSequences(Sequence{SequenceName: "counter_seq", DataType: "int8", Increment: 1}),
+		WithDomains(Domain{DomainName: "price", UnderlyingType: "DECIMAL(12, 2)"}),
+		WithCompositeTypes(CompositeType{
+			TypeName: "addr",
+			Attributes: []CompositeTypeAttribute{
+				{AttributeName: "street", AttributeType: "VARCHAR(255)"},
+				{AttributeName: "n", AttributeType: "INT"},
+				{AttributeName: "updated_at", AttributeType: "TIMESTAMPTZ(3)"},
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err := Migrate(CreateMissing|UpdateExisting|DropExtraneous, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	buf := &bytes.Buffer{}
+	err = m.WriteSQL(buf)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(buf.String(), ""); diff != "" {
+		t.Error(testutil.Callers(), diff)
+	}
+}
@@ -0,0 +1,84 @@
+package ddl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// WriteJSON writes the DatabaseMetadata to w as indented JSON. The output can
+// be committed as a snapshot and loaded back with ReadJSON or WithSnapshot.
+func (dbm *DatabaseMetadata) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dbm)
+}
+
+// ReadJSON replaces the DatabaseMetadata with the JSON read from r and rebuilds
+// its position caches, which are not serialized.
+func (dbm *DatabaseMetadata) ReadJSON(r io.Reader) error {
+	var dbMetadata DatabaseMetadata
+	err := json.NewDecoder(r).Decode(&dbMetadata)
+	if err != nil {
+		return err
+	}
+	dbMetadata.refreshCaches()
+	*dbm = dbMetadata
+	return nil
+}
+
+// WithSnapshot loads the DatabaseMetadata from a JSON snapshot written by
+// WriteJSON. A snapshot replaces the whole DatabaseMetadata, so it returns an
+// error if an option before it already loaded any schemas or extensions.
+func WithSnapshot(fsys fs.FS, name string) DatabaseMetadataOption {
+	return func(dbm *DatabaseMetadata) error {
+		if len(dbm.Schemas) > 0 || len(dbm.Extensions) > 0 {
+			return fmt.Errorf("WithSnapshot %s: the DatabaseMetadata is already populated, WithSnapshot must be the first option", name)
+		}
+		file, err := fsys.Open(name)
+		if err != nil {
+			return fmt.Errorf("WithSnapshot: %w", err)
+		}
+		defer file.Close()
+		var dbMetadata DatabaseMetadata
+		err = dbMetadata.ReadJSON(file)
+		if err != nil {
+			return fmt.Errorf("WithSnapshot %s: %w", name, err)
+		}
+		if dbMetadata.Dialect == "" {
+			dbMetadata.Dialect = dbm.Dialect
+		} else if dbm.Dialect != "" && dbMetadata.Dialect != dbm.Dialect {
+			return fmt.Errorf("WithSnapshot %s: dialect mismatch: snapshot is %s, want %s", name, dbMetadata.Dialect, dbm.Dialect)
+		}
+		*dbm = dbMetadata
+		return nil
+	}
+}
+
+func (dbm *DatabaseMetadata) refreshCaches() {
+	dbm.RefreshSchemaCache()
+	dbm.RefreshExtensionCache()
+	for i := range dbm.Schemas {
+		schema := &dbm.Schemas[i]
+		schema.RefreshTableCache()
+		schema.RefreshViewCache()
+		schema.RefreshFunctionCache()
+		schema.RefreshEnumCache()
+		schema.RefreshSequenceCache()
+		schema.RefreshDomainCache()
+		schema.RefreshCompositeTypeCache()
+		for j := range schema.Tables {
+			tbl := &schema.Tables[j]
+			tbl.RefreshColumnCache()
+			tbl.RefreshConstraintCache()
+			tbl.RefreshIndexesCache()
+			tbl.RefreshTriggerCache()
+		}
+		for j := range schema.Views {
+			view := &schema.Views[j]
+			view.RefreshIndexCache()
+			view.RefreshTriggerCache()
+		}
+	}
+}
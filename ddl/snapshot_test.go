@@ -0,0 +1,104 @@
+package ddl
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_WithSnapshot(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		t.Parallel()
+		dbMetadata, err := NewDatabaseMetadata(sq.DialectPostgres,
+			WithSequences(invoiceNoSeq),
+			WithDomains(positiveAmount),
+			WithCompositeTypes(mailingAddress),
+			WithTables(NEW_INVOICE(), NEW_ENUM_FILM()),
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = dbMetadata.WriteJSON(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		fsys := fstest.MapFS{"snapshot.json": &fstest.MapFile{Data: buf.Bytes()}}
+		snapshot, err := NewDatabaseMetadata(sq.DialectPostgres, WithSnapshot(fsys, "snapshot.json"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		n1 := snapshot.CachedSchemaPosition("")
+		if n1 < 0 {
+			t.Fatal(testutil.Callers(), "schema cache not rebuilt")
+		}
+		schema := snapshot.Schemas[n1]
+		n2 := schema.CachedTablePosition("invoice")
+		if n2 < 0 {
+			t.Fatal(testutil.Callers(), "table cache not rebuilt")
+		}
+		if n3 := schema.Tables[n2].CachedColumnPosition("invoice_no"); n3 < 0 {
+			t.Error(testutil.Callers(), "column cache not rebuilt")
+		}
+		if schema.CachedEnumPosition("film_rating") < 0 ||
+			schema.CachedSequencePosition("invoice_no_seq") < 0 ||
+			schema.CachedDomainPosition("positive_amount") < 0 ||
+			schema.CachedCompositeTypePosition("mailing_address") < 0 {
+			t.Error(testutil.Callers(), "type caches not rebuilt")
+		}
+		m, err := Migrate(CreateMissing|UpdateExisting|DropExtraneous, snapshot, dbMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf.Reset()
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), ""); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("fixture", func(t *testing.T) {
+		t.Parallel()
+		dbMetadata, err := NewDatabaseMetadata("", WithSnapshot(os.DirFS("."), "got_metadata.json"))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(dbMetadata.Dialect, sq.DialectPostgres); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		n1 := dbMetadata.CachedSchemaPosition("public")
+		if n1 < 0 {
+			t.Fatal(testutil.Callers(), "schema public not found")
+		}
+		if n2 := dbMetadata.Schemas[n1].CachedTablePosition("pm_denied_plugin"); n2 < 0 {
+			t.Error(testutil.Callers(), "table pm_denied_plugin not found")
+		}
+	})
+
+	t.Run("dialect mismatch", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewDatabaseMetadata(sq.DialectMySQL, WithSnapshot(os.DirFS("."), "got_metadata.json"))
+		if err == nil {
+			t.Error(testutil.Callers(), "expected a dialect mismatch error")
+		}
+	})
+
+	t.Run("already populated", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(NEW_ENUM_FILM()), WithSnapshot(os.DirFS("."), "got_metadata.json"))
+		if err == nil {
+			t.Error(testutil.Callers(), "expected an error for WithSnapshot after WithTables")
+		}
+		_, err = NewDatabaseMetadata(sq.DialectPostgres, WithExtensions("pgcrypto"), WithSnapshot(os.DirFS("."), "got_metadata.json"))
+		if err == nil {
+			t.Error(testutil.Callers(), "expected an error for WithSnapshot after WithExtensions")
+		}
+	})
+}
@@ -0,0 +1,1459 @@
+package ddl
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/bokwoon95/sq"
+)
+
+// WithSQLFiles loads the tables, views, indexes, triggers, functions,
+// extensions, enums, composite types, domains and sequences created by the
+// CREATE statements in a set of .sql files. ALTER TABLE ... ADD statements are
+// applied to the tables they alter. Other statements (INSERT, DROP, SET etc)
+// are skipped.
+//
+// Indexes, triggers and ALTER TABLE statements are loaded after all tables
+// and views, so a file may refer to tables created in a later file.
+// Constraints and indexes without a name are named the same way as those
+// declared in struct tags.
+func WithSQLFiles(fsys fs.FS, filenames ...string) DatabaseMetadataOption {
+	return func(dbm *DatabaseMetadata) error {
+		if dbm.Dialect == "" {
+			return fmt.Errorf("WithSQLFiles: dialect cannot be empty")
+		}
+		type statement struct {
+			filename string
+			line     int
+			sql      string
+		}
+		var statements []statement
+		for _, filename := range filenames {
+			b, err := fs.ReadFile(fsys, filename)
+			if err != nil {
+				return fmt.Errorf("WithSQLFiles: %w", err)
+			}
+			stmts, lines := splitSQLStatements(dbm.Dialect, string(b))
+			for i, stmt := range stmts {
+				statements = append(statements, statement{filename: filename, line: lines[i], sql: stmt})
+			}
+		}
+		for _, dependents := range []bool{false, true} {
+			for _, stmt := range statements {
+				err := dbm.loadSQLStatement(stmt.sql, dependents)
+				if err != nil {
+					return fmt.Errorf("WithSQLFiles %s:%d: %w", stmt.filename, stmt.line, err)
+				}
+			}
+		}
+		for i := range dbm.Schemas {
+			for j := range dbm.Schemas[i].Tables {
+				dbm.Schemas[i].Tables[j].markKeyColumns()
+			}
+		}
+		return nil
+	}
+}
+
+// splitSQLStatements splits src into its statements and returns the line
+// each statement starts on. Statements end with a semicolon outside of
+// strings, comments and the BEGIN ... END body of a trigger or routine. MySQL
+// DELIMITER lines change the statement delimiter.
+func splitSQLStatements(dialect, src string) (stmts []string, lines []int) {
+	sc := &sqlScanner{dialect: dialect, s: src}
+	delimiter := ";"
+	for {
+		sc.skipSpace()
+		if sc.i >= len(src) {
+			break
+		}
+		start := sc.i
+		if dialect == sq.DialectMySQL && len(src)-start > 10 && strings.EqualFold(src[start:start+10], "DELIMITER ") {
+			end := strings.IndexByte(src[start:], '\n')
+			if end < 0 {
+				end = len(src) - start
+			}
+			delimiter = strings.TrimSpace(src[start+10 : start+end])
+			sc.i = start + end
+			continue
+		}
+		end := len(src)
+		var depth, words int
+		var isCreate, isRoutine, bracketSeen, skipWord bool
+		for {
+			sc.skipSpace()
+			if sc.i >= len(src) {
+				break
+			}
+			if depth == 0 && strings.HasPrefix(src[sc.i:], delimiter) {
+				end = sc.i
+				sc.i += len(delimiter)
+				break
+			}
+			token := sc.pop()
+			// A custom delimiter like $$ may be glued onto the preceding
+			// word, as in END$$.
+			if delimiter != ";" && len(token) > len(delimiter) && isWord(token) && strings.HasSuffix(token, delimiter) {
+				token = token[:len(token)-len(delimiter)]
+				sc.i -= len(delimiter)
+			}
+			if token == "(" {
+				bracketSeen = true
+			}
+			if !isWord(token) {
+				continue
+			}
+			words++
+			// The CASE, IF, LOOP, WHILE or REPEAT after an END closes that
+			// block and must not open a new one.
+			if skipWord {
+				skipWord = false
+				continue
+			}
+			switch word := strings.ToUpper(token); word {
+			case "CREATE":
+				isCreate = isCreate || words == 1
+			case "TRIGGER", "FUNCTION", "PROCEDURE", "EVENT":
+				isRoutine = isRoutine || (isCreate && !bracketSeen)
+			case "BEGIN":
+				if isRoutine {
+					depth++
+				}
+			case "CASE":
+				if depth > 0 {
+					depth++
+				}
+			case "END":
+				if depth > 0 {
+					switch next := strings.ToUpper(strings.TrimSuffix(sc.peek(), delimiter)); next {
+					case "IF", "LOOP", "WHILE", "REPEAT":
+						skipWord = true
+					case "CASE":
+						depth--
+						skipWord = true
+					default:
+						depth--
+					}
+				}
+			}
+		}
+		if stmt := strings.TrimSpace(src[start:end]); stmt != "" {
+			stmts = append(stmts, stmt)
+			lines = append(lines, 1+strings.Count(src[:start], "\n"))
+		}
+	}
+	return stmts, lines
+}
+
+// loadSQLStatement loads the objects created by a single SQL statement. If
+// dependents is false only tables, views, functions, schemas and extensions
+// are loaded, otherwise only the indexes, triggers and constraints that
+// depend on them.
+func (dbm *DatabaseMetadata) loadSQLStatement(stmt string, dependents bool) error {
+	sc := &sqlScanner{dialect: dbm.Dialect, s: stmt}
+	if sc.keyword("ALTER", "TABLE") {
+		if !dependents {
+			return nil
+		}
+		return dbm.loadAlterTable(sc)
+	}
+	if !sc.keyword("CREATE") {
+		return nil
+	}
+	var orReplace, isMaterialized, isVirtual bool
+	var indexType string
+LOOP:
+	for {
+		switch strings.ToUpper(sc.peek()) {
+		case "OR":
+			orReplace = sc.keyword("OR", "REPLACE")
+			if !orReplace {
+				break LOOP
+			}
+		case "TEMP", "TEMPORARY", "UNLOGGED", "RECURSIVE", "CONSTRAINT":
+			sc.pop()
+		case "MATERIALIZED":
+			sc.pop()
+			isMaterialized = dbm.Dialect == sq.DialectPostgres
+		case "VIRTUAL":
+			sc.pop()
+			isVirtual = true
+		case "UNIQUE", "FULLTEXT", "SPATIAL":
+			indexType = strings.ToUpper(sc.pop())
+		case "ALGORITHM", "DEFINER":
+			sc.pop()
+			if sc.pop() != "=" {
+				return fmt.Errorf("CREATE: expected =")
+			}
+			sc.pop()
+			if sc.peek() == "@" {
+				sc.pop()
+				sc.pop()
+			}
+		case "SQL":
+			if !sc.keyword("SQL", "SECURITY") {
+				break LOOP
+			}
+			sc.pop()
+		default:
+			break LOOP
+		}
+	}
+	switch objectType := strings.ToUpper(sc.pop()); objectType {
+	case "TABLE":
+		if dependents {
+			return nil
+		}
+		if isVirtual {
+			return dbm.loadCreateVirtualTable(sc)
+		}
+		return dbm.loadCreateTable(sc)
+	case "VIEW":
+		if dependents {
+			return nil
+		}
+		return dbm.loadCreateView(sc, orReplace, isMaterialized)
+	case "FUNCTION", "PROCEDURE":
+		if dependents {
+			return nil
+		}
+		if dbm.Dialect == sq.DialectSQLite {
+			return fmt.Errorf("sqlite does not support functions")
+		}
+		function := Function{SQL: stmt}
+		err := function.populateFunctionInfo(dbm.Dialect)
+		if err != nil {
+			return err
+		}
+		return dbm.loadFunction(function)
+	case "EXTENSION":
+		if dependents {
+			return nil
+		}
+		if dbm.Dialect != sq.DialectPostgres {
+			return fmt.Errorf("%s does not support extensions", dbm.Dialect)
+		}
+		sc.keyword("IF", "NOT", "EXISTS")
+		extension, err := sc.name()
+		if err != nil {
+			return fmt.Errorf("CREATE EXTENSION: %w", err)
+		}
+		if dbm.CachedExtensionPosition(extension) < 0 {
+			dbm.AppendExtension(extension)
+		}
+		return nil
+	case "SCHEMA":
+		if dependents {
+			return nil
+		}
+		sc.keyword("IF", "NOT", "EXISTS")
+		schemaName, err := sc.name()
+		if err != nil {
+			return fmt.Errorf("CREATE SCHEMA: %w", err)
+		}
+		dbm.loadSchemaObject(schemaName, func(schema *Schema) {})
+		return nil
+	case "TYPE":
+		if dependents {
+			return nil
+		}
+		return dbm.loadCreateType(sc)
+	case "DOMAIN":
+		if dependents {
+			return nil
+		}
+		return dbm.loadCreateDomain(sc)
+	case "SEQUENCE":
+		if dependents {
+			return nil
+		}
+		return dbm.loadCreateSequence(sc)
+	case "INDEX":
+		if !dependents {
+			return nil
+		}
+		return dbm.loadCreateIndex(sc, indexType)
+	case "TRIGGER":
+		if !dependents {
+			return nil
+		}
+		return dbm.loadCreateTrigger(stmt)
+	}
+	return nil
+}
+
+// loadCreateType loads a postgres CREATE TYPE ... AS ENUM (...) or CREATE TYPE
+// ... AS (...). Other kinds of types (range, base and shell types) are not
+// supported.
+func (dbm *DatabaseMetadata) loadCreateType(sc *sqlScanner) error {
+	if sc.dialect != sq.DialectPostgres {
+		return fmt.Errorf("%s does not support CREATE TYPE", sc.dialect)
+	}
+	typeSchema, typeName, err := sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("CREATE TYPE: %w", err)
+	}
+	if sc.keyword("AS", "ENUM") {
+		body, ok := sc.group()
+		if !ok {
+			return fmt.Errorf("CREATE TYPE %s: expected ('label', ...)", typeName)
+		}
+		enum := Enum{EnumSchema: typeSchema, EnumName: typeName}
+		bodySc := &sqlScanner{dialect: sc.dialect, s: body}
+		for !bodySc.eof() {
+			label, err := unquoteString(bodySc.pop())
+			if err != nil {
+				return fmt.Errorf("CREATE TYPE %s: %w", typeName, err)
+			}
+			enum.EnumLabels = append(enum.EnumLabels, label)
+			if token := bodySc.pop(); token != "," && token != "" {
+				return fmt.Errorf("CREATE TYPE %s: unexpected %q", typeName, token)
+			}
+		}
+		dbm.loadSchemaObject(typeSchema, func(schema *Schema) {
+			if schema.CachedEnumPosition(typeName) >= 0 {
+				err = fmt.Errorf("CREATE TYPE %s: type already exists", typeName)
+				return
+			}
+			schema.AppendEnum(enum)
+		})
+		return err
+	}
+	if !sc.keyword("AS") {
+		return fmt.Errorf("CREATE TYPE %s: only enum and composite types are supported", typeName)
+	}
+	body, ok := sc.group()
+	if !ok {
+		return fmt.Errorf("CREATE TYPE %s: only enum and composite types are supported", typeName)
+	}
+	compositeType := CompositeType{TypeSchema: typeSchema, TypeName: typeName}
+	bodySc := &sqlScanner{dialect: sc.dialect, s: body}
+	for !bodySc.eof() {
+		var attribute CompositeTypeAttribute
+		attribute.AttributeName, err = bodySc.name()
+		if err != nil {
+			return fmt.Errorf("CREATE TYPE %s: %w", typeName, err)
+		}
+		attribute.AttributeType = bodySc.rawUntil("COLLATE")
+		if attribute.AttributeType == "" {
+			return fmt.Errorf("CREATE TYPE %s: attribute %s has no type", typeName, attribute.AttributeName)
+		}
+		if bodySc.keyword("COLLATE") {
+			attribute.CollationName, err = bodySc.name()
+			if err != nil {
+				return fmt.Errorf("CREATE TYPE %s: COLLATE: %w", typeName, err)
+			}
+		}
+		compositeType.Attributes = append(compositeType.Attributes, attribute)
+		if token := bodySc.pop(); token != "," && token != "" {
+			return fmt.Errorf("CREATE TYPE %s: unexpected %q", typeName, token)
+		}
+	}
+	if len(compositeType.Attributes) == 0 {
+		return fmt.Errorf("CREATE TYPE %s: type has no attributes", typeName)
+	}
+	dbm.loadSchemaObject(typeSchema, func(schema *Schema) {
+		if schema.CachedCompositeTypePosition(typeName) >= 0 {
+			err = fmt.Errorf("CREATE TYPE %s: type already exists", typeName)
+			return
+		}
+		schema.AppendCompositeType(compositeType)
+	})
+	return err
+}
+
+// domainClauses are the keywords that end the underlying type of a domain.
+var domainClauses = []string{"COLLATE", "DEFAULT", "CONSTRAINT", "NOT", "NULL", "CHECK"}
+
+func (dbm *DatabaseMetadata) loadCreateDomain(sc *sqlScanner) error {
+	if sc.dialect != sq.DialectPostgres {
+		return fmt.Errorf("%s does not support domains", sc.dialect)
+	}
+	domainSchema, domainName, err := sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("CREATE DOMAIN: %w", err)
+	}
+	sc.keyword("AS")
+	domain := Domain{
+		DomainSchema:   domainSchema,
+		DomainName:     domainName,
+		UnderlyingType: sc.rawUntil(domainClauses...),
+	}
+	if domain.UnderlyingType == "" {
+		return fmt.Errorf("CREATE DOMAIN %s: expected a data type", domainName)
+	}
+	for !sc.eof() {
+		var constraintName string
+		if sc.keyword("CONSTRAINT") {
+			constraintName, err = sc.name()
+			if err != nil {
+				return fmt.Errorf("CREATE DOMAIN %s: CONSTRAINT: %w", domainName, err)
+			}
+		}
+		switch {
+		case constraintName == "" && sc.keyword("COLLATE"):
+			domain.CollationName, err = sc.name()
+			if err != nil {
+				return fmt.Errorf("CREATE DOMAIN %s: COLLATE: %w", domainName, err)
+			}
+		case constraintName == "" && sc.keyword("DEFAULT"):
+			domain.DomainDefault = sc.rawUntil(domainClauses...)
+		case sc.keyword("NOT", "NULL"):
+			domain.IsNotNull = true
+		case sc.keyword("NULL"):
+			domain.IsNotNull = false
+		case sc.keyword("CHECK"):
+			checkExpr, ok := sc.group()
+			if !ok {
+				return fmt.Errorf("CREATE DOMAIN %s: CHECK: expected (expression)", domainName)
+			}
+			domain.Checks = append(domain.Checks, DomainCheck{
+				ConstraintName: constraintName,
+				CheckExpr:      strings.TrimSpace(checkExpr),
+			})
+		default:
+			return fmt.Errorf("CREATE DOMAIN %s: unexpected %q", domainName, sc.peek())
+		}
+	}
+	domain = nameDomainChecks(domain)
+	dbm.loadSchemaObject(domainSchema, func(schema *Schema) {
+		if schema.CachedDomainPosition(domainName) >= 0 {
+			err = fmt.Errorf("CREATE DOMAIN %s: domain already exists", domainName)
+			return
+		}
+		schema.AppendDomain(domain)
+	})
+	return err
+}
+
+// sequenceClauses are the keywords that start the options of a sequence.
+var sequenceClauses = []string{"AS", "INCREMENT", "MINVALUE", "MAXVALUE", "NO", "START", "CACHE", "CYCLE", "OWNED"}
+
+func (dbm *DatabaseMetadata) loadCreateSequence(sc *sqlScanner) error {
+	if sc.dialect != sq.DialectPostgres {
+		return fmt.Errorf("%s does not support sequences", sc.dialect)
+	}
+	ifNotExists := sc.keyword("IF", "NOT", "EXISTS")
+	sequenceSchema, sequenceName, err := sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("CREATE SEQUENCE: %w", err)
+	}
+	sequence := Sequence{SequenceSchema: sequenceSchema, SequenceName: sequenceName}
+	for !sc.eof() {
+		switch {
+		case sc.keyword("AS"):
+			sequence.DataType = sc.rawUntil(sequenceClauses...)
+		case sc.keyword("INCREMENT"):
+			sc.keyword("BY")
+			sequence.Increment, err = sc.integer()
+		case sc.keyword("MINVALUE"):
+			sequence.MinValue, err = sc.integer()
+		case sc.keyword("MAXVALUE"):
+			sequence.MaxValue, err = sc.integer()
+		case sc.keyword("START"):
+			sc.keyword("WITH")
+			sequence.StartValue, err = sc.integer()
+		case sc.keyword("CACHE"):
+			_, err = sc.integer()
+		case sc.keyword("CYCLE"):
+			sequence.Cycle = true
+		case sc.keyword("NO", "CYCLE"):
+			sequence.Cycle = false
+		case sc.keyword("NO", "MINVALUE"), sc.keyword("NO", "MAXVALUE"):
+		case sc.keyword("OWNED", "BY"):
+			sc.rawUntil(sequenceClauses...)
+		default:
+			return fmt.Errorf("CREATE SEQUENCE %s: unexpected %q", sequenceName, sc.peek())
+		}
+		if err != nil {
+			return fmt.Errorf("CREATE SEQUENCE %s: %w", sequenceName, err)
+		}
+	}
+	dbm.loadSchemaObject(sequenceSchema, func(schema *Schema) {
+		if schema.CachedSequencePosition(sequenceName) >= 0 {
+			if !ifNotExists {
+				err = fmt.Errorf("CREATE SEQUENCE %s: sequence already exists", sequenceName)
+			}
+			return
+		}
+		schema.AppendSequence(sequence)
+	})
+	return err
+}
+
+func (dbm *DatabaseMetadata) loadCreateTable(sc *sqlScanner) error {
+	ifNotExists := sc.keyword("IF", "NOT", "EXISTS")
+	tableSchema, tableName, err := sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("CREATE TABLE: %w", err)
+	}
+	body, ok := sc.group()
+	if !ok {
+		return fmt.Errorf("CREATE TABLE %s: expected column definitions, only tables with column definitions are supported", tableName)
+	}
+	tbl := Table{TableSchema: tableSchema, TableName: tableName}
+	bodySc := &sqlScanner{dialect: sc.dialect, s: body}
+	for !bodySc.eof() {
+		err = tbl.loadTableElement(bodySc)
+		if err != nil {
+			return fmt.Errorf("CREATE TABLE %s: %w", tableName, err)
+		}
+		if token := bodySc.pop(); token != "," && token != "" {
+			return fmt.Errorf("CREATE TABLE %s: unexpected %q", tableName, token)
+		}
+	}
+	return dbm.loadSQLTable(tbl, ifNotExists)
+}
+
+// loadCreateVirtualTable loads a sqlite CREATE VIRTUAL TABLE. The arguments
+// of an fts5 table that are not options (option=value) are its columns.
+func (dbm *DatabaseMetadata) loadCreateVirtualTable(sc *sqlScanner) error {
+	if sc.dialect != sq.DialectSQLite {
+		return fmt.Errorf("%s does not support virtual tables", sc.dialect)
+	}
+	ifNotExists := sc.keyword("IF", "NOT", "EXISTS")
+	tableSchema, tableName, err := sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("CREATE VIRTUAL TABLE: %w", err)
+	}
+	if !sc.keyword("USING") {
+		return fmt.Errorf("CREATE VIRTUAL TABLE %s: expected USING", tableName)
+	}
+	tbl := Table{TableSchema: tableSchema, TableName: tableName}
+	tbl.VirtualTable = sc.pop()
+	if args, ok := sc.group(); ok {
+		tbl.VirtualTableArgs, err = (&sqlScanner{dialect: sc.dialect, s: args}).list()
+		if err != nil {
+			return fmt.Errorf("CREATE VIRTUAL TABLE %s: %w", tableName, err)
+		}
+	}
+	if strings.EqualFold(tbl.VirtualTable, "FTS5") {
+		for _, arg := range tbl.VirtualTableArgs {
+			if !strings.Contains(arg, "=") {
+				tbl.AppendColumn(Column{
+					TableSchema: tableSchema,
+					TableName:   tableName,
+					ColumnName:  arg,
+					ColumnType:  "TEXT",
+				})
+			}
+		}
+	}
+	return dbm.loadSQLTable(tbl, ifNotExists)
+}
+
+func (dbm *DatabaseMetadata) loadSQLTable(tbl Table, ifNotExists bool) error {
+	var err error
+	dbm.loadSchemaObject(tbl.TableSchema, func(schema *Schema) {
+		if schema.CachedTablePosition(tbl.TableName) >= 0 {
+			if !ifNotExists {
+				err = fmt.Errorf("CREATE TABLE %s: table already exists", tbl.TableName)
+			}
+			return
+		}
+		schema.AppendTable(tbl)
+	})
+	return err
+}
+
+func (dbm *DatabaseMetadata) loadCreateView(sc *sqlScanner, orReplace, isMaterialized bool) error {
+	ifNotExists := sc.keyword("IF", "NOT", "EXISTS")
+	viewSchema, viewName, err := sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("CREATE VIEW: %w", err)
+	}
+	if sc.peek() == "(" {
+		return fmt.Errorf("CREATE VIEW %s: view column lists are not supported, alias the columns in the query instead", viewName)
+	}
+	if !sc.keyword("AS") {
+		return fmt.Errorf("CREATE VIEW %s: expected AS", viewName)
+	}
+	view := View{
+		ViewSchema:     viewSchema,
+		ViewName:       viewName,
+		IsMaterialized: isMaterialized,
+		SQL:            sc.rest(),
+	}
+	dbm.loadSchemaObject(viewSchema, func(schema *Schema) {
+		n := schema.CachedViewPosition(viewName)
+		switch {
+		case n < 0:
+			schema.AppendView(view)
+		case orReplace:
+			schema.Views[n].SQL = view.SQL
+		case !ifNotExists:
+			err = fmt.Errorf("CREATE VIEW %s: view already exists", viewName)
+		}
+	})
+	return err
+}
+
+func (dbm *DatabaseMetadata) loadCreateIndex(sc *sqlScanner, indexType string) error {
+	index := Index{IsUnique: indexType == "UNIQUE"}
+	if indexType == "FULLTEXT" || indexType == "SPATIAL" {
+		index.IndexType = indexType
+	}
+	sc.keyword("CONCURRENTLY")
+	ifNotExists := sc.keyword("IF", "NOT", "EXISTS")
+	var err error
+	if !strings.EqualFold(sc.peek(), "ON") {
+		index.IndexName, err = sc.name()
+		if err != nil {
+			return fmt.Errorf("CREATE INDEX: %w", err)
+		}
+	}
+	if sc.keyword("USING") {
+		index.IndexType = strings.ToUpper(sc.pop())
+	}
+	if !sc.keyword("ON") {
+		return fmt.Errorf("CREATE INDEX %s: expected ON", index.IndexName)
+	}
+	sc.keyword("ONLY")
+	index.TableSchema, index.TableName, err = sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("CREATE INDEX %s: %w", index.IndexName, err)
+	}
+	if sc.keyword("USING") {
+		index.IndexType = strings.ToUpper(sc.pop())
+	}
+	elements, ok := sc.group()
+	if !ok {
+		return fmt.Errorf("CREATE INDEX %s: expected index columns", index.IndexName)
+	}
+	index.Columns, index.Exprs, err = indexElements(sc.dialect, elements)
+	if err != nil {
+		return fmt.Errorf("CREATE INDEX %s: %w", index.IndexName, err)
+	}
+	for !sc.eof() {
+		switch {
+		case sc.keyword("INCLUDE"):
+			includeColumns, ok := sc.group()
+			if !ok {
+				return fmt.Errorf("CREATE INDEX %s: expected INCLUDE columns", index.IndexName)
+			}
+			index.IncludeColumns, err = (&sqlScanner{dialect: sc.dialect, s: includeColumns}).names()
+			if err != nil {
+				return fmt.Errorf("CREATE INDEX %s: %w", index.IndexName, err)
+			}
+		case sc.keyword("WHERE"):
+			index.Predicate = sc.rest()
+		case sc.keyword("USING"):
+			index.IndexType = strings.ToUpper(sc.pop())
+		default:
+			return fmt.Errorf("CREATE INDEX %s: unsupported clause %q", index.IndexName, sc.peek())
+		}
+	}
+	if index.IndexName == "" {
+		index.IndexName = generateName(INDEX, index.TableName, index.Columns...)
+	}
+	return dbm.loadSQLIndex(index, ifNotExists)
+}
+
+func (dbm *DatabaseMetadata) loadSQLIndex(index Index, ifNotExists bool) error {
+	n1 := dbm.CachedSchemaPosition(index.TableSchema)
+	if n1 < 0 {
+		return fmt.Errorf("CREATE INDEX %s: table %s does not exist", index.IndexName, index.TableName)
+	}
+	schema := &dbm.Schemas[n1]
+	if n2 := schema.CachedTablePosition(index.TableName); n2 >= 0 {
+		tbl := &schema.Tables[n2]
+		if tbl.CachedIndexPosition(index.IndexName) >= 0 {
+			if ifNotExists {
+				return nil
+			}
+			return fmt.Errorf("CREATE INDEX %s: index already exists", index.IndexName)
+		}
+		tbl.AppendIndex(index)
+		return nil
+	}
+	if n2 := schema.CachedViewPosition(index.TableName); n2 >= 0 {
+		view := &schema.Views[n2]
+		if view.CachedIndexPosition(index.IndexName) >= 0 {
+			if ifNotExists {
+				return nil
+			}
+			return fmt.Errorf("CREATE INDEX %s: index already exists", index.IndexName)
+		}
+		view.AppendIndex(index)
+		return nil
+	}
+	return fmt.Errorf("CREATE INDEX %s: table %s does not exist", index.IndexName, index.TableName)
+}
+
+func (dbm *DatabaseMetadata) loadCreateTrigger(stmt string) error {
+	trigger := Trigger{SQL: stmt}
+	err := trigger.populateTriggerInfo(dbm.Dialect)
+	if err != nil {
+		return err
+	}
+	trigger.TableSchema = unquoteIdentifier(dbm.Dialect, trigger.TableSchema)
+	trigger.TableName = unquoteIdentifier(dbm.Dialect, trigger.TableName)
+	trigger.TriggerName = unquoteIdentifier(dbm.Dialect, trigger.TriggerName)
+	n1 := dbm.CachedSchemaPosition(trigger.TableSchema)
+	if n1 < 0 {
+		return fmt.Errorf("CREATE TRIGGER %s: table %s does not exist", trigger.TriggerName, trigger.TableName)
+	}
+	schema := &dbm.Schemas[n1]
+	if n2 := schema.CachedTablePosition(trigger.TableName); n2 >= 0 {
+		tbl := &schema.Tables[n2]
+		if tbl.CachedTriggerPosition(trigger.TriggerName) >= 0 {
+			return fmt.Errorf("CREATE TRIGGER %s: trigger already exists", trigger.TriggerName)
+		}
+		tbl.AppendTrigger(trigger)
+		return nil
+	}
+	if n2 := schema.CachedViewPosition(trigger.TableName); n2 >= 0 {
+		view := &schema.Views[n2]
+		if view.CachedTriggerPosition(trigger.TableSchema, trigger.TableName, trigger.TriggerName) >= 0 {
+			return fmt.Errorf("CREATE TRIGGER %s: trigger already exists", trigger.TriggerName)
+		}
+		view.AppendTrigger(trigger)
+		return nil
+	}
+	return fmt.Errorf("CREATE TRIGGER %s: table %s does not exist", trigger.TriggerName, trigger.TableName)
+}
+
+// loadAlterTable applies the ADD actions of an ALTER TABLE statement. Any
+// other action is an error.
+func (dbm *DatabaseMetadata) loadAlterTable(sc *sqlScanner) error {
+	sc.keyword("IF", "EXISTS")
+	sc.keyword("ONLY")
+	tableSchema, tableName, err := sc.qualifiedName()
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE: %w", err)
+	}
+	n1 := dbm.CachedSchemaPosition(tableSchema)
+	if n1 < 0 {
+		return fmt.Errorf("ALTER TABLE %s: table does not exist", tableName)
+	}
+	n2 := dbm.Schemas[n1].CachedTablePosition(tableName)
+	if n2 < 0 {
+		return fmt.Errorf("ALTER TABLE %s: table does not exist", tableName)
+	}
+	tbl := &dbm.Schemas[n1].Tables[n2]
+	for {
+		if !sc.keyword("ADD") {
+			return fmt.Errorf("ALTER TABLE %s: unsupported action %q, only ADD is supported", tableName, sc.peek())
+		}
+		if sc.keyword("COLUMN") {
+			sc.keyword("IF", "NOT", "EXISTS")
+			err = tbl.loadColumnDefinition(sc)
+		} else {
+			err = tbl.loadTableElement(sc)
+		}
+		if err != nil {
+			return fmt.Errorf("ALTER TABLE %s: %w", tableName, err)
+		}
+		switch token := sc.pop(); token {
+		case ",":
+			continue
+		case "":
+			return nil
+		default:
+			return fmt.Errorf("ALTER TABLE %s: unexpected %q", tableName, token)
+		}
+	}
+}
+
+// loadTableElement loads a column definition or table constraint (or a mysql
+// index) of a CREATE TABLE.
+func (tbl *Table) loadTableElement(sc *sqlScanner) error {
+	var constraintName string
+	if sc.keyword("CONSTRAINT") {
+		switch strings.ToUpper(sc.peek()) {
+		case "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE":
+		default:
+			var err error
+			constraintName, err = sc.name()
+			if err != nil {
+				return fmt.Errorf("CONSTRAINT: %w", err)
+			}
+		}
+	}
+	constraint := Constraint{
+		TableSchema:    tbl.TableSchema,
+		TableName:      tbl.TableName,
+		ConstraintName: constraintName,
+	}
+	isMySQL := sc.dialect == sq.DialectMySQL
+	var err error
+	switch {
+	case sc.keyword("PRIMARY", "KEY"):
+		constraint.ConstraintType = PRIMARY_KEY
+		constraint.Columns, err = sc.nameGroup()
+	case sc.keyword("UNIQUE"):
+		if isMySQL && (sc.keyword("INDEX") || sc.keyword("KEY")) {
+			return tbl.loadMySQLIndex(sc, true, "")
+		}
+		sc.keyword("NULLS", "NOT", "DISTINCT")
+		constraint.ConstraintType = UNIQUE
+		constraint.Columns, err = sc.nameGroup()
+	case sc.keyword("FOREIGN", "KEY"):
+		constraint.ConstraintType = FOREIGN_KEY
+		constraint.Columns, err = sc.nameGroup()
+		if err == nil {
+			if !sc.keyword("REFERENCES") {
+				return fmt.Errorf("FOREIGN KEY: expected REFERENCES")
+			}
+			err = constraint.loadReferences(sc)
+		}
+	case sc.keyword("CHECK"):
+		constraint.ConstraintType = CHECK
+		var ok bool
+		constraint.CheckExpr, ok = sc.group()
+		if !ok {
+			return fmt.Errorf("CHECK: expected (expression)")
+		}
+		constraint.CheckExpr = strings.TrimSpace(constraint.CheckExpr)
+	case sc.keyword("EXCLUDE"):
+		constraint.ConstraintType = EXCLUDE
+		err = constraint.loadExclusion(sc)
+	case isMySQL && constraintName == "" && (sc.keyword("INDEX") || sc.keyword("KEY")):
+		return tbl.loadMySQLIndex(sc, false, "")
+	case isMySQL && constraintName == "" && (strings.EqualFold(sc.peek(), "FULLTEXT") || strings.EqualFold(sc.peek(), "SPATIAL")):
+		indexType := strings.ToUpper(sc.pop())
+		if !sc.keyword("INDEX") {
+			sc.keyword("KEY")
+		}
+		return tbl.loadMySQLIndex(sc, false, indexType)
+	default:
+		if constraintName != "" {
+			return fmt.Errorf("CONSTRAINT %s: expected PRIMARY KEY, UNIQUE, FOREIGN KEY, CHECK or EXCLUDE", constraintName)
+		}
+		return tbl.loadColumnDefinition(sc)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", constraint.ConstraintType, err)
+	}
+	constraint.loadConstraintTiming(sc)
+	return tbl.appendSQLConstraint(constraint)
+}
+
+// columnClauses are the keywords that end the column type of a column
+// definition.
+var columnClauses = []string{
+	"NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK",
+	"CONSTRAINT", "GENERATED", "AS", "COLLATE", "AUTO_INCREMENT",
+	"AUTOINCREMENT", "ON", "COMMENT", "CHARACTER", "CHARSET",
+}
+
+func (tbl *Table) loadColumnDefinition(sc *sqlScanner) error {
+	columnName, err := sc.name()
+	if err != nil {
+		return fmt.Errorf("column: %w", err)
+	}
+	if tbl.CachedColumnPosition(columnName) >= 0 {
+		return fmt.Errorf("column %s already exists", columnName)
+	}
+	column := Column{
+		TableSchema: tbl.TableSchema,
+		TableName:   tbl.TableName,
+		ColumnName:  columnName,
+		ColumnType:  sc.rawUntil(columnClauses...),
+	}
+	for {
+		var constraintName string
+		if sc.keyword("CONSTRAINT") {
+			constraintName, err = sc.name()
+			if err != nil {
+				return fmt.Errorf("column %s: CONSTRAINT: %w", columnName, err)
+			}
+		}
+		constraint := Constraint{
+			TableSchema:    tbl.TableSchema,
+			TableName:      tbl.TableName,
+			ConstraintName: constraintName,
+			Columns:        []string{columnName},
+		}
+		switch token := sc.peek(); {
+		case token == "" || token == ",":
+			tbl.AppendColumn(column)
+			return nil
+		case sc.keyword("NOT", "NULL"):
+			column.IsNotNull = true
+		case sc.keyword("NULL"):
+			column.IsNotNull = false
+		case sc.keyword("DEFAULT"):
+			column.ColumnDefault = sc.rawUntil(columnClauses...)
+			if column.ColumnDefault == "" {
+				return fmt.Errorf("column %s: DEFAULT: expected a value", columnName)
+			}
+		case sc.keyword("PRIMARY", "KEY"):
+			if !sc.keyword("ASC") {
+				sc.keyword("DESC")
+			}
+			if sc.keyword("AUTOINCREMENT") {
+				column.IsAutoincrement = true
+			}
+			constraint.ConstraintType = PRIMARY_KEY
+		case sc.keyword("AUTOINCREMENT"), sc.keyword("AUTO_INCREMENT"):
+			column.IsAutoincrement = true
+		case sc.keyword("UNIQUE"):
+			sc.keyword("KEY")
+			constraint.ConstraintType = UNIQUE
+		case sc.keyword("REFERENCES"):
+			constraint.ConstraintType = FOREIGN_KEY
+			err = constraint.loadReferences(sc)
+			if err != nil {
+				return fmt.Errorf("column %s: REFERENCES: %w", columnName, err)
+			}
+		case sc.keyword("CHECK"):
+			checkExpr, ok := sc.group()
+			if !ok {
+				return fmt.Errorf("column %s: CHECK: expected (expression)", columnName)
+			}
+			constraint.ConstraintType = CHECK
+			constraint.CheckExpr = strings.TrimSpace(checkExpr)
+		case sc.keyword("GENERATED", "ALWAYS", "AS", "IDENTITY"):
+			column.Identity = ALWAYS_AS_IDENTITY
+			sc.group()
+		case sc.keyword("GENERATED", "BY", "DEFAULT", "AS", "IDENTITY"):
+			column.Identity = BY_DEFAULT_AS_IDENTITY
+			sc.group()
+		case sc.keyword("GENERATED", "ALWAYS", "AS"), sc.keyword("AS"):
+			generatedExpr, ok := sc.group()
+			if !ok {
+				return fmt.Errorf("column %s: GENERATED: expected (expression)", columnName)
+			}
+			column.GeneratedExpr = strings.TrimSpace(generatedExpr)
+			column.GeneratedExprStored = sc.dialect == sq.DialectPostgres
+			if sc.keyword("STORED") {
+				column.GeneratedExprStored = true
+			} else if sc.keyword("VIRTUAL") {
+				column.GeneratedExprStored = false
+			}
+		case sc.keyword("COLLATE"):
+			column.CollationName, err = sc.name()
+			if err != nil {
+				return fmt.Errorf("column %s: COLLATE: %w", columnName, err)
+			}
+		case sc.keyword("ON", "UPDATE"):
+			value := sc.rawUntil(columnClauses...)
+			if !strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP") {
+				return fmt.Errorf("column %s: ON UPDATE %s is not supported, only ON UPDATE CURRENT_TIMESTAMP is", columnName, value)
+			}
+			column.OnUpdateCurrentTimestamp = true
+		case sc.keyword("COMMENT"):
+			column.ColumnComment, err = unquoteString(sc.pop())
+			if err != nil {
+				return fmt.Errorf("column %s: COMMENT: %w", columnName, err)
+			}
+		case sc.keyword("CHARACTER", "SET"), sc.keyword("CHARSET"):
+			sc.pop()
+		default:
+			return fmt.Errorf("column %s: unsupported clause %q", columnName, token)
+		}
+		if constraint.ConstraintType != "" {
+			constraint.loadConstraintTiming(sc)
+			err = tbl.appendSQLConstraint(constraint)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", columnName, err)
+			}
+		}
+	}
+}
+
+// loadMySQLIndex loads an index declared inside a mysql CREATE TABLE, after
+// the INDEX or KEY keyword.
+func (tbl *Table) loadMySQLIndex(sc *sqlScanner, isUnique bool, indexType string) error {
+	index := Index{
+		TableSchema: tbl.TableSchema,
+		TableName:   tbl.TableName,
+		IndexType:   indexType,
+		IsUnique:    isUnique,
+	}
+	var err error
+	if sc.peek() != "(" && !strings.EqualFold(sc.peek(), "USING") {
+		index.IndexName, err = sc.name()
+		if err != nil {
+			return fmt.Errorf("INDEX: %w", err)
+		}
+	}
+	if sc.keyword("USING") {
+		index.IndexType = strings.ToUpper(sc.pop())
+	}
+	elements, ok := sc.group()
+	if !ok {
+		return fmt.Errorf("INDEX %s: expected index columns", index.IndexName)
+	}
+	index.Columns, index.Exprs, err = indexElements(sc.dialect, elements)
+	if err != nil {
+		return fmt.Errorf("INDEX %s: %w", index.IndexName, err)
+	}
+	if sc.keyword("USING") {
+		index.IndexType = strings.ToUpper(sc.pop())
+	}
+	if index.IndexName == "" {
+		index.IndexName = generateName(INDEX, tbl.TableName, index.Columns...)
+	}
+	if tbl.CachedIndexPosition(index.IndexName) >= 0 {
+		return fmt.Errorf("INDEX %s: index already exists", index.IndexName)
+	}
+	tbl.AppendIndex(index)
+	return nil
+}
+
+// appendSQLConstraint appends a constraint to the table. Unnamed constraints
+// are named with generateName, unnamed CHECK constraints that share a name
+// are numbered the way postgres numbers them.
+func (tbl *Table) appendSQLConstraint(constraint Constraint) error {
+	if constraint.ConstraintName == "" {
+		if constraint.ConstraintType == CHECK {
+			var columns []string
+			if len(constraint.Columns) == 1 {
+				columns = constraint.Columns
+			}
+			name := generateName(CHECK, tbl.TableName, columns...)
+			constraint.ConstraintName = name
+			for i := 1; tbl.CachedConstraintPosition(constraint.ConstraintName) >= 0; i++ {
+				constraint.ConstraintName = fmt.Sprintf("%s%d", name, i)
+			}
+			constraint.Columns = nil
+		} else {
+			constraint.ConstraintName = generateName(constraint.ConstraintType, tbl.TableName, constraint.Columns...)
+		}
+	} else if constraint.ConstraintType == CHECK {
+		constraint.Columns = nil
+	}
+	if tbl.CachedConstraintPosition(constraint.ConstraintName) >= 0 {
+		return fmt.Errorf("constraint %s already exists", constraint.ConstraintName)
+	}
+	tbl.AppendConstraint(constraint)
+	return nil
+}
+
+// loadReferences loads the referenced table and columns and the ON
+// UPDATE/DELETE actions of a foreign key, after the REFERENCES keyword. If no
+// columns are referenced, the referenced columns have the same names as the
+// constraint columns.
+func (constraint *Constraint) loadReferences(sc *sqlScanner) error {
+	var err error
+	constraint.ReferencesSchema, constraint.ReferencesTable, err = sc.qualifiedName()
+	if err != nil {
+		return err
+	}
+	constraint.ReferencesColumns = constraint.Columns
+	if sc.peek() == "(" {
+		constraint.ReferencesColumns, err = sc.nameGroup()
+		if err != nil {
+			return err
+		}
+	}
+	for {
+		switch {
+		case sc.keyword("MATCH", "FULL"):
+			constraint.MatchOption = "MATCH FULL"
+		case sc.keyword("MATCH", "PARTIAL"):
+			constraint.MatchOption = "MATCH PARTIAL"
+		case sc.keyword("MATCH", "SIMPLE"):
+			constraint.MatchOption = ""
+		case sc.keyword("ON", "UPDATE"):
+			constraint.UpdateRule, err = sc.referentialAction()
+			if err != nil {
+				return fmt.Errorf("ON UPDATE: %w", err)
+			}
+		case sc.keyword("ON", "DELETE"):
+			constraint.DeleteRule, err = sc.referentialAction()
+			if err != nil {
+				return fmt.Errorf("ON DELETE: %w", err)
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (sc *sqlScanner) referentialAction() (string, error) {
+	switch {
+	case sc.keyword("CASCADE"):
+		return CASCADE, nil
+	case sc.keyword("RESTRICT"):
+		return RESTRICT, nil
+	case sc.keyword("NO", "ACTION"):
+		return NO_ACTION, nil
+	case sc.keyword("SET", "NULL"):
+		return SET_NULL, nil
+	case sc.keyword("SET", "DEFAULT"):
+		return SET_DEFAULT, nil
+	}
+	return "", fmt.Errorf("unknown action %q", sc.peek())
+}
+
+// loadConstraintTiming loads the [NOT] DEFERRABLE and INITIALLY
+// DEFERRED/IMMEDIATE clauses of a constraint. MySQL accepts but ignores them.
+func (constraint *Constraint) loadConstraintTiming(sc *sqlScanner) {
+	for {
+		switch {
+		case sc.keyword("DEFERRABLE"):
+			constraint.IsDeferrable = true
+		case sc.keyword("NOT", "DEFERRABLE"):
+			constraint.IsDeferrable = false
+		case sc.keyword("INITIALLY", "DEFERRED"):
+			constraint.IsInitiallyDeferred = true
+		case sc.keyword("INITIALLY", "IMMEDIATE"):
+			constraint.IsInitiallyDeferred = false
+		default:
+			if sc.dialect == sq.DialectMySQL {
+				constraint.IsDeferrable = false
+				constraint.IsInitiallyDeferred = false
+			}
+			return
+		}
+	}
+}
+
+// loadExclusion loads a postgres exclusion constraint, after the EXCLUDE
+// keyword.
+func (constraint *Constraint) loadExclusion(sc *sqlScanner) error {
+	if sc.dialect != sq.DialectPostgres {
+		return fmt.Errorf("%s does not support exclusion constraints", sc.dialect)
+	}
+	if sc.keyword("USING") {
+		constraint.ExclusionIndexType = strings.ToUpper(sc.pop())
+	}
+	elements, ok := sc.group()
+	if !ok {
+		return fmt.Errorf("expected (element WITH operator, ...)")
+	}
+	items, err := (&sqlScanner{dialect: sc.dialect, s: elements}).list()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		itemSc := &sqlScanner{dialect: sc.dialect, s: item}
+		element := itemSc.rawUntil("WITH")
+		if !itemSc.keyword("WITH") {
+			return fmt.Errorf("%s: expected WITH operator", element)
+		}
+		column, expr := indexElement(sc.dialect, element)
+		constraint.Columns = append(constraint.Columns, column)
+		constraint.Exprs = append(constraint.Exprs, expr)
+		constraint.ExclusionOperators = append(constraint.ExclusionOperators, itemSc.rest())
+	}
+	if sc.keyword("WHERE") {
+		predicate, ok := sc.group()
+		if !ok {
+			return fmt.Errorf("WHERE: expected (predicate)")
+		}
+		constraint.Predicate = strings.TrimSpace(predicate)
+	}
+	return nil
+}
+
+// indexElements splits the elements of an index into columns and
+// expressions. Each element is either a column, leaving its expression
+// empty, or an expression, leaving its column empty.
+func indexElements(dialect, elements string) (columns, exprs []string, err error) {
+	items, err := (&sqlScanner{dialect: dialect, s: elements}).list()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range items {
+		column, expr := indexElement(dialect, item)
+		columns = append(columns, column)
+		exprs = append(exprs, expr)
+	}
+	return columns, exprs, nil
+}
+
+func indexElement(dialect, element string) (column, expr string) {
+	sc := &sqlScanner{dialect: dialect, s: element}
+	if name, err := sc.name(); err == nil && sc.eof() {
+		return name, ""
+	}
+	return "", strings.TrimSpace(element)
+}
+
+// sqlScanner reads the tokens of a SQL statement. A token is a word, a quoted
+// string or identifier, a postgres dollar-quoted string or a single
+// character. Whitespace and comments between tokens are skipped.
+type sqlScanner struct {
+	dialect string
+	s       string
+	i       int
+}
+
+func (sc *sqlScanner) skipSpace() {
+	for sc.i < len(sc.s) {
+		switch c := sc.s[sc.i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f':
+			sc.i++
+		case strings.HasPrefix(sc.s[sc.i:], "--") || (c == '#' && sc.dialect == sq.DialectMySQL):
+			if n := strings.IndexByte(sc.s[sc.i:], '\n'); n >= 0 {
+				sc.i += n + 1
+			} else {
+				sc.i = len(sc.s)
+			}
+		case strings.HasPrefix(sc.s[sc.i:], "/*"):
+			if n := strings.Index(sc.s[sc.i+2:], "*/"); n >= 0 {
+				sc.i += n + 4
+			} else {
+				sc.i = len(sc.s)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func isWord(token string) bool {
+	return token != "" && token[0] != '$' && isWordChar(token[0])
+}
+
+// pop returns the next token and advances past it, or returns an empty string
+// if there are no more tokens.
+func (sc *sqlScanner) pop() string {
+	sc.skipSpace()
+	start := sc.i
+	if start >= len(sc.s) {
+		return ""
+	}
+	switch c := sc.s[start]; {
+	case c == '\'' || c == '"' || c == '`':
+		for sc.i = start + 1; sc.i < len(sc.s); sc.i++ {
+			if sc.s[sc.i] == '\\' && c == '\'' && sc.dialect == sq.DialectMySQL {
+				sc.i++
+				continue
+			}
+			if sc.s[sc.i] == c {
+				if sc.i+1 < len(sc.s) && sc.s[sc.i+1] == c {
+					sc.i++
+					continue
+				}
+				break
+			}
+		}
+		sc.i++
+	case c == '$' && sc.dialect == sq.DialectPostgres:
+		tagEnd := start + 1
+		for tagEnd < len(sc.s) && sc.s[tagEnd] != '$' && isWordChar(sc.s[tagEnd]) {
+			tagEnd++
+		}
+		if tagEnd >= len(sc.s) || sc.s[tagEnd] != '$' {
+			sc.i = tagEnd
+			break
+		}
+		tag := sc.s[start : tagEnd+1]
+		if n := strings.Index(sc.s[tagEnd+1:], tag); n >= 0 {
+			sc.i = tagEnd + 1 + n + len(tag)
+		} else {
+			sc.i = len(sc.s)
+		}
+	case isWordChar(c):
+		for sc.i < len(sc.s) && isWordChar(sc.s[sc.i]) {
+			sc.i++
+		}
+	default:
+		sc.i++
+	}
+	if sc.i > len(sc.s) {
+		sc.i = len(sc.s)
+	}
+	return sc.s[start:sc.i]
+}
+
+// peek returns the next token without advancing past it.
+func (sc *sqlScanner) peek() string {
+	i := sc.i
+	token := sc.pop()
+	sc.i = i
+	return token
+}
+
+func (sc *sqlScanner) eof() bool {
+	sc.skipSpace()
+	return sc.i >= len(sc.s)
+}
+
+// keyword advances past the next tokens if they are the keywords words, case
+// insensitively. Otherwise it does not advance at all.
+func (sc *sqlScanner) keyword(words ...string) bool {
+	i := sc.i
+	for _, word := range words {
+		if !strings.EqualFold(sc.pop(), word) {
+			sc.i = i
+			return false
+		}
+	}
+	return true
+}
+
+// group advances past the next bracketed group and returns what is inside the
+// brackets. If the next token is not an opening bracket it returns false.
+func (sc *sqlScanner) group() (inner string, ok bool) {
+	if sc.peek() != "(" {
+		return "", false
+	}
+	sc.pop()
+	start := sc.i
+	for depth := 1; ; {
+		switch sc.pop() {
+		case "":
+			return sc.s[start:], true
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return sc.s[start : sc.i-1], true
+			}
+		}
+	}
+}
+
+// rawUntil advances up to the next comma, closing bracket or one of the
+// keywords stopWords, and returns the raw text it advanced past.
+func (sc *sqlScanner) rawUntil(stopWords ...string) string {
+	sc.skipSpace()
+	start, end := sc.i, sc.i
+	for {
+		token := sc.peek()
+		if token == "" || token == "," || token == ")" || token == ";" {
+			break
+		}
+		if isWord(token) {
+			var isStopWord bool
+			for _, stopWord := range stopWords {
+				if strings.EqualFold(token, stopWord) {
+					isStopWord = true
+					break
+				}
+			}
+			if isStopWord {
+				break
+			}
+		}
+		if token == "(" {
+			sc.group()
+		} else {
+			sc.pop()
+		}
+		end = sc.i
+	}
+	return strings.TrimSpace(sc.s[start:end])
+}
+
+// rest advances to the end and returns the rest of the text.
+func (sc *sqlScanner) rest() string {
+	sc.skipSpace()
+	rest := strings.TrimSpace(sc.s[sc.i:])
+	sc.i = len(sc.s)
+	return rest
+}
+
+// list returns the comma separated items of the text.
+func (sc *sqlScanner) list() ([]string, error) {
+	var items []string
+	for !sc.eof() {
+		items = append(items, sc.rawUntil())
+		if token := sc.pop(); token != "," && token != "" {
+			return nil, fmt.Errorf("unexpected %q", token)
+		}
+	}
+	return items, nil
+}
+
+// name returns the next token as an identifier. Quoted identifiers are
+// unquoted, postgres folds unquoted identifiers to lowercase.
+func (sc *sqlScanner) name() (string, error) {
+	token := sc.pop()
+	if token == "" {
+		return "", fmt.Errorf("expected a name")
+	}
+	if token[0] == '"' || token[0] == '`' || isWord(token) {
+		return unquoteIdentifier(sc.dialect, token), nil
+	}
+	return "", fmt.Errorf("expected a name, got %q", token)
+}
+
+// qualifiedName returns the next name, which may be qualified by a schema.
+func (sc *sqlScanner) qualifiedName() (schemaName, name string, err error) {
+	name, err = sc.name()
+	if err != nil {
+		return "", "", err
+	}
+	if sc.i < len(sc.s) && sc.s[sc.i] == '.' {
+		sc.i++
+		schemaName = name
+		name, err = sc.name()
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return schemaName, name, nil
+}
+
+// integer returns the next token, which may be signed, as an integer.
+func (sc *sqlScanner) integer() (int64, error) {
+	token := sc.pop()
+	if token == "-" || token == "+" {
+		token += sc.pop()
+	}
+	n, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected an integer, got %q", token)
+	}
+	return n, nil
+}
+
+// names returns the comma separated names of the text.
+func (sc *sqlScanner) names() ([]string, error) {
+	var names []string
+	for !sc.eof() {
+		name, err := sc.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if token := sc.pop(); token != "," && token != "" {
+			return nil, fmt.Errorf("unexpected %q after %s", token, name)
+		}
+	}
+	return names, nil
+}
+
+// nameGroup returns the names inside the next bracketed group.
+func (sc *sqlScanner) nameGroup() ([]string, error) {
+	inner, ok := sc.group()
+	if !ok {
+		return nil, fmt.Errorf("expected (column, ...)")
+	}
+	names, err := (&sqlScanner{dialect: sc.dialect, s: inner}).names()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("expected (column, ...)")
+	}
+	return names, nil
+}
+
+func unquoteIdentifier(dialect, identifier string) string {
+	if len(identifier) >= 2 {
+		switch quote := identifier[0]; quote {
+		case '"', '`':
+			if identifier[len(identifier)-1] == quote {
+				return strings.ReplaceAll(identifier[1:len(identifier)-1], string([]byte{quote, quote}), string(quote))
+			}
+		}
+	}
+	if dialect == sq.DialectPostgres {
+		return strings.ToLower(identifier)
+	}
+	return identifier
+}
+
+func unquoteString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '\'' || s[len(s)-1] != '\'' {
+		return "", fmt.Errorf("expected a string, got %q", s)
+	}
+	s = strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	return strings.ReplaceAll(s, `\'`, "'"), nil
+}
@@ -0,0 +1,416 @@
+package ddl
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_WithSQLFiles(t *testing.T) {
+	for _, dialect := range []string{sq.DialectSQLite, sq.DialectPostgres, sq.DialectMySQL} {
+		dialect := dialect
+		t.Run(dialect+" sakila", func(t *testing.T) {
+			t.Parallel()
+			gotDBMetadata, err := NewDatabaseMetadata(dialect, WithSQLFiles(embeddedFiles, "testdata/"+dialect+"_sakila_up.sql"))
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			// the up files are generated from the sakila tables, so loading
+			// them back should leave nothing to migrate
+			wantDBMetadata, err := NewDatabaseMetadata(dialect, WithTables(
+				NEW_ACTOR(""),
+				NEW_ADDRESS(""),
+				NEW_CATEGORY(""),
+				NEW_CITY(""),
+				NEW_COUNTRY(""),
+				NEW_CUSTOMER(""),
+				NEW_FILM(""),
+				NEW_FILM_ACTOR(""),
+				NEW_FILM_ACTOR_REVIEW(""),
+				NEW_FILM_CATEGORY(""),
+				NEW_FILM_TEXT(""),
+				NEW_INVENTORY(""),
+				NEW_LANGUAGE(""),
+				NEW_PAYMENT(""),
+				NEW_RENTAL(""),
+				NEW_STAFF(""),
+				NEW_STORE(""),
+			), WithDDLViews(
+				NEW_ACTOR_INFO(""),
+				NEW_CUSTOMER_LIST(""),
+				NEW_FILM_LIST(""),
+				NEW_FULL_ADDRESS(""),
+				NEW_NICER_BUT_SLOWER_FILM_LIST(""),
+				NEW_SALES_BY_FILM_CATEGORY(""),
+				NEW_SALES_BY_STORE(""),
+				NEW_STAFF_LIST(""),
+			))
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			m, err := Migrate(CreateMissing|UpdateExisting, gotDBMetadata, wantDBMetadata)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			buf := &bytes.Buffer{}
+			err = m.WriteSQL(buf)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(buf.String(), ""); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		})
+	}
+
+	type TT struct {
+		dialect string
+		fsys    fstest.MapFS
+		wantSQL string
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		filenames := make([]string, 0, len(tt.fsys))
+		for _, filename := range []string{"1_indexes.sql", "2_tables.sql"} {
+			if _, ok := tt.fsys[filename]; ok {
+				filenames = append(filenames, filename)
+			}
+		}
+		dbMetadata, err := NewDatabaseMetadata(tt.dialect, WithSQLFiles(tt.fsys, filenames...))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		m, err := Migrate(CreateMissing, DatabaseMetadata{}, dbMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("postgres", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.fsys = fstest.MapFS{
+			"1_indexes.sql": &fstest.MapFile{Data: []byte(`
+-- indexes may come before the tables they index
+CREATE INDEX ON "Book" (title, (lower(author)));
+CREATE UNIQUE INDEX book_isbn_idx ON "Book" USING btree (isbn) INCLUDE (title) WHERE isbn <> '';
+`)},
+			"2_tables.sql": &fstest.MapFile{Data: []byte(`
+CREATE TABLE Author (
+    author_id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY
+    ,name TEXT NOT NULL CHECK (name <> '')
+);
+/* a comment; with a semicolon */
+CREATE TABLE IF NOT EXISTS "Book" (
+    book_id BIGINT GENERATED BY DEFAULT AS IDENTITY
+    ,title TEXT NOT NULL DEFAULT 'untitled; really' COLLATE "C"
+    ,author TEXT
+    ,author_id INT REFERENCES author ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED
+    ,isbn TEXT UNIQUE
+    ,price NUMERIC(6,2) CHECK (price > 0)
+    ,created_at TIMESTAMPTZ DEFAULT NOW()
+
+    ,PRIMARY KEY (book_id)
+    ,CHECK (price < 1000)
+);
+INSERT INTO author (name) VALUES ('ignored');
+`)},
+		}
+		tt.wantSQL = `CREATE TABLE IF NOT EXISTS author (` +
+			`
+    author_id INT GENERATED ALWAYS AS IDENTITY
+    ,name TEXT NOT NULL
+
+    ,CONSTRAINT author_author_id_pkey PRIMARY KEY (author_id)
+    ,CONSTRAINT author_name_check CHECK (name <> '')
+);
+
+CREATE TABLE IF NOT EXISTS "Book" (
+    book_id BIGINT GENERATED BY DEFAULT AS IDENTITY
+    ,title TEXT NOT NULL DEFAULT 'untitled; really' COLLATE "C"
+    ,author TEXT
+    ,author_id INT
+    ,isbn TEXT
+    ,price NUMERIC(6,2)
+    ,created_at TIMESTAMPTZ DEFAULT NOW()
+
+    ,CONSTRAINT "Book_isbn_key" UNIQUE (isbn)
+    ,CONSTRAINT "Book_price_check" CHECK (price > 0)
+    ,CONSTRAINT "Book_book_id_pkey" PRIMARY KEY (book_id)
+    ,CONSTRAINT "Book_check" CHECK (price < 1000)
+);
+
+CREATE INDEX IF NOT EXISTS "Book_title__idx" ON "Book" (title, (lower(author)));
+
+CREATE UNIQUE INDEX IF NOT EXISTS book_isbn_idx ON "Book" (isbn) INCLUDE (title) WHERE isbn <> '';
+
+ALTER TABLE IF EXISTS "Book"
+    ADD CONSTRAINT "Book_author_id_fkey" FOREIGN KEY (author_id) REFERENCES author (author_id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED;`
+		assert(t, tt)
+	})
+
+	t.Run("postgres types, domains and sequences", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.fsys = fstest.MapFS{
+			"2_tables.sql": &fstest.MapFile{Data: []byte(`
+CREATE TYPE mood AS ENUM ('happy', 'it''s complicated');
+CREATE TYPE audit.address AS (street TEXT COLLATE "C", postal_code VARCHAR(10));
+CREATE DOMAIN email AS TEXT NOT NULL CHECK (VALUE LIKE '%@%') CONSTRAINT email_length_check CHECK (LENGTH(VALUE) < 255);
+CREATE DOMAIN positive_amount NUMERIC(10,2) DEFAULT 1 CHECK (VALUE > 0);
+CREATE SEQUENCE IF NOT EXISTS invoice_seq AS BIGINT INCREMENT BY 2 MINVALUE -10 NO MAXVALUE START WITH -10 CACHE 20 CYCLE OWNED BY NONE;
+CREATE TABLE person (
+    person_id INT PRIMARY KEY
+    ,email email
+    ,mood mood
+);
+`)},
+		}
+		tt.wantSQL = `CREATE SCHEMA IF NOT EXISTS audit;
+
+CREATE TYPE mood AS ENUM ('happy', 'it''s complicated');
+
+CREATE SEQUENCE IF NOT EXISTS invoice_seq AS BIGINT INCREMENT BY 2 MINVALUE -10 START WITH -10 CYCLE;
+
+CREATE DOMAIN email AS TEXT NOT NULL CONSTRAINT email_check CHECK (VALUE LIKE '%@%') CONSTRAINT email_length_check CHECK (LENGTH(VALUE) < 255);
+
+CREATE DOMAIN positive_amount AS NUMERIC(10,2) DEFAULT 1 CONSTRAINT positive_amount_check CHECK (VALUE > 0);
+
+CREATE TYPE audit.address AS (street TEXT COLLATE "C", postal_code VARCHAR(10));
+
+CREATE TABLE IF NOT EXISTS person (
+    person_id INT
+    ,email email
+    ,mood mood
+
+    ,CONSTRAINT person_person_id_pkey PRIMARY KEY (person_id)
+);`
+		assert(t, tt)
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.fsys = fstest.MapFS{
+			"2_tables.sql": &fstest.MapFile{Data: []byte(`
+CREATE TABLE ` + "`book`" + ` (
+    book_id INT AUTO_INCREMENT
+    ,title VARCHAR(255) CHARACTER SET utf8mb4 NOT NULL COMMENT 'the book''s title'
+    ,isbn VARCHAR(13)
+    ,updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+    ,PRIMARY KEY (book_id)
+    ,UNIQUE KEY (isbn)
+    ,FULLTEXT INDEX book_title_idx (title)
+) ENGINE=InnoDB;
+
+DELIMITER $$
+CREATE TRIGGER book_before_insert_trg BEFORE INSERT ON book FOR EACH ROW BEGIN
+    IF NEW.title = '' THEN
+        SET NEW.title = 'untitled';
+    END IF;
+END$$
+DELIMITER ;
+`)},
+		}
+		tt.wantSQL = "CREATE TABLE IF NOT EXISTS book (" +
+			`
+    book_id INT AUTO_INCREMENT
+    ,title VARCHAR(255) NOT NULL
+    ,isbn VARCHAR(13)
+    ,updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+
+    ,PRIMARY KEY (book_id)
+    ,UNIQUE INDEX book_isbn_idx (isbn)
+    ,FULLTEXT INDEX book_title_idx (title)
+);
+
+-- DELIMITER ;;
+
+CREATE TRIGGER book_before_insert_trg BEFORE INSERT ON book FOR EACH ROW BEGIN
+    IF NEW.title = '' THEN
+        SET NEW.title = 'untitled';
+    END IF;
+END; -- ;;
+
+-- DELIMITER ;`
+		assert(t, tt)
+	})
+}
+
+func Test_WithSQLFilesErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		dialect     string
+		sql         string
+	}{{
+		description: "duplicate table",
+		dialect:     sq.DialectSQLite,
+		sql:         "CREATE TABLE t (id INT); CREATE TABLE t (id INT);",
+	}, {
+		description: "index on missing table",
+		dialect:     sq.DialectSQLite,
+		sql:         "CREATE INDEX t_id_idx ON t (id);",
+	}, {
+		description: "unsupported alter table action",
+		dialect:     sq.DialectPostgres,
+		sql:         "CREATE TABLE t (id INT); ALTER TABLE t DROP COLUMN id;",
+	}, {
+		description: "unsupported column clause",
+		dialect:     sq.DialectSQLite,
+		sql:         "CREATE TABLE t (id INT PRIMARY KEY ON CONFLICT REPLACE);",
+	}, {
+		description: "unsupported type",
+		dialect:     sq.DialectPostgres,
+		sql:         "CREATE TYPE floatrange AS RANGE (subtype = float8);",
+	}, {
+		description: "duplicate enum",
+		dialect:     sq.DialectPostgres,
+		sql:         "CREATE TYPE mood AS ENUM ('happy'); CREATE TYPE mood AS ENUM ('sad');",
+	}, {
+		description: "unsupported domain clause",
+		dialect:     sq.DialectPostgres,
+		sql:         "CREATE DOMAIN email AS TEXT CHECK (VALUE LIKE '%@%') NO INHERIT;",
+	}, {
+		description: "sequence with a non-integer option",
+		dialect:     sq.DialectPostgres,
+		sql:         "CREATE SEQUENCE s START WITH x;",
+	}, {
+		description: "sqlite domain",
+		dialect:     sq.DialectSQLite,
+		sql:         "CREATE DOMAIN email AS TEXT;",
+	}, {
+		description: "create table as select",
+		dialect:     sq.DialectPostgres,
+		sql:         "CREATE TABLE t AS SELECT 1 AS id;",
+	}}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.description, func(t *testing.T) {
+			t.Parallel()
+			fsys := fstest.MapFS{"schema.sql": &fstest.MapFile{Data: []byte(tt.sql)}}
+			_, err := NewDatabaseMetadata(tt.dialect, WithSQLFiles(fsys, "schema.sql"))
+			if err == nil {
+				t.Fatal(testutil.Callers(), "expected error but got nil")
+			}
+		})
+	}
+}
+
+func Test_splitSQLStatements(t *testing.T) {
+	type TT struct {
+		dialect   string
+		src       string
+		wantStmts []string
+		wantLines []int
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotStmts, gotLines := splitSQLStatements(tt.dialect, tt.src)
+		if diff := testutil.Diff(gotStmts, tt.wantStmts); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotLines, tt.wantLines); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("strings and comments", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectSQLite
+		tt.src = "-- a comment;\nSELECT 'a;b', \"c;d\";\n/* e;\nf */ SELECT 1; SELECT 2"
+		tt.wantStmts = []string{`SELECT 'a;b', "c;d"`, "SELECT 1", "SELECT 2"}
+		tt.wantLines = []int{2, 4, 4}
+		assert(t, tt)
+	})
+
+	t.Run("sqlite trigger", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectSQLite
+		tt.src = "CREATE TRIGGER t AFTER UPDATE ON a BEGIN\n" +
+			"    UPDATE a SET x = CASE WHEN y THEN 1 ELSE 2 END;\n" +
+			"    UPDATE b SET x = 1;\n" +
+			"END;\n" +
+			"CREATE TABLE b (begin_date DATE);"
+		tt.wantStmts = []string{
+			"CREATE TRIGGER t AFTER UPDATE ON a BEGIN\n" +
+				"    UPDATE a SET x = CASE WHEN y THEN 1 ELSE 2 END;\n" +
+				"    UPDATE b SET x = 1;\n" +
+				"END",
+			"CREATE TABLE b (begin_date DATE)",
+		}
+		tt.wantLines = []int{1, 5}
+		assert(t, tt)
+	})
+
+	t.Run("postgres dollar quotes", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectPostgres
+		tt.src = "CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN\n" +
+			"    RETURN NEW;\n" +
+			"END; $body$ LANGUAGE plpgsql;\n" +
+			"BEGIN; SELECT 1; COMMIT;"
+		tt.wantStmts = []string{
+			"CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN\n" +
+				"    RETURN NEW;\n" +
+				"END; $body$ LANGUAGE plpgsql",
+			"BEGIN",
+			"SELECT 1",
+			"COMMIT",
+		}
+		tt.wantLines = []int{1, 4, 4, 4}
+		assert(t, tt)
+	})
+
+	t.Run("mysql delimiter", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.src = "DELIMITER //\n" +
+			"CREATE PROCEDURE p() BEGIN SELECT 'it\\'s'; END//\n" +
+			"DELIMITER ;\n" +
+			"CREATE TRIGGER t AFTER INSERT ON a FOR EACH ROW BEGIN\n" +
+			"    IF NEW.x THEN DELETE FROM b; END IF;\n" +
+			"END; -- ;;\n"
+		tt.wantStmts = []string{
+			"CREATE PROCEDURE p() BEGIN SELECT 'it\\'s'; END",
+			"CREATE TRIGGER t AFTER INSERT ON a FOR EACH ROW BEGIN\n" +
+				"    IF NEW.x THEN DELETE FROM b; END IF;\n" +
+				"END",
+		}
+		tt.wantLines = []int{2, 4}
+		assert(t, tt)
+	})
+
+	t.Run("mysql end case", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = sq.DialectMySQL
+		tt.src = "DELIMITER ;;\n" +
+			"CREATE PROCEDURE p(x INT) BEGIN CASE x WHEN 1 THEN SELECT 1; ELSE SELECT 2; END CASE; END;;\n" +
+			"DELIMITER ;\n" +
+			"CREATE TABLE t (id INT);"
+		tt.wantStmts = []string{
+			"CREATE PROCEDURE p(x INT) BEGIN CASE x WHEN 1 THEN SELECT 1; ELSE SELECT 2; END CASE; END",
+			"CREATE TABLE t (id INT)",
+		}
+		tt.wantLines = []int{2, 4}
+		assert(t, tt)
+	})
+}
@@ -0,0 +1,558 @@
+package ddl
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bokwoon95/sq"
+)
+
+type WriteStructOptions struct {
+	// PackageName is the package name of the generated file. Defaults to
+	// "tables".
+	PackageName string
+}
+
+type structField struct {
+	name      string
+	fieldType string
+	column    Column
+}
+
+type tableStruct struct {
+	name   string
+	table  Table
+	fields []structField
+	// columnFields maps column names to their struct field name.
+	columnFields map[string]string
+}
+
+// WriteStructs writes Go source code for the tables in the DatabaseMetadata to
+// w. Each table gets a struct with one field per column and ddl struct tags
+// for the column definitions, a NEW_XXX constructor and a DDL method holding
+// the table's constraints, indexes and triggers.
+func (dbm *DatabaseMetadata) WriteStructs(w io.Writer, opts *WriteStructOptions) error {
+	packageName := "tables"
+	if opts != nil && opts.PackageName != "" {
+		packageName = opts.PackageName
+	}
+	var structs []tableStruct
+	structNames := make(map[string]bool)
+	for _, schema := range dbm.Schemas {
+		if schema.Ignore {
+			continue
+		}
+		for _, table := range schema.Tables {
+			if table.Ignore {
+				continue
+			}
+			name := exportedIdentifier(table.TableName)
+			if table.TableSchema != "" && table.TableSchema != dbm.CurrentSchema {
+				name = exportedIdentifier(table.TableSchema) + "_" + name
+			}
+			for structNames[name] {
+				name += "_"
+			}
+			structNames[name] = true
+			tblStruct := tableStruct{
+				name:         name,
+				table:        table,
+				columnFields: make(map[string]string),
+			}
+			fieldNames := map[string]bool{"TableInfo": true}
+			for _, column := range table.Columns {
+				if column.Ignore {
+					continue
+				}
+				fieldName := exportedIdentifier(column.ColumnName)
+				for fieldNames[fieldName] {
+					fieldName += "_"
+				}
+				fieldNames[fieldName] = true
+				tblStruct.columnFields[column.ColumnName] = fieldName
+				tblStruct.fields = append(tblStruct.fields, structField{
+					name:      fieldName,
+					fieldType: fieldTypeForColumn(dbm.Dialect, column),
+					column:    column,
+				})
+			}
+			structs = append(structs, tblStruct)
+		}
+	}
+	structsByTable := make(map[[2]string]tableStruct)
+	for _, tblStruct := range structs {
+		structsByTable[[2]string{tblStruct.table.TableSchema, tblStruct.table.TableName}] = tblStruct
+	}
+	buf := bufpool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufpool.Put(buf)
+	}()
+	buf.WriteString("// Code generated by ddl.WriteStructs. DO NOT EDIT.\n\n")
+	buf.WriteString("package " + packageName + "\n\n")
+	buf.WriteString("import (\n\t\"github.com/bokwoon95/sq\"\n\t\"github.com/bokwoon95/sq/ddl\"\n)\n")
+	for _, tblStruct := range structs {
+		err := writeTableStruct(buf, dbm.Dialect, dbm.CurrentSchema, tblStruct, structsByTable)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tblStruct.table.TableName, err)
+		}
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated code: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func writeTableStruct(buf *bytes.Buffer, dialect, currentSchema string, tblStruct tableStruct, structsByTable map[[2]string]tableStruct) error {
+	tbl := tblStruct.table
+	// struct
+	buf.WriteString("\ntype " + tblStruct.name + " struct {\n\tsq.TableInfo\n")
+	var columnConfigs []structField
+	for _, field := range tblStruct.fields {
+		buf.WriteString("\t" + field.name + " sq." + field.fieldType)
+		tag, ok := columnTag(dialect, field)
+		if !ok {
+			columnConfigs = append(columnConfigs, field)
+		} else if tag != "" {
+			buf.WriteString(" `ddl:" + strconv.Quote(tag) + "`")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	// constructor
+	tableSchema := tbl.TableSchema
+	if tableSchema == currentSchema {
+		tableSchema = ""
+	}
+	buf.WriteString("\nfunc NEW_" + tblStruct.name + "(alias string) " + tblStruct.name + " {\n")
+	buf.WriteString("\tvar tbl " + tblStruct.name + "\n")
+	buf.WriteString("\ttbl.TableInfo = sq.TableInfo{")
+	if tableSchema != "" {
+		buf.WriteString("TableSchema: " + strconv.Quote(tableSchema) + ", ")
+	}
+	buf.WriteString("TableName: " + strconv.Quote(tbl.TableName) + ", TableAlias: alias}\n")
+	for _, field := range tblStruct.fields {
+		constructor := "New" + field.fieldType
+		if field.fieldType == "BinaryField" {
+			constructor = "NewBlobField"
+		}
+		buf.WriteString("\ttbl." + field.name + " = sq." + constructor + "(" + strconv.Quote(field.column.ColumnName) + ", tbl.TableInfo)\n")
+	}
+	buf.WriteString("\treturn tbl\n}\n")
+	// DDL
+	buf.WriteString("\nfunc (tbl " + tblStruct.name + ") DDL(dialect string, t *ddl.T) {\n")
+	fieldList := func(columns []string) (string, error) {
+		var b strings.Builder
+		for _, column := range columns {
+			fieldName, ok := tblStruct.columnFields[column]
+			if !ok {
+				return "", fmt.Errorf("no such column %s", column)
+			}
+			b.WriteString(", tbl." + fieldName)
+		}
+		return b.String(), nil
+	}
+	if tbl.VirtualTable != "" {
+		buf.WriteString("\tt.VirtualTable(" + strconv.Quote(tbl.VirtualTable))
+		for _, arg := range tbl.VirtualTableArgs {
+			if _, ok := tblStruct.columnFields[arg]; ok && strings.EqualFold(tbl.VirtualTable, "FTS5") {
+				// LoadTable adds the FTS5 columns by itself.
+				continue
+			}
+			buf.WriteString(", " + strconv.Quote(arg))
+		}
+		buf.WriteString(")\n")
+	}
+	for _, field := range columnConfigs {
+		column := field.column
+		buf.WriteString("\tt.Column(tbl." + field.name + ").Config(func(c *ddl.Column) {\n")
+		buf.WriteString("\t\tc.ColumnType = " + strconv.Quote(column.ColumnType) + "\n")
+		if column.IsNotNull {
+			buf.WriteString("\t\tc.IsNotNull = true\n")
+		}
+		if column.ColumnDefault != "" {
+			buf.WriteString("\t\tc.ColumnDefault = " + strconv.Quote(column.ColumnDefault) + "\n")
+		}
+		if column.Identity != "" {
+			buf.WriteString("\t\tc.Identity = " + strconv.Quote(column.Identity) + "\n")
+		}
+		if column.IsAutoincrement {
+			buf.WriteString("\t\tc.IsAutoincrement = true\n")
+		}
+		if column.OnUpdateCurrentTimestamp {
+			buf.WriteString("\t\tc.OnUpdateCurrentTimestamp = true\n")
+		}
+		if column.GeneratedExpr != "" {
+			buf.WriteString("\t\tc.GeneratedExpr = " + strconv.Quote(column.GeneratedExpr) + "\n")
+		}
+		if column.GeneratedExprStored {
+			buf.WriteString("\t\tc.GeneratedExprStored = true\n")
+		}
+		if column.CollationName != "" {
+			buf.WriteString("\t\tc.CollationName = " + strconv.Quote(column.CollationName) + "\n")
+		}
+		buf.WriteString("\t})\n")
+	}
+	// Referenced tables are declared once at the top of the DDL method.
+	var refStructs []tableStruct
+	refNames := make(map[string]bool)
+	for _, constraint := range tbl.Constraints {
+		if constraint.Ignore || constraint.ConstraintType != FOREIGN_KEY {
+			continue
+		}
+		refSchema := constraint.ReferencesSchema
+		if refSchema == "" {
+			refSchema = tbl.TableSchema
+		}
+		refStruct, ok := structsByTable[[2]string{refSchema, constraint.ReferencesTable}]
+		if !ok || refStruct.name == tblStruct.name || refNames[refStruct.name] {
+			continue
+		}
+		refNames[refStruct.name] = true
+		refStructs = append(refStructs, refStruct)
+	}
+	sort.Slice(refStructs, func(i, j int) bool { return refStructs[i].name < refStructs[j].name })
+	for _, refStruct := range refStructs {
+		buf.WriteString("\tREF_" + refStruct.name + " := NEW_" + refStruct.name + "(\"\")\n")
+	}
+	for _, constraint := range tbl.Constraints {
+		if constraint.Ignore {
+			continue
+		}
+		name := strconv.Quote(constraint.ConstraintName)
+		switch constraint.ConstraintType {
+		case PRIMARY_KEY, UNIQUE, FOREIGN_KEY:
+			fields, err := fieldList(constraint.Columns)
+			if err != nil {
+				return fmt.Errorf("constraint %s: %w", constraint.ConstraintName, err)
+			}
+			// Unnamed constraints (sqlite) get their names generated by the
+			// unnamed variants.
+			method, args := "Name", name+fields
+			if constraint.ConstraintName == "" {
+				method, args = "", strings.TrimPrefix(fields, ", ")
+			}
+			switch constraint.ConstraintType {
+			case PRIMARY_KEY:
+				buf.WriteString("\tt." + method + "PrimaryKey(" + args + ")")
+			case UNIQUE:
+				buf.WriteString("\tt." + method + "Unique(" + args + ")")
+			case FOREIGN_KEY:
+				buf.WriteString("\tt." + method + "ForeignKey(" + args + ")")
+				refSchema := constraint.ReferencesSchema
+				if refSchema == "" {
+					refSchema = tbl.TableSchema
+				}
+				refStruct, ok := structsByTable[[2]string{refSchema, constraint.ReferencesTable}]
+				var refFields []string
+				if ok {
+					refVar := "REF_" + refStruct.name
+					if refStruct.name == tblStruct.name {
+						refVar = "tbl"
+					}
+					refFields = append(refFields, refVar)
+					for _, column := range constraint.ReferencesColumns {
+						fieldName, exists := refStruct.columnFields[column]
+						if !exists {
+							ok = false
+							break
+						}
+						refFields = append(refFields, refVar+"."+fieldName)
+					}
+				}
+				if ok {
+					buf.WriteString(".References(" + strings.Join(refFields, ", ") + ")")
+				}
+				if constraint.UpdateRule != "" && constraint.UpdateRule != NO_ACTION {
+					buf.WriteString(".OnUpdate(" + constantName(constraint.UpdateRule) + ")")
+				}
+				if constraint.DeleteRule != "" && constraint.DeleteRule != NO_ACTION {
+					buf.WriteString(".OnDelete(" + constantName(constraint.DeleteRule) + ")")
+				}
+				if constraint.IsInitiallyDeferred {
+					buf.WriteString(".InitiallyDeferred()")
+				} else if constraint.IsDeferrable {
+					buf.WriteString(".Deferrable()")
+				}
+				if !ok {
+					buf.WriteString(".Config(func(c *ddl.Constraint) {\n")
+					if constraint.ReferencesSchema != "" {
+						buf.WriteString("\t\tc.ReferencesSchema = " + strconv.Quote(constraint.ReferencesSchema) + "\n")
+					}
+					buf.WriteString("\t\tc.ReferencesTable = " + strconv.Quote(constraint.ReferencesTable) + "\n")
+					buf.WriteString("\t\tc.ReferencesColumns = " + stringSliceLiteral(constraint.ReferencesColumns) + "\n")
+					buf.WriteString("\t})")
+				}
+				buf.WriteString("\n")
+				continue
+			}
+			if constraint.IsInitiallyDeferred {
+				buf.WriteString(".InitiallyDeferred()")
+			} else if constraint.IsDeferrable {
+				buf.WriteString(".Deferrable()")
+			}
+			buf.WriteString("\n")
+		case CHECK:
+			buf.WriteString("\tt.Check(" + name + ", " + formatLiteral(constraint.CheckExpr) + ")\n")
+		case EXCLUDE:
+			buf.WriteString("\tt.NameExclude(" + name + ", " + strconv.Quote(constraint.ExclusionIndexType) + ", ddl.Exclusions{\n")
+			for i, column := range constraint.Columns {
+				field := "sq.Literal(" + strconv.Quote(constraint.Exprs[i]) + ")"
+				if column != "" {
+					fieldName, ok := tblStruct.columnFields[column]
+					if !ok {
+						return fmt.Errorf("constraint %s: no such column %s", constraint.ConstraintName, column)
+					}
+					field = "tbl." + fieldName
+				}
+				buf.WriteString("\t\t{Field: " + field + ", Operator: " + strconv.Quote(constraint.ExclusionOperators[i]) + "},\n")
+			}
+			buf.WriteString("\t})")
+			if constraint.Predicate != "" {
+				buf.WriteString(".Where(" + formatLiteral(constraint.Predicate) + ")")
+			}
+			buf.WriteString("\n")
+		default:
+			return fmt.Errorf("constraint %s: unknown constraint type %q", constraint.ConstraintName, constraint.ConstraintType)
+		}
+	}
+	for _, index := range tbl.Indexes {
+		if index.Ignore {
+			continue
+		}
+		buf.WriteString("\tt.NameIndex(" + strconv.Quote(index.IndexName))
+		for i, column := range index.Columns {
+			if column == "" {
+				buf.WriteString(", sq.Literal(" + strconv.Quote(index.Exprs[i]) + ")")
+				continue
+			}
+			fieldName, ok := tblStruct.columnFields[column]
+			if !ok {
+				return fmt.Errorf("index %s: no such column %s", index.IndexName, column)
+			}
+			buf.WriteString(", tbl." + fieldName)
+		}
+		buf.WriteString(")")
+		if index.IsUnique {
+			buf.WriteString(".Unique()")
+		}
+		if index.IndexType != "" && !strings.EqualFold(index.IndexType, "BTREE") {
+			buf.WriteString(".Using(" + strconv.Quote(index.IndexType) + ")")
+		}
+		if index.Predicate != "" {
+			buf.WriteString(".Where(" + formatLiteral(index.Predicate) + ")")
+		}
+		if len(index.IncludeColumns) > 0 {
+			fields, err := fieldList(index.IncludeColumns)
+			if err != nil {
+				return fmt.Errorf("index %s: %w", index.IndexName, err)
+			}
+			buf.WriteString(".Include(" + strings.TrimPrefix(fields, ", ") + ")")
+		}
+		buf.WriteString("\n")
+	}
+	for _, trigger := range tbl.Triggers {
+		if trigger.Ignore {
+			continue
+		}
+		buf.WriteString("\tt.Trigger(" + formatLiteral(trigger.SQL) + ")\n")
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// columnTag returns the ddl struct tag for the column. If the column cannot be
+// expressed as a struct tag, ok is false and the column should be configured
+// in the DDL method instead.
+func columnTag(dialect string, field structField) (tag string, ok bool) {
+	column := field.column
+	var modifiers []string
+	addModifier := func(name, value string) bool {
+		if value == "" {
+			modifiers = append(modifiers, name)
+			return true
+		}
+		if strings.ContainsAny(value, "{}`") {
+			return false
+		}
+		if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+			value = "{" + value + "}"
+		}
+		modifiers = append(modifiers, name+"="+value)
+		return true
+	}
+	if !strings.EqualFold(column.ColumnType, defaultColumnType(dialect, zeroField(field.fieldType))) {
+		if !addModifier(dialect+":type", column.ColumnType) {
+			return "", false
+		}
+	}
+	switch column.Identity {
+	case BY_DEFAULT_AS_IDENTITY:
+		addModifier("identity", "")
+	case ALWAYS_AS_IDENTITY:
+		addModifier("alwaysidentity", "")
+	}
+	if column.IsAutoincrement {
+		switch dialect {
+		case sq.DialectMySQL:
+			addModifier("auto_increment", "")
+		case sq.DialectSQLite:
+			addModifier("autoincrement", "")
+		}
+	}
+	if column.IsNotNull {
+		addModifier("notnull", "")
+	}
+	if column.ColumnDefault != "" {
+		if !addModifier("default", column.ColumnDefault) {
+			return "", false
+		}
+	}
+	if column.OnUpdateCurrentTimestamp {
+		addModifier("onupdatecurrenttimestamp", "")
+	}
+	if column.GeneratedExpr != "" {
+		name := "expr"
+		if column.GeneratedExprStored && dialect != sq.DialectPostgres {
+			name = "storedexpr"
+		}
+		if !addModifier(name, column.GeneratedExpr) {
+			return "", false
+		}
+	}
+	if column.CollationName != "" {
+		if !addModifier("collate", column.CollationName) {
+			return "", false
+		}
+	}
+	return strings.Join(modifiers, " "), true
+}
+
+func fieldTypeForColumn(dialect string, column Column) string {
+	columnType := strings.ToUpper(strings.TrimSpace(column.ColumnType))
+	if strings.HasSuffix(columnType, "]") || strings.HasSuffix(columnType, " ARRAY") {
+		return "CustomField"
+	}
+	if dialect == sq.DialectMySQL {
+		switch columnType {
+		case "BINARY(16)":
+			return "UUIDField"
+		case "TINYINT(1)":
+			return "BooleanField"
+		}
+	}
+	baseType := columnType
+	if i := strings.IndexAny(baseType, " ("); i >= 0 {
+		baseType = baseType[:i]
+	}
+	switch baseType {
+	case "UUID", "UNIQUEIDENTIFIER":
+		return "UUIDField"
+	case "BOOLEAN", "BOOL":
+		return "BooleanField"
+	case "INT", "INTEGER", "SMALLINT", "BIGINT", "TINYINT", "MEDIUMINT",
+		"INT2", "INT4", "INT8", "SERIAL", "SMALLSERIAL", "BIGSERIAL",
+		"NUMERIC", "DECIMAL", "REAL", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "YEAR":
+		return "NumberField"
+	case "CHAR", "CHARACTER", "VARCHAR", "NCHAR", "NVARCHAR", "TEXT", "TINYTEXT",
+		"MEDIUMTEXT", "LONGTEXT", "CLOB", "CITEXT", "ENUM":
+		return "StringField"
+	case "DATE", "TIME", "TIMETZ", "TIMESTAMP", "TIMESTAMPTZ", "DATETIME":
+		return "TimeField"
+	case "JSON", "JSONB":
+		return "JSONField"
+	case "BYTEA", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY":
+		return "BinaryField"
+	}
+	return "CustomField"
+}
+
+func zeroField(fieldType string) sq.Field {
+	switch fieldType {
+	case "BinaryField":
+		return sq.BinaryField{}
+	case "BooleanField":
+		return sq.BooleanField{}
+	case "JSONField":
+		return sq.JSONField{}
+	case "NumberField":
+		return sq.NumberField{}
+	case "StringField":
+		return sq.StringField{}
+	case "TimeField":
+		return sq.TimeField{}
+	case "UUIDField":
+		return sq.UUIDField{}
+	}
+	return sq.CustomField{}
+}
+
+// exportedIdentifier converts a table or column name into an uppercase Go
+// identifier e.g. film_actor -> FILM_ACTOR.
+func exportedIdentifier(name string) string {
+	var b strings.Builder
+	for _, char := range name {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_' {
+			b.WriteRune(unicode.ToUpper(char))
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	identifier := b.String()
+	if identifier == "" || unicode.IsDigit(rune(identifier[0])) || identifier[0] == '_' {
+		identifier = "X" + identifier
+	}
+	if token.Lookup(identifier).IsKeyword() {
+		identifier += "_"
+	}
+	return identifier
+}
+
+func constantName(rule string) string {
+	switch rule {
+	case RESTRICT:
+		return "ddl.RESTRICT"
+	case CASCADE:
+		return "ddl.CASCADE"
+	case NO_ACTION:
+		return "ddl.NO_ACTION"
+	case SET_NULL:
+		return "ddl.SET_NULL"
+	case SET_DEFAULT:
+		return "ddl.SET_DEFAULT"
+	}
+	return strconv.Quote(rule)
+}
+
+// formatLiteral returns a Go expression for the arguments of a format
+// function (Check, Where, Trigger) that reproduces s verbatim.
+func formatLiteral(s string) string {
+	if strings.ContainsAny(s, "{}") {
+		return `"{}", sq.Literal(` + stringLiteral(s) + `)`
+	}
+	return stringLiteral(s)
+}
+
+func stringLiteral(s string) string {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+func stringSliceLiteral(strs []string) string {
+	quoted := make([]string, len(strs))
+	for i, str := range strs {
+		quoted[i] = strconv.Quote(str)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
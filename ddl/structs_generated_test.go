@@ -0,0 +1,63 @@
+// Code generated by ddl.WriteStructs. DO NOT EDIT.
+
+package ddl_test
+
+import (
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/ddl"
+)
+
+type AUTHOR struct {
+	sq.TableInfo
+	AUTHOR_ID  sq.NumberField `ddl:"sqlite:type=INTEGER"`
+	NAME       sq.StringField `ddl:"notnull"`
+	EMAIL      sq.StringField
+	DATA       sq.JSONField
+	CREATED_AT sq.TimeField `ddl:"notnull default=CURRENT_TIMESTAMP"`
+}
+
+func NEW_AUTHOR(alias string) AUTHOR {
+	var tbl AUTHOR
+	tbl.TableInfo = sq.TableInfo{TableName: "author", TableAlias: alias}
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	tbl.EMAIL = sq.NewStringField("email", tbl.TableInfo)
+	tbl.DATA = sq.NewJSONField("data", tbl.TableInfo)
+	tbl.CREATED_AT = sq.NewTimeField("created_at", tbl.TableInfo)
+	return tbl
+}
+
+func (tbl AUTHOR) DDL(dialect string, t *ddl.T) {
+	t.PrimaryKey(tbl.AUTHOR_ID)
+	t.Unique(tbl.EMAIL)
+}
+
+type BOOK struct {
+	sq.TableInfo
+	BOOK_ID      sq.NumberField  `ddl:"sqlite:type=INTEGER autoincrement"`
+	AUTHOR_ID    sq.NumberField  `ddl:"notnull"`
+	TITLE        sq.StringField  `ddl:"sqlite:type=VARCHAR(255) notnull"`
+	PRICE        sq.NumberField  `ddl:"sqlite:type=NUMERIC"`
+	IS_PUBLISHED sq.BooleanField `ddl:"notnull default=FALSE"`
+	COVER        sq.BinaryField
+}
+
+func NEW_BOOK(alias string) BOOK {
+	var tbl BOOK
+	tbl.TableInfo = sq.TableInfo{TableName: "book", TableAlias: alias}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.PRICE = sq.NewNumberField("price", tbl.TableInfo)
+	tbl.IS_PUBLISHED = sq.NewBooleanField("is_published", tbl.TableInfo)
+	tbl.COVER = sq.NewBlobField("cover", tbl.TableInfo)
+	return tbl
+}
+
+func (tbl BOOK) DDL(dialect string, t *ddl.T) {
+	REF_AUTHOR := NEW_AUTHOR("")
+	t.ForeignKey(tbl.AUTHOR_ID).References(REF_AUTHOR, REF_AUTHOR.AUTHOR_ID).OnUpdate(ddl.CASCADE).OnDelete(ddl.CASCADE)
+	t.PrimaryKey(tbl.BOOK_ID)
+	t.NameIndex("book_author_id_title_idx", tbl.AUTHOR_ID, tbl.TITLE).Unique().Where("is_published")
+	t.NameIndex("book_title_idx", tbl.TITLE)
+}
@@ -0,0 +1,171 @@
+package ddl_test
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/ddl"
+	"github.com/bokwoon95/sq/internal/testutil"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const structsSchema = `
+CREATE TABLE author (
+    author_id INTEGER PRIMARY KEY
+    ,name TEXT NOT NULL
+    ,email TEXT
+    ,data JSON
+    ,created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    ,CONSTRAINT author_email_key UNIQUE (email)
+);
+CREATE TABLE book (
+    book_id INTEGER PRIMARY KEY AUTOINCREMENT
+    ,author_id INT NOT NULL
+    ,title VARCHAR(255) NOT NULL
+    ,price NUMERIC
+    ,is_published BOOLEAN NOT NULL DEFAULT FALSE
+    ,cover BLOB
+    ,CONSTRAINT book_author_id_fkey FOREIGN KEY (author_id) REFERENCES author (author_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX book_title_idx ON book (title);
+CREATE UNIQUE INDEX book_author_id_title_idx ON book (author_id, title) WHERE is_published;
+`
+
+func Test_WriteStructs(t *testing.T) {
+	const dialect = sq.DialectSQLite
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "db.sqlite3"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer db.Close()
+	_, err = db.Exec(structsSchema)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	gotDBMetadata, err := ddl.NewDatabaseMetadata(dialect, ddl.WithDB(db, &ddl.Filter{SortOutput: true}))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	buf := &bytes.Buffer{}
+	err = gotDBMetadata.WriteStructs(buf, &ddl.WriteStructOptions{PackageName: "ddl_test"})
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	b, err := os.ReadFile("structs_generated_test.go")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(buf.String(), string(b)); diff != "" {
+		t.Fatal(testutil.Callers(), diff)
+	}
+	// The generated structs should describe the same schema that they were
+	// generated from.
+	wantDBMetadata, err := ddl.NewDatabaseMetadata(dialect, ddl.WithTables(
+		NEW_AUTHOR(""),
+		NEW_BOOK(""),
+	))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	m, err := ddl.Migrate(ddl.CreateMissing|ddl.UpdateExisting|ddl.DropExtraneous, gotDBMetadata, wantDBMetadata)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	buf.Reset()
+	err = m.WriteSQL(buf)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if buf.Len() > 0 {
+		t.Fatal(testutil.Callers(), " expected empty migration, got:\n"+buf.String())
+	}
+	// Migrate does not diff AUTOINCREMENT, so check it separately.
+	for _, dbMetadata := range []ddl.DatabaseMetadata{gotDBMetadata, wantDBMetadata} {
+		schema := dbMetadata.Schemas[0]
+		table := schema.Tables[schema.CachedTablePosition("book")]
+		column := table.Columns[table.CachedColumnPosition("book_id")]
+		if !column.IsAutoincrement {
+			t.Error(testutil.Callers(), " book.book_id is not AUTOINCREMENT")
+		}
+	}
+}
+
+func Test_IntrospectSQLiteAutoincrement(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "db.sqlite3"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`
+CREATE TABLE note (
+    -- note_id is not AUTOINCREMENT
+    note_id INTEGER PRIMARY KEY
+    ,body TEXT DEFAULT 'AUTOINCREMENT'
+    ,autoincrement_count INT
+    ,CHECK (body <> 'autoincrement')
+);
+CREATE TABLE counter (
+    counter_id INTEGER PRIMARY KEY AUTOINCREMENT /* autoincrement */
+    ,name TEXT
+);
+`)
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	dbMetadata, err := ddl.NewDatabaseMetadata(sq.DialectSQLite, ddl.WithDB(db, nil))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	schema := dbMetadata.Schemas[0]
+	for tableName, wantAutoincrement := range map[string]bool{"note": false, "counter": true} {
+		table := schema.Tables[schema.CachedTablePosition(tableName)]
+		column := table.Columns[table.CachedColumnPosition(tableName+"_id")]
+		if column.IsAutoincrement != wantAutoincrement {
+			t.Errorf(testutil.Callers()+" %s.%s: IsAutoincrement is %t, want %t", tableName, column.ColumnName, column.IsAutoincrement, wantAutoincrement)
+		}
+	}
+}
+
+const structsPostgresSchema = `
+CREATE TABLE author (
+    author_id INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY
+    ,name TEXT NOT NULL
+    ,email VARCHAR(255) UNIQUE
+    ,data JSONB
+    ,created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE book (
+    book_id UUID PRIMARY KEY DEFAULT gen_random_uuid()
+    ,author_id INT NOT NULL REFERENCES author (author_id) ON DELETE CASCADE
+    ,title TEXT NOT NULL
+    ,price NUMERIC(10,2)
+    ,tags TEXT[]
+);
+CREATE INDEX book_title_idx ON book (title);
+`
+
+func Test_WriteStructsPostgres(t *testing.T) {
+	dbMetadata, err := ddl.NewDatabaseMetadata(sq.DialectPostgres, ddl.WithSQLFiles(fstest.MapFS{
+		"schema.sql": &fstest.MapFile{Data: []byte(structsPostgresSchema)},
+	}, "schema.sql"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	buf := &bytes.Buffer{}
+	err = dbMetadata.WriteStructs(buf, &ddl.WriteStructOptions{PackageName: "tables"})
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	b, err := os.ReadFile("testdata/structs_postgres.golden")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	if diff := testutil.Diff(buf.String(), string(b)); diff != "" {
+		t.Fatal(testutil.Callers(), diff)
+	}
+}
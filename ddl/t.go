@@ -35,6 +35,13 @@ func (t *T) VirtualTable(moduleName string, moduleArgs ...string) {
 	t.tbl.VirtualTableArgs = moduleArgs
 }
 
+// RenamedFrom tells Migrate that the table used to be called tableName, so
+// that an existing table by that name is renamed instead of dropped and
+// recreated.
+func (t *T) RenamedFrom(tableName string) {
+	t.tbl.RenamedFrom = tableName
+}
+
 type TColumn struct {
 	dialect        string
 	tbl            *Table
@@ -209,6 +216,44 @@ func (t *TColumn) Unique() *TColumn {
 	return t
 }
 
+// RenamedFrom tells Migrate that the column used to be called columnName, so
+// that an existing column by that name is renamed instead of dropped and
+// re-added.
+func (t *TColumn) RenamedFrom(columnName string) *TColumn {
+	t.tbl.Columns[t.columnPosition].RenamedFrom = columnName
+	return t
+}
+
+// Enum sets the column type to an enum. On postgres the enum is created as a
+// type in the table's schema, on mysql the column type is an inline ENUM(...)
+// and on sqlite the labels are enforced with a CHECK constraint.
+func (t *TColumn) Enum(ddlEnum DDLEnum) *TColumn {
+	enum, err := NewEnum(ddlEnum)
+	if err != nil {
+		panicErr(fmt.Errorf("Enum: %w", err))
+	}
+	switch t.dialect {
+	case sq.DialectPostgres:
+		enum.EnumSchema = t.tbl.TableSchema
+		t.tbl.Columns[t.columnPosition].ColumnType = enumColumnType(t.dialect, enum)
+		for _, e := range t.tbl.enums {
+			if e.EnumSchema == enum.EnumSchema && e.EnumName == enum.EnumName {
+				return t
+			}
+		}
+		t.tbl.enums = append(t.tbl.enums, enum)
+	case sq.DialectMySQL:
+		t.tbl.Columns[t.columnPosition].ColumnType = enumColumnType(t.dialect, enum)
+	default:
+		constraintName := generateName(CHECK, t.tbl.TableName, t.columnName)
+		_, err = createOrUpdateConstraint(t.dialect, t.tbl, CHECK, constraintName, []string{t.columnName}, enumCheckExpr(t.dialect, t.columnName, enum))
+		if err != nil {
+			panicErr(fmt.Errorf("Enum: %w", err))
+		}
+	}
+	return t
+}
+
 func (t *TColumn) Collate(collation string) *TColumn {
 	t.tbl.Columns[t.columnPosition].CollationName = collation
 	return t
@@ -714,6 +759,28 @@ func (t *T) Trigger(format string, values ...interface{}) {
 	}
 }
 
+// recordStructField records structField as the declaring field of the
+// constraints and indexes at or after constraintPosition and indexPosition
+// that do not have one yet.
+func (tbl *Table) recordStructField(structField string, constraintPosition, indexPosition int) {
+	if structField == "" {
+		return
+	}
+	if tbl.structFields == nil {
+		tbl.structFields = make(map[string]string)
+	}
+	for _, constraint := range tbl.Constraints[constraintPosition:] {
+		if _, ok := tbl.structFields[constraint.ConstraintName]; !ok {
+			tbl.structFields[constraint.ConstraintName] = structField
+		}
+	}
+	for _, index := range tbl.Indexes[indexPosition:] {
+		if _, ok := tbl.structFields[index.IndexName]; !ok {
+			tbl.structFields[index.IndexName] = structField
+		}
+	}
+}
+
 func (tbl *Table) LoadTable(dialect string, table sq.SchemaTable) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -745,7 +812,13 @@ func (tbl *Table) LoadTable(dialect string, table sq.SchemaTable) (err error) {
 	if tbl.TableSchema != "" {
 		qualifiedTable = tbl.TableSchema + "." + tbl.TableName
 	}
+	var structField string
+	var constraintCount, indexCount int
 	for i := 0; i < tableValue.NumField(); i++ {
+		// attribute the constraints and indexes added by the previous field
+		tbl.recordStructField(structField, constraintCount, indexCount)
+		structField = tableType.Name() + "." + tableType.Field(i).Name
+		constraintCount, indexCount = len(tbl.Constraints), len(tbl.Indexes)
 		fieldValue := tableValue.Field(i)
 		if !fieldValue.CanInterface() {
 			err = tbl.loadTableConfig(dialect, qualifiedTable, tableType.Field(i).Tag.Get("ddl"))
@@ -779,6 +852,7 @@ func (tbl *Table) LoadTable(dialect string, table sq.SchemaTable) (err error) {
 			return err
 		}
 	}
+	tbl.recordStructField(structField, constraintCount, indexCount)
 	defer func() {
 		if strings.EqualFold(tbl.VirtualTable, "FTS5") {
 			var columnNames []string
@@ -792,21 +866,7 @@ func (tbl *Table) LoadTable(dialect string, table sq.SchemaTable) (err error) {
 			}
 			tbl.VirtualTableArgs = append(columnNames, tbl.VirtualTableArgs...)
 		}
-		for _, constraint := range tbl.Constraints {
-			if len(constraint.Columns) != 1 {
-				continue
-			}
-			n := tbl.CachedColumnPosition(constraint.Columns[0])
-			if n < 0 {
-				continue
-			}
-			switch constraint.ConstraintType {
-			case PRIMARY_KEY:
-				tbl.Columns[n].IsPrimaryKey = true
-			case UNIQUE:
-				tbl.Columns[n].IsUnique = true
-			}
-		}
+		tbl.markKeyColumns()
 	}()
 	if ddlTable, ok := table.(DDLTable); ok {
 		ddlTable.DDL(dialect, &T{dialect: dialect, tbl: tbl})
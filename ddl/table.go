@@ -19,11 +19,20 @@ type Table struct {
 	VirtualTableArgs []string     `json:",omitempty"`
 	SQL              string       `json:",omitempty"`
 	Comment          string       `json:",omitempty"`
+	RenamedFrom      string       `json:",omitempty"`
 	Ignore           bool         `json:",omitempty"`
 	columnCache      map[string]int
 	constraintCache  map[string]int
 	indexCache       map[string]int
 	triggerCache     map[string]int
+	// structFields maps the names of constraints and indexes to the struct
+	// field whose ddl tag declared them e.g. "FILM.LANGUAGE_ID", for error
+	// messages.
+	structFields map[string]string
+	// enums are the postgres enums used by the table's columns, they are
+	// moved into the table's schema when the table is loaded into a
+	// DatabaseMetadata.
+	enums []Enum
 }
 
 func (tbl *Table) CachedColumnPosition(columnName string) (columnPosition int) {
@@ -194,6 +203,26 @@ func (tbl *Table) RefreshTriggerCache() {
 	}
 }
 
+// markKeyColumns sets IsPrimaryKey and IsUnique on the columns that are the
+// only column of a PRIMARY KEY or UNIQUE constraint.
+func (tbl *Table) markKeyColumns() {
+	for _, constraint := range tbl.Constraints {
+		if len(constraint.Columns) != 1 {
+			continue
+		}
+		n := tbl.CachedColumnPosition(constraint.Columns[0])
+		if n < 0 {
+			continue
+		}
+		switch constraint.ConstraintType {
+		case PRIMARY_KEY:
+			tbl.Columns[n].IsPrimaryKey = true
+		case UNIQUE:
+			tbl.Columns[n].IsUnique = true
+		}
+	}
+}
+
 func (tbl *Table) loadIndexConfig(dialect, tableSchema, tableName string, columns []string, config string) error {
 	columnNames, modifiers, modifierPositions, err := tokenizeValue(config)
 	if err != nil {
@@ -459,6 +488,8 @@ func (tbl *Table) loadColumnConfig(dialect, columnName, columnType, config strin
 			column.GeneratedExprStored = true
 		case "collate":
 			column.CollationName = modifier[1]
+		case "renamedfrom":
+			column.RenamedFrom = modifier[1]
 		case "default":
 			if needsExpressionBrackets(modifier[1]) && dialect != sq.DialectPostgres {
 				column.ColumnDefault = "(" + modifier[1] + ")"
@@ -564,6 +595,8 @@ func (tbl *Table) loadTableConfig(dialect, qualifiedTable, tableModifiers string
 			if err != nil {
 				return fmt.Errorf("%s: %s", qualifiedTable, err.Error())
 			}
+		case "renamedfrom":
+			tbl.RenamedFrom = modifier[1]
 		case "ignore":
 			if modifier[1] == "" {
 				tbl.Ignore = true
@@ -896,7 +929,7 @@ func (cmd *RenameTableCommand) AppendSQL(dialect string, buf *bytes.Buffer, args
 			if tableSchema != "" {
 				buf.WriteString(sq.QuoteIdentifier(dialect, tableSchema) + ".")
 			}
-			buf.WriteString(sq.QuoteIdentifier(dialect, cmd.TableNames[i]) + " RENAME TO ")
+			buf.WriteString(sq.QuoteIdentifier(dialect, cmd.TableNames[i]) + " TO ")
 			if renameToSchema := cmd.RenameToSchemas[i]; renameToSchema != "" {
 				buf.WriteString(sq.QuoteIdentifier(dialect, renameToSchema) + ".")
 			}
@@ -918,6 +951,69 @@ func (cmd *RenameTableCommand) AppendSQL(dialect string, buf *bytes.Buffer, args
 	return nil
 }
 
+// RebuildTableCommand recreates an SQLite table from scratch, for changes that
+// SQLite's ALTER TABLE cannot make in place. The data is copied from the old
+// table's CopyFromColumns into the new table's CopyColumns. The old table's
+// indexes and triggers are dropped together with it and have to be recreated
+// separately.
+//
+// Dropping the old table fires the ON DELETE actions of the tables that
+// reference it, so the command must be run with PRAGMA foreign_keys = OFF.
+// The pragma is a no-op inside a transaction, so it has to be turned off on
+// the connection beforehand; Migration.ExecContext refuses to rebuild tables
+// while it is on.
+type RebuildTableCommand struct {
+	Table           Table
+	CopyColumns     []string
+	CopyFromColumns []string
+	Ignore          bool
+}
+
+func (cmd RebuildTableCommand) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	if cmd.Ignore {
+		return nil
+	}
+	if dialect != sq.DialectSQLite {
+		return fmt.Errorf("%s does not need its tables rebuilt", dialect)
+	}
+	if cmd.Table.VirtualTable != "" {
+		return fmt.Errorf("cannot rebuild virtual table %s", cmd.Table.TableName)
+	}
+	if len(cmd.CopyColumns) != len(cmd.CopyFromColumns) {
+		return fmt.Errorf("rebuild %s: %d columns copied from %d columns", cmd.Table.TableName, len(cmd.CopyColumns), len(cmd.CopyFromColumns))
+	}
+	var tableSchema string
+	if cmd.Table.TableSchema != "" {
+		tableSchema = sq.QuoteIdentifier(dialect, cmd.Table.TableSchema) + "."
+	}
+	newTable := cmd.Table
+	newTable.TableName = cmd.Table.TableName + "__rebuild"
+	err := CreateTableCommand{IncludeConstraints: true, Table: newTable}.AppendSQL(dialect, buf, args, params, env)
+	if err != nil {
+		return err
+	}
+	if len(cmd.CopyColumns) > 0 {
+		buf.WriteString(";\n\nINSERT INTO " + tableSchema + sq.QuoteIdentifier(dialect, newTable.TableName) + " (")
+		for i, columnName := range cmd.CopyColumns {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(sq.QuoteIdentifier(dialect, columnName))
+		}
+		buf.WriteString(") SELECT ")
+		for i, columnName := range cmd.CopyFromColumns {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(sq.QuoteIdentifier(dialect, columnName))
+		}
+		buf.WriteString(" FROM " + tableSchema + sq.QuoteIdentifier(dialect, cmd.Table.TableName))
+	}
+	buf.WriteString(";\n\nDROP TABLE " + tableSchema + sq.QuoteIdentifier(dialect, cmd.Table.TableName))
+	buf.WriteString(";\n\nALTER TABLE " + tableSchema + sq.QuoteIdentifier(dialect, newTable.TableName) + " RENAME TO " + sq.QuoteIdentifier(dialect, cmd.Table.TableName))
+	return nil
+}
+
 type DropTableCommand struct {
 	DropIfExists bool
 	TableSchemas []string
@@ -1,5 +1,5 @@
 CREATE TABLE IF NOT EXISTS actor (
-    actor_id INTEGER PRIMARY KEY
+    actor_id INTEGER PRIMARY KEY AUTOINCREMENT
     ,first_name TEXT NOT NULL
     ,last_name TEXT NOT NULL
     ,full_name TEXT
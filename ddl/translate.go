@@ -0,0 +1,1192 @@
+package ddl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bokwoon95/sq"
+)
+
+// Translate returns the equivalent of the DatabaseMetadata in another dialect,
+// along with a warning for everything that could not be carried over exactly.
+// Column types, AUTO_INCREMENT/identity columns, ON UPDATE CURRENT_TIMESTAMP,
+// index types, collations and common default expressions are mapped to their
+// counterparts in the target dialect. Functions and trigger bodies are
+// dialect-specific and are left out, view definitions are copied verbatim.
+//
+// Migrating an empty database to the translated DatabaseMetadata gives the
+// full CREATE script for the target dialect:
+//
+//	translated, warnings, err := dbMetadata.Translate(sq.DialectPostgres)
+//	m, err := Migrate(CreateMissing, DatabaseMetadata{Dialect: sq.DialectPostgres}, translated)
+//	err = m.WriteSQL(w)
+func (dbm *DatabaseMetadata) Translate(dialect string) (translated DatabaseMetadata, warnings []string, err error) {
+	switch dialect {
+	case sq.DialectSQLite, sq.DialectPostgres, sq.DialectMySQL:
+	default:
+		return DatabaseMetadata{}, nil, fmt.Errorf("Translate: unsupported dialect %q", dialect)
+	}
+	switch dbm.Dialect {
+	case sq.DialectSQLite, sq.DialectPostgres, sq.DialectMySQL:
+	default:
+		return DatabaseMetadata{}, nil, fmt.Errorf("Translate: unsupported source dialect %q", dbm.Dialect)
+	}
+	if dbm.Dialect == dialect {
+		// a deep copy, so that the caller can modify it freely
+		b, err := json.Marshal(dbm)
+		if err != nil {
+			return DatabaseMetadata{}, nil, fmt.Errorf("Translate: %w", err)
+		}
+		err = translated.ReadJSON(bytes.NewReader(b))
+		if err != nil {
+			return DatabaseMetadata{}, nil, fmt.Errorf("Translate: %w", err)
+		}
+		return translated, nil, nil
+	}
+	t := &translator{
+		src:            dbm.Dialect,
+		dst:            dialect,
+		dbm:            dbm,
+		warnedSchemas:  make(map[string]bool),
+		usedNames:      make(map[[2]string]bool),
+		createdEnums:   make(map[[2]string]bool),
+		createdTrigger: make(map[[2]string]bool),
+	}
+	t.translate()
+	return t.out, t.warnings, nil
+}
+
+// translator holds the state of a single Translate call.
+type translator struct {
+	src, dst string
+	dbm      *DatabaseMetadata
+	out      DatabaseMetadata
+	warnings []string
+	// defaultCollation is the most common collation of a mysql database,
+	// which is taken to be the database default and is not carried over.
+	defaultCollation string
+	warnedSchemas    map[string]bool
+	// usedNames holds the index and constraint names already taken in each
+	// postgres schema, where they share a namespace.
+	usedNames map[[2]string]bool
+	// createdEnums and createdTrigger hold the enums and ON UPDATE
+	// CURRENT_TIMESTAMP trigger functions already created in each schema.
+	createdEnums   map[[2]string]bool
+	createdTrigger map[[2]string]bool
+}
+
+func (t *translator) warn(format string, a ...interface{}) {
+	t.warnings = append(t.warnings, fmt.Sprintf(format, a...))
+}
+
+func (t *translator) translate() {
+	t.out = DatabaseMetadata{
+		Dialect:      t.dst,
+		DatabaseName: t.dbm.DatabaseName,
+		Comment:      t.dbm.Comment,
+	}
+	for _, extension := range t.dbm.Extensions {
+		t.warn("extension %s: extensions are postgres-only, dropped", extension)
+	}
+	if t.src == sq.DialectMySQL {
+		counts := make(map[string]int)
+		var stringColumns int
+		for _, schema := range t.dbm.Schemas {
+			for _, tbl := range schema.Tables {
+				for _, column := range tbl.Columns {
+					if columnType := strings.ToUpper(column.ColumnType); strings.Contains(columnType, "CHAR") || strings.Contains(columnType, "TEXT") || strings.HasPrefix(columnType, "ENUM") || strings.HasPrefix(columnType, "SET") {
+						stringColumns++
+					}
+					if column.CollationName != "" {
+						counts[column.CollationName]++
+					}
+				}
+			}
+		}
+		for collationName, count := range counts {
+			if count > counts[t.defaultCollation] || (count == counts[t.defaultCollation] && collationName < t.defaultCollation) {
+				t.defaultCollation = collationName
+			}
+		}
+		if counts[t.defaultCollation]*2 <= stringColumns {
+			// collations were spelled out for a few columns only, as in a
+			// schema loaded from .sql files
+			t.defaultCollation = ""
+		}
+	}
+	for _, schema := range t.dbm.Schemas {
+		if schema.Ignore {
+			continue
+		}
+		for _, sequence := range schema.Sequences {
+			if !sequence.Ignore {
+				t.warn("sequence %s: sequences are postgres-only, dropped", qualifiedName(sequence.SequenceSchema, sequence.SequenceName))
+			}
+		}
+		for _, domain := range schema.Domains {
+			if !domain.Ignore {
+				t.warn("domain %s: domains are postgres-only, its columns use the underlying type %s instead", qualifiedName(domain.DomainSchema, domain.DomainName), domain.UnderlyingType)
+			}
+		}
+		for _, compositeType := range schema.CompositeTypes {
+			if !compositeType.Ignore {
+				t.warn("composite type %s: composite types are postgres-only, its columns use JSON instead", qualifiedName(compositeType.TypeSchema, compositeType.TypeName))
+			}
+		}
+		for _, function := range schema.Functions {
+			if !function.Ignore {
+				t.warn("function %s: function bodies are dialect-specific, not translated", qualifiedName(function.FunctionSchema, function.FunctionName))
+			}
+		}
+		for _, tbl := range schema.Tables {
+			if !tbl.Ignore {
+				t.translateTable(schema.SchemaName, tbl)
+			}
+		}
+		for _, view := range schema.Views {
+			if !view.Ignore {
+				t.translateView(schema.SchemaName, view)
+			}
+		}
+	}
+	t.out.refreshCaches()
+}
+
+// schemaName returns the name of the target schema for objects in the source
+// schema. The current schema becomes the default schema of the target.
+func (t *translator) schemaName(schemaName string) string {
+	currentSchema := t.dbm.CurrentSchema
+	if currentSchema == "" {
+		switch t.src {
+		case sq.DialectSQLite:
+			currentSchema = "main"
+		case sq.DialectPostgres:
+			currentSchema = "public"
+		}
+	}
+	if schemaName == "" || schemaName == currentSchema {
+		return ""
+	}
+	if t.dst == sq.DialectSQLite {
+		if !t.warnedSchemas[schemaName] {
+			t.warnedSchemas[schemaName] = true
+			t.warn("schema %s: sqlite has no schemas, its objects were moved to the main schema", schemaName)
+		}
+		return ""
+	}
+	return schemaName
+}
+
+// uniqueName returns name if it is not yet taken by another index or
+// constraint in the schema (postgres only), otherwise it returns fallback.
+func (t *translator) uniqueName(schemaName, name, fallback, where string) string {
+	if t.dst != sq.DialectPostgres {
+		return name
+	}
+	if t.usedNames[[2]string{schemaName, name}] && fallback != name {
+		t.warn("%s: name %s is already taken in the schema, renamed to %s", where, name, fallback)
+		name = fallback
+	}
+	t.usedNames[[2]string{schemaName, name}] = true
+	return name
+}
+
+func (t *translator) translateTable(schemaName string, tbl Table) {
+	tableName := qualifiedName(tbl.TableSchema, tbl.TableName)
+	if tbl.VirtualTable != "" {
+		t.warn("table %s: virtual tables (USING %s) are sqlite-only, dropped", tableName, tbl.VirtualTable)
+		return
+	}
+	schemaName = t.schemaName(schemaName)
+	out := Table{
+		TableSchema: schemaName,
+		TableName:   tbl.TableName,
+		Comment:     tbl.Comment,
+	}
+	// keyColumns are the columns that appear in a key or an index.
+	keyColumns := make(map[string]bool)
+	for _, constraint := range tbl.Constraints {
+		if !constraint.Ignore && constraint.ConstraintType != CHECK {
+			for _, columnName := range constraint.Columns {
+				keyColumns[columnName] = true
+			}
+		}
+	}
+	for _, index := range tbl.Indexes {
+		if !index.Ignore {
+			for _, columnName := range index.Columns {
+				keyColumns[columnName] = true
+			}
+		}
+	}
+	var extraConstraints []Constraint
+	for _, column := range tbl.Columns {
+		if column.Ignore {
+			continue
+		}
+		outColumn, constraints := t.translateColumn(&out, tbl, column, keyColumns[column.ColumnName])
+		out.AppendColumn(outColumn)
+		extraConstraints = append(extraConstraints, constraints...)
+	}
+	for _, constraint := range tbl.Constraints {
+		if constraint.Ignore {
+			continue
+		}
+		if outConstraint, ok := t.translateConstraint(out, constraint); ok {
+			out.AppendConstraint(outConstraint)
+		}
+	}
+	for _, constraint := range extraConstraints {
+		constraint.ConstraintName = t.uniqueName(schemaName, constraint.ConstraintName, constraint.ConstraintName, "")
+		out.AppendConstraint(constraint)
+	}
+	for _, index := range tbl.Indexes {
+		if index.Ignore {
+			continue
+		}
+		if outIndex, ok := t.translateIndex(out, index); ok {
+			out.AppendIndex(outIndex)
+		}
+	}
+	for _, trigger := range tbl.Triggers {
+		if !trigger.Ignore {
+			t.warn("trigger %s on %s: trigger bodies are dialect-specific, not translated", trigger.TriggerName, tableName)
+		}
+	}
+	out.markKeyColumns()
+	t.out.loadSchemaObject(schemaName, func(schema *Schema) {
+		schema.AppendTable(out)
+	})
+}
+
+// translateColumn translates a column of tbl, to be added to out. It returns
+// the CHECK constraints that the column needs in the target dialect (for enum
+// and domain columns).
+func (t *translator) translateColumn(out *Table, tbl Table, column Column, isKey bool) (Column, []Constraint) {
+	where := "column " + qualifiedName(tbl.TableSchema, tbl.TableName, column.ColumnName)
+	outColumn := Column{
+		TableSchema:   out.TableSchema,
+		TableName:     out.TableName,
+		ColumnName:    column.ColumnName,
+		IsNotNull:     column.IsNotNull,
+		IsPrimaryKey:  column.IsPrimaryKey,
+		IsUnique:      column.IsUnique,
+		CollationName: column.CollationName,
+		ColumnDefault: column.ColumnDefault,
+		ColumnComment: column.ColumnComment,
+	}
+	var constraints []Constraint
+	typ := t.parseColumnType(where, column.ColumnType)
+	if typ.domain != nil {
+		domain := typ.domain
+		outColumn.IsNotNull = outColumn.IsNotNull || domain.IsNotNull
+		if outColumn.ColumnDefault == "" {
+			outColumn.ColumnDefault = domain.DomainDefault
+		}
+		if outColumn.CollationName == "" {
+			outColumn.CollationName = domain.CollationName
+		}
+		for i, check := range domain.Checks {
+			constraintName := generateName(CHECK, out.TableName, column.ColumnName)
+			if i > 0 {
+				constraintName += strconv.Itoa(i)
+			}
+			checkExpr := valueRegexp.ReplaceAllString(check.CheckExpr, sq.QuoteIdentifier(t.src, column.ColumnName))
+			constraints = append(constraints, Constraint{
+				TableSchema:    out.TableSchema,
+				TableName:      out.TableName,
+				ConstraintName: constraintName,
+				ConstraintType: CHECK,
+				CheckExpr:      t.translateExpr(where, "domain CHECK", checkExpr),
+			})
+		}
+	}
+	isAutoincrement := column.IsAutoincrement || column.Identity != "" || typ.serial
+	if strings.HasPrefix(strings.ToUpper(outColumn.ColumnDefault), "NEXTVAL(") && typ.isInteger() {
+		// a serial column declared by hand
+		isAutoincrement = true
+		outColumn.ColumnDefault = ""
+	}
+	if t.src == sq.DialectSQLite && column.IsPrimaryKey && strings.EqualFold(column.ColumnType, "INTEGER") {
+		// INTEGER PRIMARY KEY is an alias for the ROWID and is filled in
+		// automatically.
+		isAutoincrement = true
+	}
+	if typ.kind == "enum" {
+		enum := Enum{
+			EnumSchema: out.TableSchema,
+			EnumName:   typ.enumName,
+			EnumLabels: typ.labels,
+		}
+		if enum.EnumName == "" {
+			enum.EnumName = out.TableName + "_" + column.ColumnName
+		}
+		switch t.dst {
+		case sq.DialectPostgres:
+			key := [2]string{enum.EnumSchema, enum.EnumName}
+			if !t.createdEnums[key] {
+				t.createdEnums[key] = true
+				t.out.loadSchemaObject(enum.EnumSchema, func(schema *Schema) {
+					schema.AppendEnum(enum)
+				})
+			}
+			outColumn.ColumnType = enumColumnType(t.dst, enum)
+		case sq.DialectMySQL:
+			outColumn.ColumnType = enumColumnType(t.dst, enum)
+		case sq.DialectSQLite:
+			outColumn.ColumnType = "TEXT"
+			constraints = append(constraints, Constraint{
+				TableSchema:    out.TableSchema,
+				TableName:      out.TableName,
+				ConstraintName: generateName(CHECK, out.TableName, column.ColumnName),
+				ConstraintType: CHECK,
+				CheckExpr:      enumCheckExpr(t.dst, column.ColumnName, enum),
+			})
+		}
+	} else {
+		outColumn.ColumnType = t.formatColumnType(where, typ, isKey)
+	}
+	if isAutoincrement {
+		switch t.dst {
+		case sq.DialectPostgres:
+			if typ.isInteger() {
+				outColumn.Identity = BY_DEFAULT_AS_IDENTITY
+				if column.Identity == ALWAYS_AS_IDENTITY {
+					outColumn.Identity = ALWAYS_AS_IDENTITY
+				}
+			} else {
+				t.warn("%s: postgres identity columns must be integers, autoincrement dropped", where)
+			}
+		case sq.DialectMySQL:
+			if isKey {
+				outColumn.IsAutoincrement = true
+			} else {
+				t.warn("%s: mysql AUTO_INCREMENT columns must be part of a key, autoincrement dropped", where)
+			}
+		case sq.DialectSQLite:
+			if column.IsPrimaryKey && typ.isInteger() {
+				outColumn.ColumnType = "INTEGER"
+				outColumn.IsAutoincrement = column.IsAutoincrement || column.Identity != "" || typ.serial
+			} else {
+				t.warn("%s: sqlite AUTOINCREMENT columns must be an INTEGER PRIMARY KEY, autoincrement dropped", where)
+			}
+		}
+	}
+	if column.GeneratedExpr != "" {
+		outColumn.GeneratedExpr = t.translateExpr(where, "generated expression", column.GeneratedExpr)
+		outColumn.GeneratedExprStored = column.GeneratedExprStored
+		if t.dst == sq.DialectPostgres && !outColumn.GeneratedExprStored {
+			outColumn.GeneratedExprStored = true
+			t.warn("%s: postgres does not support VIRTUAL generated columns, made STORED", where)
+		}
+	}
+	if outColumn.ColumnDefault != "" {
+		outColumn.ColumnDefault = t.translateDefault(where, typ, outColumn.ColumnDefault)
+	}
+	if outColumn.CollationName != "" {
+		outColumn.CollationName = t.translateCollation(where, outColumn.CollationName)
+	}
+	if column.OnUpdateCurrentTimestamp {
+		t.onUpdateCurrentTimestamp(out, column.ColumnName)
+	}
+	return outColumn, constraints
+}
+
+// valueRegexp matches the VALUE keyword in a domain CHECK expression.
+var valueRegexp = regexp.MustCompile(`(?i)\bVALUE\b`)
+
+// onUpdateCurrentTimestamp emulates a mysql ON UPDATE CURRENT_TIMESTAMP column
+// with a trigger, in the same way as the sakila example schema does.
+func (t *translator) onUpdateCurrentTimestamp(out *Table, columnName string) {
+	qualifiedTable := sq.QuoteIdentifier(t.dst, out.TableName)
+	if out.TableSchema != "" {
+		qualifiedTable = sq.QuoteIdentifier(t.dst, out.TableSchema) + "." + qualifiedTable
+	}
+	switch t.dst {
+	case sq.DialectPostgres:
+		functionName := columnName + "_trg"
+		qualifiedFunction := sq.QuoteIdentifier(t.dst, functionName)
+		if out.TableSchema != "" {
+			qualifiedFunction = sq.QuoteIdentifier(t.dst, out.TableSchema) + "." + qualifiedFunction
+		}
+		if key := [2]string{out.TableSchema, functionName}; !t.createdTrigger[key] {
+			t.createdTrigger[key] = true
+			t.out.loadSchemaObject(out.TableSchema, func(schema *Schema) {
+				schema.AppendFunction(Function{
+					FunctionSchema: out.TableSchema,
+					FunctionName:   functionName,
+					ReturnType:     "trigger",
+					SQL: "CREATE OR REPLACE FUNCTION " + qualifiedFunction + "() RETURNS trigger AS $$ BEGIN\n" +
+						"    NEW." + sq.QuoteIdentifier(t.dst, columnName) + " = NOW();\n" +
+						"    RETURN NEW;\n" +
+						"END; $$ LANGUAGE plpgsql",
+				})
+			})
+		}
+		triggerName := out.TableName + "_" + columnName + "_before_update_trg"
+		out.AppendTrigger(Trigger{
+			TableSchema: out.TableSchema,
+			TableName:   out.TableName,
+			TriggerName: triggerName,
+			SQL: "CREATE TRIGGER " + sq.QuoteIdentifier(t.dst, triggerName) + " BEFORE UPDATE ON " + qualifiedTable + "\n" +
+				"FOR EACH ROW EXECUTE PROCEDURE " + qualifiedFunction + "()",
+		})
+	case sq.DialectSQLite:
+		triggerName := out.TableName + "_" + columnName + "_after_update_trg"
+		out.AppendTrigger(Trigger{
+			TableSchema: out.TableSchema,
+			TableName:   out.TableName,
+			TriggerName: triggerName,
+			SQL: "CREATE TRIGGER " + sq.QuoteIdentifier(t.dst, triggerName) + " AFTER UPDATE ON " + qualifiedTable + " BEGIN\n" +
+				"    UPDATE " + qualifiedTable + " SET " + sq.QuoteIdentifier(t.dst, columnName) + " = DATETIME('now') WHERE ROWID = NEW.ROWID;\n" +
+				"END",
+		})
+	}
+}
+
+func (t *translator) translateConstraint(out Table, constraint Constraint) (Constraint, bool) {
+	where := "constraint " + constraint.ConstraintName + " on " + qualifiedName(constraint.TableSchema, constraint.TableName)
+	if constraint.TableName == "" {
+		where = "constraint " + constraint.ConstraintName + " on " + out.TableName
+	}
+	outConstraint := constraint
+	outConstraint.TableSchema = out.TableSchema
+	outConstraint.TableName = out.TableName
+	if constraint.ConstraintType == PRIMARY_KEY && constraint.ConstraintName == "PRIMARY" {
+		// every mysql primary key is named PRIMARY
+		outConstraint.ConstraintName = generateName(PRIMARY_KEY, out.TableName, constraint.Columns...)
+	}
+	switch constraint.ConstraintType {
+	case EXCLUDE:
+		t.warn("%s: EXCLUDE constraints are postgres-only, dropped", where)
+		return Constraint{}, false
+	case CHECK:
+		outConstraint.CheckExpr = t.translateExpr(where, "CHECK", constraint.CheckExpr)
+	case FOREIGN_KEY:
+		if constraint.ReferencesSchema != "" {
+			outConstraint.ReferencesSchema = t.schemaName(constraint.ReferencesSchema)
+		}
+		if t.dst == sq.DialectMySQL && constraint.MatchOption != "" {
+			t.warn("%s: mysql ignores %s, dropped", where, constraint.MatchOption)
+			outConstraint.MatchOption = ""
+		}
+		if t.dst == sq.DialectMySQL {
+			if constraint.UpdateRule == SET_DEFAULT {
+				t.warn("%s: mysql does not support ON UPDATE SET DEFAULT, dropped", where)
+				outConstraint.UpdateRule = ""
+			}
+			if constraint.DeleteRule == SET_DEFAULT {
+				t.warn("%s: mysql does not support ON DELETE SET DEFAULT, dropped", where)
+				outConstraint.DeleteRule = ""
+			}
+		}
+	}
+	for i, expr := range constraint.Exprs {
+		if expr != "" {
+			outConstraint.Exprs[i] = t.translateExpr(where, "expression", expr)
+		}
+	}
+	if constraint.IsDeferrable && (t.dst == sq.DialectMySQL || (t.dst == sq.DialectSQLite && constraint.ConstraintType != FOREIGN_KEY)) {
+		t.warn("%s: %s does not support DEFERRABLE %s constraints, made NOT DEFERRABLE", where, t.dst, constraint.ConstraintType)
+		outConstraint.IsDeferrable = false
+		outConstraint.IsInitiallyDeferred = false
+	}
+	if constraint.ConstraintType != CHECK && constraint.ConstraintType != FOREIGN_KEY {
+		outConstraint.ConstraintName = t.uniqueName(out.TableSchema, outConstraint.ConstraintName, generateName(constraint.ConstraintType, out.TableName, constraint.Columns...), where)
+	}
+	return outConstraint, true
+}
+
+func (t *translator) translateIndex(out Table, index Index) (Index, bool) {
+	where := "index " + index.IndexName + " on " + qualifiedName(index.TableSchema, index.TableName)
+	if index.TableName == "" {
+		where = "index " + index.IndexName + " on " + out.TableName
+	}
+	outIndex := index
+	outIndex.TableSchema = out.TableSchema
+	outIndex.TableName = out.TableName
+	outIndex.SQL = ""
+	switch indexType := strings.ToUpper(index.IndexType); indexType {
+	case "", "BTREE":
+		outIndex.IndexType = ""
+	case "HASH":
+		if t.dst == sq.DialectSQLite {
+			t.warn("%s: sqlite has no HASH indexes, made a regular index", where)
+			outIndex.IndexType = ""
+		}
+	case "FULLTEXT", "SPATIAL":
+		if t.dst != sq.DialectMySQL {
+			t.warn("%s: %s indexes are mysql-only, dropped", where, indexType)
+			return Index{}, false
+		}
+	case "GIN", "GIST", "BRIN", "SPGIST":
+		if t.dst != sq.DialectPostgres {
+			t.warn("%s: %s indexes are postgres-only, dropped", where, indexType)
+			return Index{}, false
+		}
+	default:
+		t.warn("%s: unknown index type %s, dropped", where, index.IndexType)
+		return Index{}, false
+	}
+	if index.Predicate != "" {
+		if t.dst == sq.DialectMySQL {
+			t.warn("%s: mysql does not support partial indexes, dropped", where)
+			return Index{}, false
+		}
+		outIndex.Predicate = t.translateExpr(where, "WHERE", index.Predicate)
+	}
+	if len(index.IncludeColumns) > 0 && t.dst != sq.DialectPostgres {
+		t.warn("%s: INCLUDE columns are postgres-only, dropped", where)
+		outIndex.IncludeColumns = nil
+	}
+	if len(index.Exprs) > 0 {
+		outIndex.Exprs = make([]string, len(index.Exprs))
+		for i, expr := range index.Exprs {
+			if expr != "" {
+				outIndex.Exprs[i] = t.translateExpr(where, "expression", expr)
+			}
+		}
+	}
+	outIndex.IndexName = t.uniqueName(out.TableSchema, index.IndexName, generateName(INDEX, out.TableName, index.Columns...), where)
+	return outIndex, true
+}
+
+func (t *translator) translateView(schemaName string, view View) {
+	where := "view " + qualifiedName(view.ViewSchema, view.ViewName)
+	schemaName = t.schemaName(schemaName)
+	out := View{
+		ViewSchema:     schemaName,
+		ViewName:       view.ViewName,
+		IsMaterialized: view.IsMaterialized,
+		SQL:            t.convertQuotes(view.SQL),
+		Comment:        view.Comment,
+	}
+	t.warn("%s: view definitions are copied verbatim, check that the query is valid %s", where, t.dst)
+	if view.IsMaterialized {
+		t.warn("%s: materialized views are postgres-only, made a regular view", where)
+		out.IsMaterialized = false
+	}
+	for _, index := range view.Indexes {
+		if !index.Ignore {
+			t.warn("%s: index %s dropped, only postgres materialized views can be indexed", where, index.IndexName)
+		}
+	}
+	for _, trigger := range view.Triggers {
+		if !trigger.Ignore {
+			t.warn("%s: trigger %s: trigger bodies are dialect-specific, not translated", where, trigger.TriggerName)
+		}
+	}
+	t.out.loadSchemaObject(schemaName, func(schema *Schema) {
+		schema.AppendView(out)
+	})
+}
+
+// columnType is a dialect-independent column type.
+type columnType struct {
+	// kind is the type e.g. "int", "varchar" or "timestamptz", or "" if the
+	// type is not recognized.
+	kind string
+	// args are the bracketed type arguments e.g. "255" or "6,2".
+	args string
+	// raw is the original column type.
+	raw string
+	// serial is true for the postgres SERIAL types.
+	serial bool
+	// enumName and labels describe an enum type. enumName is empty for
+	// inline mysql ENUM(...) types.
+	enumName string
+	labels   []string
+	// domain is the postgres domain that the column type refers to.
+	domain *Domain
+}
+
+func (typ columnType) isInteger() bool {
+	switch typ.kind {
+	case "tinyint", "smallint", "int", "bigint":
+		return true
+	}
+	return false
+}
+
+// columnTypeKinds maps the column types of each dialect to their kind.
+var columnTypeKinds = map[string]map[string]string{
+	sq.DialectPostgres: {
+		"SMALLINT": "smallint", "INT2": "smallint", "SMALLSERIAL": "smallint", "SERIAL2": "smallint",
+		"INT": "int", "INTEGER": "int", "INT4": "int", "SERIAL": "int", "SERIAL4": "int",
+		"BIGINT": "bigint", "INT8": "bigint", "BIGSERIAL": "bigint", "SERIAL8": "bigint",
+		"NUMERIC": "decimal", "DECIMAL": "decimal",
+		"REAL": "real", "FLOAT4": "real",
+		"DOUBLE PRECISION": "double", "FLOAT8": "double", "FLOAT": "double",
+		"BOOLEAN": "boolean", "BOOL": "boolean",
+		"CHAR": "char", "CHARACTER": "char", "BPCHAR": "char",
+		"VARCHAR": "varchar", "CHARACTER VARYING": "varchar",
+		"TEXT": "text", "CITEXT": "text",
+		"BYTEA":                  "blob",
+		"DATE":                   "date",
+		"TIME":                   "time",
+		"TIME WITHOUT TIME ZONE": "time",
+		"TIMESTAMP":              "datetime", "TIMESTAMP WITHOUT TIME ZONE": "datetime",
+		"TIMESTAMPTZ": "timestamptz", "TIMESTAMP WITH TIME ZONE": "timestamptz",
+		"JSON": "json", "JSONB": "json",
+		"UUID": "uuid",
+	},
+	sq.DialectMySQL: {
+		"TINYINT": "tinyint", "SMALLINT": "smallint", "MEDIUMINT": "int", "INT": "int", "INTEGER": "int", "BIGINT": "bigint",
+		"DECIMAL": "decimal", "NUMERIC": "decimal", "DEC": "decimal", "FIXED": "decimal",
+		"FLOAT": "real", "DOUBLE": "double", "DOUBLE PRECISION": "double", "REAL": "double",
+		"BOOL": "boolean", "BOOLEAN": "boolean",
+		"CHAR": "char", "VARCHAR": "varchar",
+		"TINYTEXT": "text", "TEXT": "text", "MEDIUMTEXT": "text", "LONGTEXT": "text",
+		"BINARY": "binary", "VARBINARY": "varbinary",
+		"TINYBLOB": "blob", "BLOB": "blob", "MEDIUMBLOB": "blob", "LONGBLOB": "blob",
+		"DATE": "date", "TIME": "time", "DATETIME": "timestamptz", "TIMESTAMP": "timestamptz", "YEAR": "smallint",
+		"JSON": "json",
+	},
+	sq.DialectSQLite: {
+		"TINYINT": "int", "SMALLINT": "int", "MEDIUMINT": "int", "INT": "int", "INTEGER": "int", "BIGINT": "bigint",
+		"NUMERIC": "decimal", "DECIMAL": "decimal",
+		"REAL": "double", "DOUBLE": "double", "DOUBLE PRECISION": "double", "FLOAT": "double",
+		"BOOLEAN": "boolean",
+		"CHAR":    "char", "VARCHAR": "varchar", "TEXT": "text", "CLOB": "text",
+		"BLOB": "blob",
+		"DATE": "date", "TIME": "time", "DATETIME": "timestamptz", "TIMESTAMP": "timestamptz",
+		"JSON": "json",
+		"UUID": "uuid",
+	},
+}
+
+// parseColumnType parses a column type of the source dialect.
+func (t *translator) parseColumnType(where, rawType string) columnType {
+	typ := columnType{raw: rawType}
+	s := strings.TrimSpace(rawType)
+	if t.src == sq.DialectPostgres {
+		if strings.HasSuffix(s, "[]") {
+			t.warn("%s: array type %s replaced by JSON", where, rawType)
+			typ.kind = "json"
+			return typ
+		}
+		if enum, ok := t.lookupEnum(s); ok {
+			typ.kind, typ.enumName, typ.labels = "enum", enum.EnumName, enum.EnumLabels
+			return typ
+		}
+		if domain, ok := t.lookupDomain(s); ok {
+			underlying := t.parseColumnType(where, domain.UnderlyingType)
+			underlying.domain = &domain
+			return underlying
+		}
+		if _, ok := t.lookupCompositeType(s); ok {
+			typ.kind = "json"
+			return typ
+		}
+	}
+	if t.src == sq.DialectMySQL && len(s) > 5 && strings.EqualFold(s[:5], "ENUM(") && strings.HasSuffix(s, ")") {
+		typ.kind = "enum"
+		typ.labels = parseEnumLabels(s[5 : len(s)-1])
+		return typ
+	}
+	base := s
+	if i, j := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')'); i >= 0 && j > i {
+		typ.args = strings.ReplaceAll(s[i+1:j], " ", "")
+		base = s[:i] + " " + s[j+1:]
+	}
+	words := strings.Fields(strings.ToUpper(base))
+	var unsigned bool
+	for i := len(words) - 1; i >= 0; i-- {
+		if words[i] == "UNSIGNED" || words[i] == "ZEROFILL" {
+			unsigned = unsigned || words[i] == "UNSIGNED"
+			words = append(words[:i], words[i+1:]...)
+		}
+	}
+	base = strings.Join(words, " ")
+	typ.kind = columnTypeKinds[t.src][base]
+	switch t.src {
+	case sq.DialectPostgres:
+		typ.serial = strings.Contains(base, "SERIAL")
+		if typ.kind == "varchar" && typ.args == "" {
+			typ.kind = "text"
+		}
+		if typ.kind == "char" && typ.args == "" && base == "BPCHAR" {
+			typ.kind = "text"
+		}
+		if base == "CITEXT" {
+			t.warn("%s: CITEXT is postgres-only, comparisons are no longer case-insensitive", where)
+		}
+	case sq.DialectMySQL:
+		if typ.kind == "tinyint" && typ.args == "1" && !unsigned {
+			typ.kind = "boolean"
+		}
+		if typ.isInteger() || typ.kind == "decimal" || typ.kind == "real" || typ.kind == "double" {
+			// display widths like INT(11) carry no meaning
+			if typ.kind != "decimal" {
+				typ.args = ""
+			}
+		}
+		if unsigned {
+			switch typ.kind {
+			case "tinyint":
+				typ.kind = "smallint"
+			case "smallint":
+				typ.kind = "int"
+			case "int":
+				if base == "MEDIUMINT" {
+					break
+				}
+				typ.kind = "bigint"
+			case "bigint":
+				if t.dst != sq.DialectMySQL {
+					t.warn("%s: BIGINT UNSIGNED values above 9223372036854775807 do not fit in BIGINT", where)
+				}
+			}
+		}
+		if base == "BIT" && typ.args == "1" {
+			typ.kind = "boolean"
+		}
+		if base == "SET" {
+			t.warn("%s: SET types are mysql-only, replaced by a comma separated TEXT", where)
+			typ.kind = "text"
+		}
+	case sq.DialectSQLite:
+		if typ.kind == "" {
+			typ.kind = sqliteAffinity(base)
+		}
+	}
+	if t.src != sq.DialectMySQL && typ.isInteger() {
+		typ.args = ""
+	}
+	if typ.kind == "" {
+		t.warn("%s: unknown type %s kept as is", where, rawType)
+	}
+	return typ
+}
+
+// sqliteAffinity returns the kind of an unrecognized sqlite column type
+// following sqlite's type affinity rules.
+func sqliteAffinity(columnType string) string {
+	switch {
+	case strings.Contains(columnType, "INT"):
+		return "int"
+	case strings.Contains(columnType, "CHAR"), strings.Contains(columnType, "CLOB"), strings.Contains(columnType, "TEXT"):
+		return "text"
+	case columnType == "", strings.Contains(columnType, "BLOB"):
+		return "blob"
+	case strings.Contains(columnType, "REAL"), strings.Contains(columnType, "FLOA"), strings.Contains(columnType, "DOUB"):
+		return "double"
+	}
+	return ""
+}
+
+// formatColumnType returns the column type of the target dialect. isKey
+// reports whether the column is part of a key or index, which mysql does not
+// allow for TEXT and BLOB columns.
+func (t *translator) formatColumnType(where string, typ columnType, isKey bool) string {
+	withArgs := func(name string) string {
+		if typ.args == "" {
+			return name
+		}
+		return name + "(" + typ.args + ")"
+	}
+	switch t.dst {
+	case sq.DialectPostgres:
+		switch typ.kind {
+		case "tinyint", "smallint":
+			return "SMALLINT"
+		case "int":
+			return "INT"
+		case "bigint":
+			return "BIGINT"
+		case "decimal":
+			return withArgs("NUMERIC")
+		case "real":
+			return "REAL"
+		case "double":
+			return "DOUBLE PRECISION"
+		case "boolean":
+			return "BOOLEAN"
+		case "char":
+			return withArgs("CHAR")
+		case "varchar":
+			return withArgs("VARCHAR")
+		case "text":
+			return "TEXT"
+		case "binary", "varbinary", "blob":
+			return "BYTEA"
+		case "date":
+			return "DATE"
+		case "time":
+			return withArgs("TIME")
+		case "datetime":
+			return withArgs("TIMESTAMP")
+		case "timestamptz":
+			return withArgs("TIMESTAMPTZ")
+		case "json":
+			return "JSONB"
+		case "uuid":
+			return "UUID"
+		}
+	case sq.DialectMySQL:
+		switch typ.kind {
+		case "tinyint":
+			return "TINYINT"
+		case "smallint":
+			return "SMALLINT"
+		case "int":
+			return "INT"
+		case "bigint":
+			return "BIGINT"
+		case "decimal":
+			if typ.args == "" {
+				t.warn("%s: NUMERIC without a precision is unbounded, made DECIMAL(65,30)", where)
+				return "DECIMAL(65,30)"
+			}
+			return withArgs("DECIMAL")
+		case "real":
+			return "FLOAT"
+		case "double":
+			return "DOUBLE"
+		case "boolean":
+			return "BOOLEAN"
+		case "char":
+			return withArgs("CHAR")
+		case "varchar":
+			return withArgs("VARCHAR")
+		case "text":
+			if isKey {
+				t.warn("%s: mysql cannot index TEXT columns, made VARCHAR(255)", where)
+				return "VARCHAR(255)"
+			}
+			return "TEXT"
+		case "binary":
+			return withArgs("BINARY")
+		case "varbinary":
+			return withArgs("VARBINARY")
+		case "blob":
+			if isKey {
+				t.warn("%s: mysql cannot index BLOB columns, made VARBINARY(255)", where)
+				return "VARBINARY(255)"
+			}
+			return "BLOB"
+		case "date":
+			return "DATE"
+		case "time":
+			return withArgs("TIME")
+		case "datetime", "timestamptz":
+			return withArgs("DATETIME")
+		case "json":
+			return "JSON"
+		case "uuid":
+			return "BINARY(16)"
+		}
+	case sq.DialectSQLite:
+		switch typ.kind {
+		case "tinyint", "smallint", "int":
+			return "INT"
+		case "bigint":
+			return "BIGINT"
+		case "decimal":
+			return "NUMERIC"
+		case "real", "double":
+			return "REAL"
+		case "boolean":
+			return "BOOLEAN"
+		case "char", "varchar", "text":
+			return "TEXT"
+		case "binary", "varbinary", "blob":
+			return "BLOB"
+		case "date":
+			return "DATE"
+		case "time":
+			return "TIME"
+		case "datetime", "timestamptz":
+			return "DATETIME"
+		case "json":
+			return "JSON"
+		case "uuid":
+			return "UUID"
+		}
+	}
+	return typ.raw
+}
+
+// lookupEnum returns the postgres enum that the column type refers to.
+// Introspected column types are uppercased, so names are compared
+// case-insensitively.
+func (t *translator) lookupEnum(columnType string) (Enum, bool) {
+	schemaName, typeName := splitTypeName(columnType)
+	for _, schema := range t.dbm.Schemas {
+		for _, enum := range schema.Enums {
+			if !enum.Ignore && strings.EqualFold(enum.EnumName, typeName) && (schemaName == "" || strings.EqualFold(schema.SchemaName, schemaName)) {
+				return enum, true
+			}
+		}
+	}
+	return Enum{}, false
+}
+
+func (t *translator) lookupDomain(columnType string) (Domain, bool) {
+	schemaName, typeName := splitTypeName(columnType)
+	for _, schema := range t.dbm.Schemas {
+		for _, domain := range schema.Domains {
+			if !domain.Ignore && strings.EqualFold(domain.DomainName, typeName) && (schemaName == "" || strings.EqualFold(schema.SchemaName, schemaName)) {
+				return domain, true
+			}
+		}
+	}
+	return Domain{}, false
+}
+
+func (t *translator) lookupCompositeType(columnType string) (CompositeType, bool) {
+	schemaName, typeName := splitTypeName(columnType)
+	for _, schema := range t.dbm.Schemas {
+		for _, compositeType := range schema.CompositeTypes {
+			if !compositeType.Ignore && strings.EqualFold(compositeType.TypeName, typeName) && (schemaName == "" || strings.EqualFold(schema.SchemaName, schemaName)) {
+				return compositeType, true
+			}
+		}
+	}
+	return CompositeType{}, false
+}
+
+// splitTypeName splits a possibly schema-qualified and quoted type name.
+func splitTypeName(columnType string) (schemaName, typeName string) {
+	typeName = columnType
+	if i := strings.LastIndexByte(columnType, '.'); i >= 0 {
+		schemaName, typeName = columnType[:i], columnType[i+1:]
+	}
+	return strings.Trim(schemaName, `"`), strings.Trim(typeName, `"`)
+}
+
+// parseEnumLabels parses the comma separated, quoted labels of a mysql ENUM.
+func parseEnumLabels(s string) []string {
+	var labels []string
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		var label strings.Builder
+		for i++; i < len(s); i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				label.WriteByte(s[i])
+				continue
+			}
+			if s[i] == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					i++
+					label.WriteByte('\'')
+					continue
+				}
+				break
+			}
+			label.WriteByte(s[i])
+		}
+		labels = append(labels, label.String())
+	}
+	return labels
+}
+
+// collationKinds maps the collations of every dialect to a
+// dialect-independent kind, and collationNames maps the kinds back to a
+// collation of each dialect.
+var (
+	collationKinds = map[string]string{
+		"c": "binary", "posix": "binary", "binary": "binary", "utf8mb4_bin": "binary", "utf8_bin": "binary", "ucs_basic": "binary",
+		"nocase": "nocase",
+	}
+	collationNames = map[string]map[string]string{
+		"binary": {sq.DialectPostgres: "C", sq.DialectMySQL: "utf8mb4_bin", sq.DialectSQLite: "BINARY"},
+		"nocase": {sq.DialectMySQL: "utf8mb4_general_ci", sq.DialectSQLite: "NOCASE"},
+	}
+)
+
+func (t *translator) translateCollation(where, collationName string) string {
+	if t.src == sq.DialectMySQL && collationName == t.defaultCollation {
+		return ""
+	}
+	kind := collationKinds[strings.ToLower(collationName)]
+	if kind == "" && strings.HasSuffix(strings.ToLower(collationName), "_ci") {
+		kind = "nocase"
+	}
+	if name := collationNames[kind][t.dst]; name != "" {
+		return name
+	}
+	t.warn("%s: collation %s has no %s equivalent, dropped", where, collationName, t.dst)
+	return ""
+}
+
+// postgresCastRegexp matches the ::type casts that postgres adds to
+// introspected expressions.
+var postgresCastRegexp = regexp.MustCompile(`(?i)::(?:"[^"]+"|character varying|double precision|time(?:stamp)? with(?:out)? time zone|[a-z_][a-z0-9_]*)(?:\(\d+(?:,\s*\d+)?\))?(?:\[\])?`)
+
+// translateDefault translates a column default expression. Defaults that have
+// no equivalent in the target dialect are dropped with a warning.
+func (t *translator) translateDefault(where string, typ columnType, expr string) string {
+	expr = strings.TrimSpace(expr)
+	if t.src == sq.DialectPostgres {
+		expr = postgresCastRegexp.ReplaceAllString(expr, "")
+	}
+	if t.src == sq.DialectMySQL && !strings.Contains(expr, "(") {
+		switch typ.kind {
+		case "char", "varchar", "text", "enum":
+			// information_schema reports string defaults without quotes
+			expr = toExpr(sq.DialectMySQL, expr)
+		}
+	}
+	wrap := func(expr string) string {
+		if t.dst == sq.DialectMySQL && !(strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")")) {
+			// mysql only accepts literals and CURRENT_TIMESTAMP without
+			// brackets
+			return "(" + expr + ")"
+		}
+		return expr
+	}
+	switch strings.ToUpper(strings.Join(strings.Fields(expr), "")) {
+	case "NULL":
+		return "NULL"
+	case "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP()", "NOW()", "LOCALTIMESTAMP", "DATETIME('NOW')", "STATEMENT_TIMESTAMP()", "TRANSACTION_TIMESTAMP()":
+		return "CURRENT_TIMESTAMP"
+	case "CURRENT_DATE", "CURRENT_DATE()", "CURDATE()", "DATE('NOW')":
+		return wrap("CURRENT_DATE")
+	case "CURRENT_TIME", "CURRENT_TIME()", "CURTIME()", "TIME('NOW')":
+		return wrap("CURRENT_TIME")
+	case "GEN_RANDOM_UUID()", "UUID_GENERATE_V4()", "UUID_TO_BIN(UUID())", "(UUID_TO_BIN(UUID()))":
+		switch t.dst {
+		case sq.DialectPostgres:
+			return "gen_random_uuid()"
+		case sq.DialectMySQL:
+			return "(UUID_TO_BIN(UUID()))"
+		}
+		t.warn("%s: %s has no function for DEFAULT %s, dropped", where, t.dst, expr)
+		return ""
+	}
+	if strings.HasPrefix(strings.ToUpper(expr), "NEXTVAL(") {
+		t.warn("%s: DEFAULT %s refers to a postgres sequence, dropped", where, expr)
+		return ""
+	}
+	if typ.kind == "boolean" {
+		switch strings.ToUpper(expr) {
+		case "1", "'1'", "B'1'", "TRUE", "'T'", "'TRUE'":
+			return "TRUE"
+		case "0", "'0'", "B'0'", "FALSE", "'F'", "'FALSE'":
+			return "FALSE"
+		}
+	}
+	if isLiteral(expr) {
+		switch typ.kind {
+		case "text", "blob", "json":
+			// mysql TEXT, BLOB and JSON columns only take expression
+			// defaults
+			return wrap(expr)
+		}
+		return expr
+	}
+	return wrap(t.translateExpr(where, "DEFAULT", expr))
+}
+
+// isLiteral reports whether expr is a number, string or boolean literal.
+func isLiteral(expr string) bool {
+	if len(expr) >= 2 && expr[0] == '\'' && expr[len(expr)-1] == '\'' && !strings.Contains(expr[1:len(expr)-1], "'") {
+		return true
+	}
+	if strings.EqualFold(expr, "TRUE") || strings.EqualFold(expr, "FALSE") {
+		return true
+	}
+	_, err := strconv.ParseFloat(expr, 64)
+	return err == nil
+}
+
+// translateExpr translates an SQL expression by removing postgres casts and
+// converting quoted identifiers. Expressions that use operators or functions
+// that are not known to work in the target dialect are kept as is with a
+// warning.
+func (t *translator) translateExpr(where, what, expr string) string {
+	if t.src == sq.DialectPostgres {
+		expr = postgresCastRegexp.ReplaceAllString(expr, "")
+	}
+	expr = t.convertQuotes(expr)
+	if reason := t.nonPortable(expr); reason != "" {
+		t.warn("%s: %s %s uses %s, check that it is valid %s", where, what, expr, reason, t.dst)
+	}
+	return expr
+}
+
+// portableFunctions are the functions that behave the same in every dialect.
+var portableFunctions = map[string]bool{
+	"ABS": true, "COALESCE": true, "NULLIF": true, "LOWER": true, "UPPER": true,
+	"LENGTH": true, "TRIM": true, "LTRIM": true, "RTRIM": true, "REPLACE": true,
+	"ROUND": true, "SUBSTR": true, "MIN": true, "MAX": true, "COUNT": true, "SUM": true, "AVG": true,
+	// keywords that may be followed by a bracket
+	"IN": true, "AND": true, "OR": true, "NOT": true, "IS": true, "BETWEEN": true,
+	"WHEN": true, "THEN": true, "ELSE": true, "EXISTS": true, "VALUES": true, "LIKE": true,
+}
+
+var functionCallRegexp = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+
+// nonPortable returns a description of the first construct in expr that is
+// not known to work in the target dialect, or "" if there is none.
+func (t *translator) nonPortable(expr string) string {
+	// string literals are not looked into
+	var b strings.Builder
+	for i := 0; i < len(expr); i++ {
+		if expr[i] != '\'' {
+			b.WriteByte(expr[i])
+			continue
+		}
+		b.WriteString("''")
+		for i++; i < len(expr); i++ {
+			if expr[i] == '\'' {
+				if i+1 < len(expr) && expr[i+1] == '\'' {
+					i++
+					continue
+				}
+				break
+			}
+		}
+	}
+	s := b.String()
+	if t.dst == sq.DialectMySQL && strings.Contains(s, "||") {
+		return "|| (which is OR in mysql, use CONCAT)"
+	}
+	if strings.Contains(s, "::") {
+		return "a postgres cast"
+	}
+	if strings.Contains(s, "~") || strings.Contains(strings.ToUpper(s), "ILIKE") {
+		return "a postgres operator"
+	}
+	for _, match := range functionCallRegexp.FindAllStringSubmatch(s, -1) {
+		if name := strings.ToUpper(match[1]); !portableFunctions[name] {
+			return "the function " + name
+		}
+	}
+	return ""
+}
+
+// convertQuotes converts the quoted identifiers in expr between the double
+// quotes of sqlite and postgres and the backticks of mysql.
+func (t *translator) convertQuotes(expr string) string {
+	var from, to byte
+	switch {
+	case t.src == sq.DialectMySQL && t.dst != sq.DialectMySQL:
+		from, to = '`', '"'
+	case t.src != sq.DialectMySQL && t.dst == sq.DialectMySQL:
+		from, to = '"', '`'
+	default:
+		return expr
+	}
+	b := []byte(expr)
+	var inString bool
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '\'':
+			inString = !inString
+		case b[i] == '\\' && inString && t.src == sq.DialectMySQL:
+			i++
+		case b[i] == from && !inString:
+			b[i] = to
+		}
+	}
+	return string(b)
+}
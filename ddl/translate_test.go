@@ -0,0 +1,301 @@
+package ddl
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_Translate(t *testing.T) {
+	mysqlFS := fstest.MapFS{"schema.sql": &fstest.MapFile{Data: []byte("CREATE TABLE author (\n" +
+		"    author_id INT UNSIGNED NOT NULL AUTO_INCREMENT\n" +
+		"    ,name VARCHAR(255) NOT NULL COLLATE utf8mb4_bin\n" +
+		"    ,bio TEXT\n" +
+		"    ,is_active TINYINT(1) NOT NULL DEFAULT 1\n" +
+		"    ,rating ENUM('G','PG','R') DEFAULT 'G'\n" +
+		"    ,created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP\n" +
+		"    ,updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP\n" +
+		"    ,PRIMARY KEY (author_id)\n" +
+		"    ,CONSTRAINT author_name_check CHECK (LENGTH(`name`) > 0)\n" +
+		"    ,FULLTEXT INDEX author_bio_idx (bio)\n" +
+		"    ,INDEX author_name_idx (name)\n" +
+		");\n" +
+		"CREATE TABLE book (\n" +
+		"    book_id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY\n" +
+		"    ,author_id INT UNSIGNED NOT NULL\n" +
+		"    ,title VARCHAR(255) NOT NULL\n" +
+		"    ,price DECIMAL(6,2)\n" +
+		"    ,data JSON\n" +
+		"    ,CONSTRAINT book_author_id_fkey FOREIGN KEY (author_id) REFERENCES author (author_id) ON DELETE CASCADE\n" +
+		");\n")}}
+	mysqlDBMetadata, err := NewDatabaseMetadata(sq.DialectMySQL, WithSQLFiles(mysqlFS, "schema.sql"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	postgresFS := fstest.MapFS{"schema.sql": &fstest.MapFile{Data: []byte(`
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+CREATE SCHEMA audit;
+CREATE TYPE mood AS ENUM ('happy', 'sad');
+CREATE DOMAIN email AS TEXT CHECK (VALUE LIKE '%@%');
+CREATE TABLE author (
+    author_id SERIAL PRIMARY KEY
+    ,email email NOT NULL UNIQUE
+    ,name TEXT NOT NULL COLLATE "C"
+    ,mood mood DEFAULT 'happy'
+    ,tags TEXT[]
+    ,token UUID NOT NULL DEFAULT gen_random_uuid()
+    ,is_active BOOLEAN NOT NULL DEFAULT true
+    ,price NUMERIC
+    ,created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    ,CONSTRAINT author_name_check CHECK (name <> '')
+);
+CREATE INDEX author_tags_idx ON author USING GIN (tags);
+CREATE INDEX author_active_idx ON author (name) WHERE is_active;
+CREATE INDEX author_lower_name_idx ON author (LOWER("name"));
+CREATE TABLE audit.author_log (
+    log_id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY
+    ,author_id INT REFERENCES public.author (author_id) DEFERRABLE
+    ,logged_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    ,note TEXT DEFAULT 'n/a'::text
+);
+`)}}
+	postgresDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithSQLFiles(postgresFS, "schema.sql"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	type TT struct {
+		description  string
+		dbMetadata   DatabaseMetadata
+		dialect      string
+		wantSQL      string
+		wantWarnings []string
+	}
+
+	tests := []TT{{
+		description: "mysql to postgres",
+		dbMetadata:  mysqlDBMetadata,
+		dialect:     sq.DialectPostgres,
+		wantSQL: `CREATE TYPE author_rating AS ENUM ('G', 'PG', 'R');
+
+CREATE OR REPLACE FUNCTION updated_at_trg() RETURNS trigger AS $$ BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END; $$ LANGUAGE plpgsql;
+
+CREATE TABLE IF NOT EXISTS author (
+    author_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY
+    ,name VARCHAR(255) NOT NULL COLLATE "C"
+    ,bio TEXT
+    ,is_active BOOLEAN NOT NULL DEFAULT TRUE
+    ,rating author_rating DEFAULT 'G'
+    ,created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    ,updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+
+    ,CONSTRAINT author_author_id_pkey PRIMARY KEY (author_id)
+    ,CONSTRAINT author_name_check CHECK (LENGTH("name") > 0)
+);
+
+CREATE TABLE IF NOT EXISTS book (
+    book_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY
+    ,author_id BIGINT NOT NULL
+    ,title VARCHAR(255) NOT NULL
+    ,price NUMERIC(6,2)
+    ,data JSONB
+
+    ,CONSTRAINT book_book_id_pkey PRIMARY KEY (book_id)
+);
+
+CREATE INDEX IF NOT EXISTS author_name_idx ON author (name);
+
+CREATE TRIGGER author_updated_at_before_update_trg BEFORE UPDATE ON author
+FOR EACH ROW EXECUTE PROCEDURE updated_at_trg();
+
+ALTER TABLE IF EXISTS book
+    ADD CONSTRAINT book_author_id_fkey FOREIGN KEY (author_id) REFERENCES author (author_id) ON DELETE CASCADE;`,
+		wantWarnings: []string{
+			"index author_bio_idx on author: FULLTEXT indexes are mysql-only, dropped",
+		},
+	}, {
+		description: "mysql to sqlite",
+		dbMetadata:  mysqlDBMetadata,
+		dialect:     sq.DialectSQLite,
+		wantSQL: `CREATE TABLE IF NOT EXISTS author (
+    author_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT
+    ,name TEXT NOT NULL COLLATE BINARY
+    ,bio TEXT
+    ,is_active BOOLEAN NOT NULL DEFAULT TRUE
+    ,rating TEXT DEFAULT 'G'
+    ,created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    ,updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+
+    ,CONSTRAINT author_name_check CHECK (LENGTH("name") > 0)
+    ,CONSTRAINT author_rating_check CHECK (rating IN ('G', 'PG', 'R'))
+);
+
+CREATE TABLE IF NOT EXISTS book (
+    book_id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT
+    ,author_id BIGINT NOT NULL
+    ,title TEXT NOT NULL
+    ,price NUMERIC
+    ,data JSON
+
+    ,CONSTRAINT book_author_id_fkey FOREIGN KEY (author_id) REFERENCES author (author_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS author_name_idx ON author (name);
+
+CREATE TRIGGER author_updated_at_after_update_trg AFTER UPDATE ON author BEGIN
+    UPDATE author SET updated_at = DATETIME('now') WHERE ROWID = NEW.ROWID;
+END;`,
+		wantWarnings: []string{
+			"index author_bio_idx on author: FULLTEXT indexes are mysql-only, dropped",
+		},
+	}, {
+		description: "postgres to mysql",
+		dbMetadata:  postgresDBMetadata,
+		dialect:     sq.DialectMySQL,
+		wantSQL: `CREATE SCHEMA IF NOT EXISTS audit;
+
+CREATE TABLE IF NOT EXISTS audit.author_log (
+    log_id BIGINT AUTO_INCREMENT
+    ,author_id INT
+    ,logged_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    ,note TEXT DEFAULT ('n/a')
+
+    ,PRIMARY KEY (log_id)
+);
+
+CREATE TABLE IF NOT EXISTS author (
+    author_id INT AUTO_INCREMENT
+    ,email VARCHAR(255) NOT NULL
+    ,name VARCHAR(255) NOT NULL COLLATE utf8mb4_bin
+    ,mood ENUM('happy','sad') DEFAULT 'happy'
+    ,tags JSON
+    ,token BINARY(16) NOT NULL DEFAULT (UUID_TO_BIN(UUID()))
+    ,is_active BOOLEAN NOT NULL DEFAULT TRUE
+    ,price DECIMAL(65,30)
+    ,created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+
+    ,PRIMARY KEY (author_id)
+    ,CONSTRAINT author_email_key UNIQUE (email)
+    ,CONSTRAINT author_name_check CHECK (name <> '')
+    ,CONSTRAINT author_email_check CHECK (email LIKE '%@%')
+    ,INDEX author_lower_name_idx (LOWER(` + "`" + `name` + "`" + `))
+);
+
+ALTER TABLE audit.author_log
+    ADD CONSTRAINT author_log_author_id_fkey FOREIGN KEY (author_id) REFERENCES author (author_id);`,
+		wantWarnings: []string{
+			"extension pgcrypto: extensions are postgres-only, dropped",
+			"constraint author_log_author_id_fkey on audit.author_log: mysql does not support DEFERRABLE FOREIGN KEY constraints, made NOT DEFERRABLE",
+			"domain email: domains are postgres-only, its columns use the underlying type TEXT instead",
+			"column author.email: mysql cannot index TEXT columns, made VARCHAR(255)",
+			"column author.name: mysql cannot index TEXT columns, made VARCHAR(255)",
+			"column author.tags: array type TEXT[] replaced by JSON",
+			"column author.price: NUMERIC without a precision is unbounded, made DECIMAL(65,30)",
+			"index author_tags_idx on author: GIN indexes are postgres-only, dropped",
+			"index author_active_idx on author: mysql does not support partial indexes, dropped",
+		},
+	}, {
+		description: "postgres to sqlite",
+		dbMetadata:  postgresDBMetadata,
+		dialect:     sq.DialectSQLite,
+		wantSQL: `CREATE TABLE IF NOT EXISTS author_log (
+    log_id INTEGER PRIMARY KEY AUTOINCREMENT
+    ,author_id INT
+    ,logged_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    ,note TEXT DEFAULT 'n/a'
+
+    ,CONSTRAINT author_log_author_id_fkey FOREIGN KEY (author_id) REFERENCES author (author_id) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE TABLE IF NOT EXISTS author (
+    author_id INTEGER PRIMARY KEY AUTOINCREMENT
+    ,email TEXT NOT NULL
+    ,name TEXT NOT NULL COLLATE BINARY
+    ,mood TEXT DEFAULT 'happy'
+    ,tags JSON
+    ,token UUID NOT NULL
+    ,is_active BOOLEAN NOT NULL DEFAULT TRUE
+    ,price NUMERIC
+    ,created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+
+    ,CONSTRAINT author_email_key UNIQUE (email)
+    ,CONSTRAINT author_name_check CHECK (name <> '')
+    ,CONSTRAINT author_email_check CHECK (email LIKE '%@%')
+    ,CONSTRAINT author_mood_check CHECK (mood IN ('happy', 'sad'))
+);
+
+CREATE INDEX IF NOT EXISTS author_active_idx ON author (name) WHERE is_active;
+
+CREATE INDEX IF NOT EXISTS author_lower_name_idx ON author (LOWER("name"));`,
+		wantWarnings: []string{
+			"extension pgcrypto: extensions are postgres-only, dropped",
+			"schema audit: sqlite has no schemas, its objects were moved to the main schema",
+			"domain email: domains are postgres-only, its columns use the underlying type TEXT instead",
+			"column author.tags: array type TEXT[] replaced by JSON",
+			"column author.token: sqlite has no function for DEFAULT gen_random_uuid(), dropped",
+			"index author_tags_idx on author: GIN indexes are postgres-only, dropped",
+		},
+	}}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.description, func(t *testing.T) {
+			t.Parallel()
+			translated, warnings, err := tt.dbMetadata.Translate(tt.dialect)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(warnings, tt.wantWarnings); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+			m, err := Migrate(CreateMissing, DatabaseMetadata{Dialect: tt.dialect}, translated)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			buf := &bytes.Buffer{}
+			err = m.WriteSQL(buf)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(buf.String(), tt.wantSQL); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		})
+	}
+
+	t.Run("same dialect", func(t *testing.T) {
+		t.Parallel()
+		translated, warnings, err := mysqlDBMetadata.Translate(sq.DialectMySQL)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if len(warnings) > 0 {
+			t.Errorf(testutil.Callers()+" expected no warnings, got %v", warnings)
+		}
+		m, err := Migrate(CreateMissing|UpdateExisting, mysqlDBMetadata, translated)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		buf := &bytes.Buffer{}
+		err = m.WriteSQL(buf)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if buf.Len() > 0 {
+			t.Errorf(testutil.Callers()+" expected an identical copy, got migration %s", buf.String())
+		}
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := mysqlDBMetadata.Translate("oracle")
+		if err == nil {
+			t.Fatal(testutil.Callers(), "expected error but got nil")
+		}
+	})
+}
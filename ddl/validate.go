@@ -0,0 +1,173 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateColRef checks that the columns referenced by every index, primary
+// key, unique, foreign key and exclusion constraint in wantDBMetadata exist,
+// and that every foreign key references a set of columns covered by a primary
+// key or unique constraint (or unique index). Referenced tables are looked up
+// in wantDBMetadata first, then in gotDBMetadata.
+func validateColRef(wantDBMetadata, gotDBMetadata DatabaseMetadata) error {
+	currentSchema := wantDBMetadata.CurrentSchema
+	if currentSchema == "" {
+		currentSchema = gotDBMetadata.CurrentSchema
+	}
+	var problems []string
+	for _, schema := range wantDBMetadata.Schemas {
+		if schema.Ignore {
+			continue
+		}
+		for _, tbl := range schema.Tables {
+			if tbl.Ignore || tbl.VirtualTable != "" {
+				continue
+			}
+			qualifiedTable := qualifiedName(tbl.TableSchema, tbl.TableName)
+			report := func(kind, name, problem string) {
+				source := tbl.structFields[name]
+				if source == "" {
+					source = "DDL method"
+				}
+				problems = append(problems, fmt.Sprintf("table %s: %s %s (%s): %s", qualifiedTable, kind, name, source, problem))
+			}
+			for _, constraint := range tbl.Constraints {
+				if constraint.Ignore {
+					continue
+				}
+				kind := constraintKind(constraint.ConstraintType)
+				for _, columnName := range missingColumns(tbl, constraint.Columns) {
+					report(kind, constraint.ConstraintName, "column "+columnName+" does not exist")
+				}
+				if constraint.ConstraintType != FOREIGN_KEY {
+					continue
+				}
+				if len(constraint.ReferencesColumns) != len(constraint.Columns) {
+					report(kind, constraint.ConstraintName, fmt.Sprintf("%d columns reference %d columns", len(constraint.Columns), len(constraint.ReferencesColumns)))
+					continue
+				}
+				referencesSchema := constraint.ReferencesSchema
+				if referencesSchema == "" {
+					referencesSchema = tbl.TableSchema
+				}
+				qualifiedReferences := qualifiedName(referencesSchema, constraint.ReferencesTable)
+				referencesTable, ok := lookupTable(currentSchema, referencesSchema, constraint.ReferencesTable, wantDBMetadata, gotDBMetadata)
+				if !ok {
+					report(kind, constraint.ConstraintName, "references table "+qualifiedReferences+" which does not exist")
+					continue
+				}
+				if missing := missingColumns(referencesTable, constraint.ReferencesColumns); len(missing) > 0 {
+					for _, columnName := range missing {
+						report(kind, constraint.ConstraintName, "references column "+qualifiedReferences+"."+columnName+" which does not exist")
+					}
+					continue
+				}
+				if !isUniqueKey(referencesTable, constraint.ReferencesColumns) {
+					report(kind, constraint.ConstraintName, "references "+qualifiedReferences+" ("+strings.Join(constraint.ReferencesColumns, ", ")+") which is not a primary key or unique")
+				}
+			}
+			for _, index := range tbl.Indexes {
+				if index.Ignore {
+					continue
+				}
+				for _, columnName := range missingColumns(tbl, index.Columns) {
+					report("index", index.IndexName, "column "+columnName+" does not exist")
+				}
+				for _, columnName := range missingColumns(tbl, index.IncludeColumns) {
+					report("index", index.IndexName, "included column "+columnName+" does not exist")
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid column references:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func constraintKind(constraintType string) string {
+	switch constraintType {
+	case PRIMARY_KEY:
+		return "primary key"
+	case FOREIGN_KEY:
+		return "foreign key"
+	case UNIQUE:
+		return "unique constraint"
+	case EXCLUDE:
+		return "exclusion constraint"
+	case CHECK:
+		return "check constraint"
+	}
+	return strings.ToLower(constraintType)
+}
+
+// missingColumns returns the columns in columnNames that tbl does not have (or
+// ignores). Empty column names belong to expressions and are skipped.
+func missingColumns(tbl Table, columnNames []string) []string {
+	var missing []string
+	for _, columnName := range columnNames {
+		if columnName == "" {
+			continue
+		}
+		if n := tbl.CachedColumnPosition(columnName); n < 0 || tbl.Columns[n].Ignore {
+			missing = append(missing, columnName)
+		}
+	}
+	return missing
+}
+
+// isUniqueKey reports whether tbl has a primary key, unique constraint or
+// (full, non-expression) unique index on exactly columnNames, in any order.
+func isUniqueKey(tbl Table, columnNames []string) bool {
+	for _, constraint := range tbl.Constraints {
+		if constraint.Ignore || (constraint.ConstraintType != PRIMARY_KEY && constraint.ConstraintType != UNIQUE) {
+			continue
+		}
+		if sameColumnSet(constraint.Columns, columnNames) {
+			return true
+		}
+	}
+	for _, index := range tbl.Indexes {
+		if index.Ignore || !index.IsUnique || index.Predicate != "" {
+			continue
+		}
+		if sameColumnSet(index.Columns, columnNames) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameColumnSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, columnName := range a {
+		if columnName == "" {
+			return false
+		}
+		set[columnName] = true
+	}
+	for _, columnName := range b {
+		if !set[columnName] {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupTable finds a table in the first DatabaseMetadata that has it.
+func lookupTable(currentSchema, tableSchema, tableName string, dbms ...DatabaseMetadata) (Table, bool) {
+	for _, dbm := range dbms {
+		n1, n2 := dbm.tablePosition(currentSchema, tableSchema, tableName)
+		if n2 < 0 {
+			continue
+		}
+		if tbl := dbm.Schemas[n1].Tables[n2]; !tbl.Ignore {
+			return tbl, true
+		}
+	}
+	return Table{}, false
+}
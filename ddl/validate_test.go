@@ -0,0 +1,119 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq"
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+type VALIDATE_AUTHOR struct {
+	sq.TableInfo
+	AUTHOR_ID sq.NumberField `ddl:"primarykey"`
+	EMAIL     sq.StringField `ddl:"unique"`
+	NAME      sq.StringField
+}
+
+type VALIDATE_BOOK struct {
+	sq.TableInfo `ddl:"index={title_typo}"`
+	BOOK_ID      sq.NumberField `ddl:"primarykey"`
+	TITLE        sq.StringField
+	AUTHOR_ID    sq.NumberField `ddl:"references={author.author_idd}"`
+	AUTHOR_NAME  sq.StringField `ddl:"references={author.name}"`
+	AUTHOR_EMAIL sq.StringField `ddl:"references={author.email}"`
+	EDITOR_ID    sq.NumberField `ddl:"references={editor.editor_id}"`
+}
+
+func (tbl VALIDATE_BOOK) DDL(dialect string, t *T) {
+	t.Index(NEW_VALIDATE_AUTHOR("").NAME)
+}
+
+type VALIDATE_REVIEW struct {
+	sq.TableInfo
+	REVIEW_ID    sq.NumberField `ddl:"primarykey"`
+	AUTHOR_ID    sq.NumberField `ddl:"references={author.author_id}"`
+	AUTHOR_EMAIL sq.StringField `ddl:"references={author.email}"`
+}
+
+func NEW_VALIDATE_AUTHOR(alias string) VALIDATE_AUTHOR {
+	tbl := VALIDATE_AUTHOR{TableInfo: sq.TableInfo{TableName: "author", TableAlias: alias}}
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.EMAIL = sq.NewStringField("email", tbl.TableInfo)
+	tbl.NAME = sq.NewStringField("name", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_VALIDATE_BOOK(alias string) VALIDATE_BOOK {
+	tbl := VALIDATE_BOOK{TableInfo: sq.TableInfo{TableName: "book", TableAlias: alias}}
+	tbl.BOOK_ID = sq.NewNumberField("book_id", tbl.TableInfo)
+	tbl.TITLE = sq.NewStringField("title", tbl.TableInfo)
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.AUTHOR_NAME = sq.NewStringField("author_name", tbl.TableInfo)
+	tbl.AUTHOR_EMAIL = sq.NewStringField("author_email", tbl.TableInfo)
+	tbl.EDITOR_ID = sq.NewNumberField("editor_id", tbl.TableInfo)
+	return tbl
+}
+
+func NEW_VALIDATE_REVIEW(alias string) VALIDATE_REVIEW {
+	tbl := VALIDATE_REVIEW{TableInfo: sq.TableInfo{TableName: "review", TableAlias: alias}}
+	tbl.REVIEW_ID = sq.NewNumberField("review_id", tbl.TableInfo)
+	tbl.AUTHOR_ID = sq.NewNumberField("author_id", tbl.TableInfo)
+	tbl.AUTHOR_EMAIL = sq.NewStringField("author_email", tbl.TableInfo)
+	return tbl
+}
+
+func Test_ValidateColRef(t *testing.T) {
+	t.Run("invalid references", func(t *testing.T) {
+		t.Parallel()
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(NEW_VALIDATE_AUTHOR(""), NEW_VALIDATE_BOOK("")))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		_, err = Migrate(CreateMissing|ValidateColRef, DatabaseMetadata{}, wantDBMetadata)
+		if err == nil {
+			t.Fatal(testutil.Callers(), "expected error but got nil")
+		}
+		wantErr := "invalid column references:" +
+			"\ntable book: foreign key book_author_id_fkey (VALIDATE_BOOK.AUTHOR_ID): references column author.author_idd which does not exist" +
+			"\ntable book: foreign key book_author_name_fkey (VALIDATE_BOOK.AUTHOR_NAME): references author (name) which is not a primary key or unique" +
+			"\ntable book: foreign key book_editor_id_fkey (VALIDATE_BOOK.EDITOR_ID): references table editor which does not exist" +
+			"\ntable book: index book_title_typo_idx (VALIDATE_BOOK.TableInfo): column title_typo does not exist" +
+			"\ntable book: index book_name_idx (DDL method): column name does not exist"
+		if diff := testutil.Diff(err.Error(), wantErr); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		// without ValidateColRef the mistakes go unnoticed
+		_, err = Migrate(CreateMissing, DatabaseMetadata{}, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+	})
+
+	t.Run("valid references", func(t *testing.T) {
+		t.Parallel()
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(NEW_VALIDATE_AUTHOR(""), NEW_VALIDATE_REVIEW("")))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		_, err = Migrate(CreateMissing|ValidateColRef, DatabaseMetadata{}, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+	})
+
+	t.Run("referenced table only in the database", func(t *testing.T) {
+		t.Parallel()
+		gotDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(NEW_VALIDATE_AUTHOR("")))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		wantDBMetadata, err := NewDatabaseMetadata(sq.DialectPostgres, WithTables(NEW_VALIDATE_REVIEW("")))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		_, err = Migrate(CreateMissing|ValidateColRef, gotDBMetadata, wantDBMetadata)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+	})
+}
@@ -26,6 +26,7 @@ type DeleteQuery struct {
 	RowOffset sql.NullInt64
 	// RETURNING
 	ReturningFields AliasFields
+	ReturningInto   []interface{}
 }
 
 var _ Query = DeleteQuery{}
@@ -43,11 +44,35 @@ func (q DeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 		}
 	}
 	// DELETE FROM
-	buf.WriteString("DELETE FROM ")
+	buf.WriteString("DELETE ")
 	if len(q.FromTables) == 0 {
 		return fmt.Errorf("no table provided to DELETE")
 	}
-	if q.UsingTable != nil && dialect == DialectMySQL {
+	// TOP (sqlserver)
+	if q.RowLimit.Valid && dialect == DialectSQLServer {
+		err = BufferPrintf(dialect, buf, args, params, env, nil, "TOP ({}) ", []interface{}{q.RowLimit.Int64})
+		if err != nil {
+			return fmt.Errorf("TOP: %w", err)
+		}
+	}
+	var sqlServerAlias string
+	var excludedTableQualifiers []string
+	if (dialect == DialectSQLServer || dialect == DialectOracle) && q.FromTables[0] != nil {
+		if alias := q.FromTables[0].GetAlias(); alias != "" {
+			excludedTableQualifiers = append(excludedTableQualifiers, alias)
+		} else {
+			excludedTableQualifiers = append(excludedTableQualifiers, q.FromTables[0].GetName())
+		}
+	}
+	// sqlserver does not allow an alias after DELETE, the aliased table has
+	// to be declared in a second FROM clause instead.
+	if dialect == DialectSQLServer && q.FromTables[0] != nil {
+		sqlServerAlias = q.FromTables[0].GetAlias()
+	}
+	if sqlServerAlias != "" {
+		buf.WriteString(QuoteIdentifier(dialect, sqlServerAlias))
+	} else if q.UsingTable != nil && dialect == DialectMySQL {
+		buf.WriteString("FROM ")
 		for i, table := range q.FromTables {
 			if i > 0 {
 				buf.WriteString(", ")
@@ -59,6 +84,7 @@ func (q DeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			buf.WriteString(nameOrAlias)
 		}
 	} else {
+		buf.WriteString("FROM ")
 		fromTable := q.FromTables[0]
 		if fromTable == nil {
 			return fmt.Errorf("no table provided to DELETE")
@@ -68,29 +94,56 @@ func (q DeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("DELETE FROM: %w", err)
 		}
 		if alias := fromTable.GetAlias(); alias != "" {
-			buf.WriteString(" AS " + QuoteIdentifier(dialect, alias))
+			writeTableAlias(dialect, buf, alias)
+		}
+	}
+	// OUTPUT (sqlserver)
+	if len(q.ReturningFields) > 0 && dialect == DialectSQLServer {
+		buf.WriteString(" OUTPUT ")
+		err = appendOutputFields(dialect, buf, args, params, env, "DELETED", q.ReturningFields, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("OUTPUT: %w", err)
+		}
+	}
+	// FROM (sqlserver)
+	if sqlServerAlias != "" {
+		buf.WriteString(" FROM ")
+		err = q.FromTables[0].AppendSQL(dialect, buf, args, params, env)
+		if err != nil {
+			return fmt.Errorf("FROM: %w", err)
+		}
+		writeTableAlias(dialect, buf, sqlServerAlias)
+		if q.UsingTable != nil {
+			buf.WriteString(",")
 		}
 	}
 	// USING
 	if q.UsingTable != nil {
-		if dialect == DialectSQLite {
-			return fmt.Errorf("sqlite DELETE does not support joins")
+		if dialect == DialectSQLite || dialect == DialectOracle {
+			return fmt.Errorf("%s DELETE does not support joins", dialect)
+		}
+		switch {
+		case sqlServerAlias != "":
+			buf.WriteString(" ")
+		case dialect == DialectSQLServer:
+			buf.WriteString(" FROM ")
+		default:
+			buf.WriteString(" USING ")
 		}
-		buf.WriteString(" USING ")
 		err = q.UsingTable.AppendSQL(dialect, buf, args, params, env)
 		if err != nil {
 			return fmt.Errorf("USING: %w", err)
 		}
 		if alias := q.UsingTable.GetAlias(); alias != "" {
-			buf.WriteString(" AS " + QuoteIdentifier(dialect, alias))
+			writeTableAlias(dialect, buf, alias)
 		}
 	}
 	// JOIN
 	if len(q.JoinTables) > 0 {
-		if dialect == DialectSQLite {
-			return fmt.Errorf("sqlite DELETE does not support joins")
+		if dialect == DialectSQLite || dialect == DialectOracle {
+			return fmt.Errorf("%s DELETE does not support joins", dialect)
 		}
-		if q.UsingTable == nil {
+		if q.UsingTable == nil && sqlServerAlias == "" {
 			return fmt.Errorf("can't use JOIN without providing an initial table to join on")
 		}
 		buf.WriteString(" ")
@@ -139,7 +192,7 @@ func (q DeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 		}
 	}
 	// LIMIT
-	if q.RowLimit.Valid {
+	if q.RowLimit.Valid && dialect != DialectSQLServer {
 		if dialect != DialectMySQL && dialect != DialectSQLite {
 			return fmt.Errorf("%s DELETE does not support LIMIT", dialect)
 		}
@@ -161,8 +214,16 @@ func (q DeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("OFFSET: %w", err)
 		}
 	}
+	// RETURNING ... INTO (oracle)
+	if len(q.ReturningFields) > 0 && dialect == DialectOracle {
+		err = appendReturningInto(dialect, buf, args, params, env, q.ReturningFields, q.ReturningInto, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("RETURNING: %w", err)
+		}
+		return nil
+	}
 	// RETURNING
-	if len(q.ReturningFields) > 0 {
+	if len(q.ReturningFields) > 0 && dialect != DialectSQLServer {
 		if dialect != DialectPostgres && dialect != DialectSQLite {
 			return fmt.Errorf("%s DELETE does not support RETURNING", dialect)
 		}
@@ -177,7 +238,7 @@ func (q DeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 
 func (q DeleteQuery) SetFetchableFields(fields []Field) (Query, error) {
 	switch q.Dialect {
-	case DialectPostgres, DialectSQLite:
+	case DialectPostgres, DialectSQLite, DialectSQLServer:
 		q.ReturningFields = fields
 		return q, nil
 	default:
@@ -187,7 +248,7 @@ func (q DeleteQuery) SetFetchableFields(fields []Field) (Query, error) {
 
 func (q DeleteQuery) GetFetchableFields() ([]Field, error) {
 	switch q.Dialect {
-	case DialectPostgres, DialectSQLite:
+	case DialectPostgres, DialectSQLite, DialectSQLServer:
 		return q.ReturningFields, nil
 	default:
 		return nil, fmt.Errorf("%s DELETE %w", q.Dialect, ErrNonFetchableQuery)
@@ -0,0 +1,69 @@
+package sq
+
+import "bytes"
+
+type OracleDeleteQuery DeleteQuery
+
+var _ Query = OracleDeleteQuery{}
+
+func (q OracleDeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return DeleteQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q OracleDeleteQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return DeleteQuery(q).SetFetchableFields(fields)
+}
+
+func (q OracleDeleteQuery) GetFetchableFields() ([]Field, error) {
+	return DeleteQuery(q).GetFetchableFields()
+}
+
+func (q OracleDeleteQuery) GetDialect() string { return q.Dialect }
+
+func (d OracleQueryBuilder) DeleteWith(ctes ...CTE) OracleDeleteQuery {
+	return OracleDeleteQuery{
+		Env:     d.env,
+		Dialect: DialectOracle,
+		CTEs:    ctes,
+	}
+}
+
+func (d OracleQueryBuilder) DeleteFrom(table SchemaTable) OracleDeleteQuery {
+	return OracleDeleteQuery{
+		Env:        d.env,
+		Dialect:    DialectOracle,
+		FromTables: []SchemaTable{table},
+	}
+}
+
+func (q OracleDeleteQuery) With(ctes ...CTE) OracleDeleteQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q OracleDeleteQuery) DeleteFrom(table SchemaTable) OracleDeleteQuery {
+	if len(q.FromTables) == 0 {
+		q.FromTables = append(q.FromTables, table)
+	} else {
+		q.FromTables[0] = table
+		q.FromTables = q.FromTables[:1]
+	}
+	return q
+}
+
+func (q OracleDeleteQuery) Where(predicates ...Predicate) OracleDeleteQuery {
+	q.WherePredicate.Predicates = append(q.WherePredicate.Predicates, predicates...)
+	return q
+}
+
+func (q OracleDeleteQuery) Returning(fields ...Field) OracleDeleteQuery {
+	q.ReturningFields = append(q.ReturningFields, fields...)
+	return q
+}
+
+// Into provides the destinations that the RETURNING fields are written into.
+// Each destination should be a pointer, it is bound as an sql.Out argument.
+func (q OracleDeleteQuery) Into(dests ...interface{}) OracleDeleteQuery {
+	q.ReturningInto = append(q.ReturningInto, dests...)
+	return q
+}
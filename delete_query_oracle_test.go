@@ -0,0 +1,58 @@
+package sq
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_OracleDeleteQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		var actorID int64
+		tt.item = Oracle.
+			DeleteFrom(ACTOR).
+			Where(ACTOR.FIRST_NAME.EqString("bob")).
+			Returning(ACTOR.ACTOR_ID).
+			Into(&actorID)
+		tt.wantQuery = "DELETE FROM actor WHERE actor.first_name = :1 RETURNING actor_id INTO :2"
+		tt.wantArgs = []interface{}{"bob", sql.Out{Dest: &actorID}}
+		assert(t, tt)
+	})
+
+	t.Run("DELETE USING", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("")
+		_, _, _, err := ToSQL("", DeleteQuery{
+			Dialect:    DialectOracle,
+			FromTables: []SchemaTable{ACTOR},
+			UsingTable: ACTOR,
+		})
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package sq
+
+import "bytes"
+
+type SQLServerDeleteQuery DeleteQuery
+
+var _ Query = SQLServerDeleteQuery{}
+
+func (q SQLServerDeleteQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return DeleteQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q SQLServerDeleteQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return DeleteQuery(q).SetFetchableFields(fields)
+}
+
+func (q SQLServerDeleteQuery) GetFetchableFields() ([]Field, error) {
+	return DeleteQuery(q).GetFetchableFields()
+}
+
+func (q SQLServerDeleteQuery) GetDialect() string { return q.Dialect }
+
+func (d SQLServerQueryBuilder) DeleteWith(ctes ...CTE) SQLServerDeleteQuery {
+	return SQLServerDeleteQuery{
+		Env:     d.env,
+		Dialect: DialectSQLServer,
+		CTEs:    ctes,
+	}
+}
+
+func (d SQLServerQueryBuilder) DeleteFrom(table SchemaTable) SQLServerDeleteQuery {
+	return SQLServerDeleteQuery{
+		Env:        d.env,
+		Dialect:    DialectSQLServer,
+		FromTables: []SchemaTable{table},
+	}
+}
+
+func (q SQLServerDeleteQuery) With(ctes ...CTE) SQLServerDeleteQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q SQLServerDeleteQuery) DeleteFrom(table SchemaTable) SQLServerDeleteQuery {
+	if len(q.FromTables) == 0 {
+		q.FromTables = append(q.FromTables, table)
+	} else {
+		q.FromTables[0] = table
+		q.FromTables = q.FromTables[:1]
+	}
+	return q
+}
+
+func (q SQLServerDeleteQuery) From(table Table) SQLServerDeleteQuery {
+	q.UsingTable = table
+	return q
+}
+
+func (q SQLServerDeleteQuery) Join(table Table, predicates ...Predicate) SQLServerDeleteQuery {
+	q.JoinTables = append(q.JoinTables, Join(table, predicates...))
+	return q
+}
+
+func (q SQLServerDeleteQuery) LeftJoin(table Table, predicates ...Predicate) SQLServerDeleteQuery {
+	q.JoinTables = append(q.JoinTables, LeftJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerDeleteQuery) RightJoin(table Table, predicates ...Predicate) SQLServerDeleteQuery {
+	q.JoinTables = append(q.JoinTables, RightJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerDeleteQuery) FullJoin(table Table, predicates ...Predicate) SQLServerDeleteQuery {
+	q.JoinTables = append(q.JoinTables, FullJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerDeleteQuery) CrossJoin(table Table) SQLServerDeleteQuery {
+	q.JoinTables = append(q.JoinTables, CrossJoin(table))
+	return q
+}
+
+func (q SQLServerDeleteQuery) CustomJoin(joinType JoinType, table Table, predicates ...Predicate) SQLServerDeleteQuery {
+	q.JoinTables = append(q.JoinTables, CustomJoin(joinType, table, predicates...))
+	return q
+}
+
+func (q SQLServerDeleteQuery) Where(predicates ...Predicate) SQLServerDeleteQuery {
+	q.WherePredicate.Predicates = append(q.WherePredicate.Predicates, predicates...)
+	return q
+}
+
+func (q SQLServerDeleteQuery) Top(limit int64) SQLServerDeleteQuery {
+	q.RowLimit.Valid = true
+	q.RowLimit.Int64 = limit
+	return q
+}
+
+func (q SQLServerDeleteQuery) Output(fields ...Field) SQLServerDeleteQuery {
+	q.ReturningFields = append(q.ReturningFields, fields...)
+	return q
+}
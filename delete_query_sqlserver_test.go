@@ -0,0 +1,92 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_SQLServerDeleteQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("joins", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = SQLServer.
+			DeleteFrom(ACTOR).
+			DeleteFrom(ACTOR).
+			With(NewCTE("cte", []string{"n"}, Queryf("SELECT 1"))).
+			Join(ACTOR, Eq(1, 1)).
+			LeftJoin(ACTOR, Eq(1, 1)).
+			RightJoin(ACTOR, Eq(1, 1)).
+			FullJoin(ACTOR, Eq(1, 1)).
+			CrossJoin(ACTOR).
+			CustomJoin("CROSS APPLY", ACTOR)
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1)" +
+			" DELETE a" +
+			" FROM actor AS a" +
+			" JOIN actor AS a ON @p1 = @p2" +
+			" LEFT JOIN actor AS a ON @p3 = @p4" +
+			" RIGHT JOIN actor AS a ON @p5 = @p6" +
+			" FULL JOIN actor AS a ON @p7 = @p8" +
+			" CROSS JOIN actor AS a" +
+			" CROSS APPLY actor AS a"
+		tt.wantArgs = []interface{}{1, 1, 1, 1, 1, 1, 1, 1}
+		assert(t, tt)
+	})
+
+	t.Run("delete with join", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		FILM, LANGUAGE, INVENTORY := xNEW_FILM(""), xNEW_LANGUAGE("l"), xNEW_INVENTORY("i")
+		tt.item = SQLServer.
+			DeleteFrom(FILM).
+			From(LANGUAGE).
+			Join(INVENTORY, INVENTORY.FILM_ID.Eq(FILM.FILM_ID)).
+			Where(
+				LANGUAGE.LANGUAGE_ID.Eq(FILM.LANGUAGE_ID),
+				LANGUAGE.NAME.In([]string{"English", "Italian"}),
+				INVENTORY.LAST_UPDATE.IsNotNull(),
+			).
+			Output(FILM.FILM_ID)
+		tt.wantQuery = "DELETE FROM film" +
+			" OUTPUT DELETED.film_id" +
+			" FROM language AS l" +
+			" JOIN inventory AS i ON i.film_id = film.film_id" +
+			" WHERE l.language_id = film.language_id AND l.name IN (@p1, @p2) AND i.last_update IS NOT NULL"
+		tt.wantArgs = []interface{}{"English", "Italian"}
+		assert(t, tt)
+	})
+
+	t.Run("TOP", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		tt.item = SQLServer.
+			DeleteFrom(ACTOR).
+			Where(ACTOR.FIRST_NAME.EqString("bob")).
+			Top(10)
+		tt.wantQuery = "DELETE TOP (@p1) FROM actor WHERE actor.first_name = @p2"
+		tt.wantArgs = []interface{}{int64(10), "bob"}
+		assert(t, tt)
+	})
+}
@@ -1 +1,69 @@
 package stitchdocs
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// NewMarkdown returns the goldmark pipeline the docs are rendered with: GitHub
+// Flavored Markdown with highlighted code blocks and heading attributes.
+func NewMarkdown() goldmark.Markdown {
+	p := parser.NewParser(parser.WithBlockParsers(parser.DefaultBlockParsers()...),
+		parser.WithInlineParsers(parser.DefaultInlineParsers()...),
+		parser.WithParagraphTransformers(parser.DefaultParagraphTransformers()...),
+	)
+	return goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.Highlighting,
+		),
+		goldmark.WithParser(p),
+		goldmark.WithParserOptions(
+			parser.WithAttribute(),
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+	)
+}
+
+var pageTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <style>
+    body { max-width: 60rem; margin: 0 auto; padding: 0 1rem; font-family: sans-serif; }
+    table { border-collapse: collapse; }
+    th, td { border: 1px solid #ddd; padding: 0.25rem 0.5rem; text-align: left; }
+  </style>
+  <title>{{ .Title }}</title>
+</head>
+<body>
+{{ .Contents }}
+</body>
+</html>
+`))
+
+// RenderHTML renders markdown into a standalone HTML page with the given
+// title. Its signature matches ddl.DocsOptions.RenderHTML.
+func RenderHTML(w io.Writer, title string, markdown []byte) error {
+	buf := &bytes.Buffer{}
+	err := NewMarkdown().Convert(markdown, buf)
+	if err != nil {
+		return err
+	}
+	return pageTemplate.Execute(w, struct {
+		Title    string
+		Contents template.HTML
+	}{
+		Title:    title,
+		Contents: template.HTML(buf.String()),
+	})
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting"
 	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
 )
 
@@ -22,23 +21,7 @@ import (
 var embeddedFiles embed.FS
 
 func TestMD(t *testing.T) {
-	p := parser.NewParser(parser.WithBlockParsers(parser.DefaultBlockParsers()...),
-		parser.WithInlineParsers(parser.DefaultInlineParsers()...),
-		parser.WithParagraphTransformers(parser.DefaultParagraphTransformers()...),
-	)
-	md := goldmark.New(
-		goldmark.WithExtensions(
-			extension.GFM,
-			highlighting.Highlighting,
-		),
-		goldmark.WithParser(p),
-		goldmark.WithParserOptions(
-			parser.WithAttribute(),
-		),
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
-	)
+	md := NewMarkdown()
 	b, err := fs.ReadFile(embeddedFiles, "quickstart.md")
 	if err != nil {
 		t.Fatal(testutil.Callers(), err)
@@ -3,6 +3,7 @@ package sq
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
@@ -355,3 +356,86 @@ func fetchExistsContext(ctx context.Context, db DB, q Query, skip int) (exists b
 	}
 	return exists, nil
 }
+
+func FetchOne[T any](db DB, q Query, rowmapper func(*Row) T) (T, error) {
+	return fetchOneContext(context.Background(), db, q, rowmapper, 1)
+}
+
+func FetchOneContext[T any](ctx context.Context, db DB, q Query, rowmapper func(*Row) T) (T, error) {
+	return fetchOneContext(ctx, db, q, rowmapper, 1)
+}
+
+func fetchOneContext[T any](ctx context.Context, db DB, q Query, rowmapper func(*Row) T, skip int) (result T, err error) {
+	if rowmapper == nil {
+		return result, errors.New("sq: cannot call FetchOne/FetchOneContext without a rowmapper")
+	}
+	q = limitOne(q)
+	rowCount, err := fetchContext(ctx, db, q, func(row *Row) {
+		v := rowmapper(row)
+		if !row.IsActive() {
+			return
+		}
+		result = v
+		row.Close()
+	}, skip+1)
+	if err != nil {
+		return result, err
+	}
+	if rowCount == 0 {
+		return result, sql.ErrNoRows
+	}
+	return result, nil
+}
+
+// limitOne applies LIMIT 1 to a SELECT query. Other queries are returned
+// unmodified, since not every dialect supports LIMIT on INSERT, UPDATE or
+// DELETE.
+func limitOne(q Query) Query {
+	limit := sql.NullInt64{Valid: true, Int64: 1}
+	switch q := q.(type) {
+	case SelectQuery:
+		q.RowLimit = limit
+		return q
+	case SQLiteSelectQuery:
+		q.RowLimit = limit
+		return q
+	case PostgresSelectQuery:
+		q.RowLimit = limit
+		return q
+	case MySQLSelectQuery:
+		q.RowLimit = limit
+		return q
+	case SQLServerSelectQuery:
+		q.RowLimit = limit
+		return q
+	case OracleSelectQuery:
+		q.RowLimit = limit
+		return q
+	}
+	return q
+}
+
+func FetchSlice[T any](db DB, q Query, rowmapper func(*Row) T) ([]T, error) {
+	return fetchSliceContext(context.Background(), db, q, rowmapper, 1)
+}
+
+func FetchSliceContext[T any](ctx context.Context, db DB, q Query, rowmapper func(*Row) T) ([]T, error) {
+	return fetchSliceContext(ctx, db, q, rowmapper, 1)
+}
+
+func fetchSliceContext[T any](ctx context.Context, db DB, q Query, rowmapper func(*Row) T, skip int) (results []T, err error) {
+	if rowmapper == nil {
+		return nil, errors.New("sq: cannot call FetchSlice/FetchSliceContext without a rowmapper")
+	}
+	_, err = fetchContext(ctx, db, q, func(row *Row) {
+		v := rowmapper(row)
+		if !row.IsActive() {
+			return
+		}
+		results = append(results, v)
+	}, skip+1)
+	if err != nil {
+		return results, err
+	}
+	return results, nil
+}
@@ -0,0 +1,257 @@
+package sq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_limitOne(t *testing.T) {
+	ACTOR := xNEW_ACTOR("")
+	tests := []struct {
+		description string
+		item        Query
+		wantQuery   string
+		wantArgs    []interface{}
+	}{
+		{
+			description: "SelectQuery",
+			item:        SelectQuery{Dialect: DialectPostgres, SelectFields: AliasFields{ACTOR.ACTOR_ID}, FromTable: ACTOR},
+			wantQuery:   "SELECT actor.actor_id FROM actor LIMIT $1",
+			wantArgs:    []interface{}{int64(1)},
+		},
+		{
+			description: "SQLiteSelectQuery overrides existing limit",
+			item:        SQLite.Select(ACTOR.ACTOR_ID).From(ACTOR).Limit(10),
+			wantQuery:   "SELECT actor.actor_id FROM actor LIMIT $1",
+			wantArgs:    []interface{}{int64(1)},
+		},
+		{
+			description: "PostgresSelectQuery",
+			item:        Postgres.Select(ACTOR.ACTOR_ID).From(ACTOR),
+			wantQuery:   "SELECT actor.actor_id FROM actor LIMIT $1",
+			wantArgs:    []interface{}{int64(1)},
+		},
+		{
+			description: "MySQLSelectQuery",
+			item:        MySQL.Select(ACTOR.ACTOR_ID).From(ACTOR),
+			wantQuery:   "SELECT actor.actor_id FROM actor LIMIT ?",
+			wantArgs:    []interface{}{int64(1)},
+		},
+		{
+			description: "SQLServerSelectQuery",
+			item:        SQLServer.Select(ACTOR.ACTOR_ID).From(ACTOR),
+			wantQuery:   "SELECT TOP (@p1) actor.actor_id FROM actor",
+			wantArgs:    []interface{}{int64(1)},
+		},
+		{
+			description: "non-SELECT queries are left untouched",
+			item:        Postgres.DeleteFrom(ACTOR).Returning(ACTOR.ACTOR_ID),
+			wantQuery:   "DELETE FROM actor RETURNING actor.actor_id",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.description, func(t *testing.T) {
+			t.Parallel()
+			gotQuery, gotArgs, _, err := ToSQL(tt.item.GetDialect(), limitOne(tt.item))
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+			if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		})
+	}
+}
+
+// newActorDB returns a temporary sqlite database with an actor table holding
+// the given last names, so that fetching can be tested without the sakila
+// databases.
+func newActorDB(t *testing.T, lastNames ...string) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "actor.sqlite3"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec("CREATE TABLE actor (actor_id INTEGER PRIMARY KEY, first_name TEXT, last_name TEXT)")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	for _, lastName := range lastNames {
+		_, err = db.Exec("INSERT INTO actor (first_name, last_name) VALUES ('TEST', ?)", lastName)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+	}
+	return db
+}
+
+func TestFetch(t *testing.T) {
+	ACTOR := xNEW_ACTOR("")
+	db := newActorDB(t, "DAVIS", "ALLEN", "BERRY")
+	lastName := func(row *Row) string {
+		return row.String(ACTOR.LAST_NAME)
+	}
+
+	t.Run("FetchSlice", func(t *testing.T) {
+		t.Parallel()
+		gotNames, err := FetchSlice(Log(db), SQLite.From(ACTOR).OrderBy(ACTOR.LAST_NAME), lastName)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotNames, []string{"ALLEN", "BERRY", "DAVIS"}); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("FetchSlice no rows", func(t *testing.T) {
+		t.Parallel()
+		gotNames, err := FetchSlice(db, SQLite.From(ACTOR).Where(ACTOR.ACTOR_ID.LtInt(0)), lastName)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if len(gotNames) != 0 {
+			t.Errorf(testutil.Callers()+" expected no results, got %v", gotNames)
+		}
+	})
+
+	t.Run("FetchOne", func(t *testing.T) {
+		t.Parallel()
+		gotName, err := FetchOne(Log(db), SQLite.From(ACTOR).OrderBy(ACTOR.LAST_NAME.Desc()), lastName)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotName, "DAVIS"); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("FetchOne no rows", func(t *testing.T) {
+		t.Parallel()
+		gotName, err := FetchOne(db, SQLite.From(ACTOR).Where(ACTOR.ACTOR_ID.LtInt(0)), lastName)
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf(testutil.Callers()+" expected sql.ErrNoRows, got %v", err)
+		}
+		if gotName != "" {
+			t.Errorf(testutil.Callers()+" expected the zero value, got %q", gotName)
+		}
+	})
+}
+
+func TestSQLiteSakilaFetch(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+
+	t.Run("FetchSlice", func(t *testing.T) {
+		t.Parallel()
+		wantAnswer := sakilaAnswer1()
+		ACTOR := xNEW_ACTOR("")
+		gotAnswer, err := FetchSlice(Log(sqliteDB), SQLite.
+			SelectDistinct().
+			From(ACTOR).
+			OrderBy(ACTOR.LAST_NAME).
+			Limit(5),
+			func(row *Row) string {
+				return row.String(ACTOR.LAST_NAME)
+			},
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotAnswer, wantAnswer); diff != "" {
+			t.Fatal(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("FetchOne", func(t *testing.T) {
+		t.Parallel()
+		wantAnswer := sakilaAnswer4()[0]
+		ACTOR := xNEW_ACTOR("")
+		gotAnswer, err := FetchOne(Log(sqliteDB), SQLite.
+			From(ACTOR).
+			Where(ACTOR.LAST_NAME.LikeString("%GEN%")).
+			OrderBy(ACTOR.ACTOR_ID),
+			func(row *Row) Actor {
+				return Actor{
+					ActorID:    row.Int(ACTOR.ACTOR_ID),
+					FirstName:  row.String(ACTOR.FIRST_NAME),
+					LastName:   row.String(ACTOR.LAST_NAME),
+					LastUpdate: row.Time(ACTOR.LAST_UPDATE),
+				}
+			},
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotAnswer, wantAnswer); diff != "" {
+			t.Fatal(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("FetchOne no rows", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("")
+		_, err := FetchOne(Log(sqliteDB), SQLite.
+			From(ACTOR).
+			Where(ACTOR.ACTOR_ID.LtInt(0)),
+			func(row *Row) int {
+				return row.Int(ACTOR.ACTOR_ID)
+			},
+		)
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf(testutil.Callers()+"expected sql.ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("FetchCursor", func(t *testing.T) {
+		t.Parallel()
+		wantAnswer := sakilaAnswer4()
+		ACTOR := xNEW_ACTOR("")
+		cursor, err := FetchCursor(context.Background(), Log(sqliteDB), SQLite.
+			From(ACTOR).
+			Where(ACTOR.LAST_NAME.LikeString("%GEN%")).
+			OrderBy(ACTOR.ACTOR_ID),
+			func(row *Row) Actor {
+				return Actor{
+					ActorID:    row.Int(ACTOR.ACTOR_ID),
+					FirstName:  row.String(ACTOR.FIRST_NAME),
+					LastName:   row.String(ACTOR.LAST_NAME),
+					LastUpdate: row.Time(ACTOR.LAST_UPDATE),
+				}
+			},
+		)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		defer cursor.Close()
+		var gotAnswer []Actor
+		for cursor.Next() {
+			actor, err := cursor.Result()
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			gotAnswer = append(gotAnswer, actor)
+		}
+		if err := cursor.Err(); err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if err := cursor.Close(); err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotAnswer, wantAnswer); diff != "" {
+			t.Fatal(testutil.Callers(), diff)
+		}
+		if cursor.RowCount() != int64(len(wantAnswer)) {
+			t.Fatalf(testutil.Callers()+"expected row count %d, got %d", len(wantAnswer), cursor.RowCount())
+		}
+	})
+}
@@ -154,6 +154,7 @@ func BufferPrintValue(dialect string, buf *bytes.Buffer, args *[]interface{}, pa
 	if isExplodableSlice(value) {
 		return explodeSlice(dialect, buf, args, params, excludedTableQualifiers, value)
 	}
+	value = uuidArg(dialect, value)
 	var paramIndices []int
 	if paramName != "" {
 		paramIndices = params[paramName]
@@ -183,6 +184,16 @@ func BufferPrintValue(dialect string, buf *bytes.Buffer, args *[]interface{}, pa
 				params[paramName] = []int{len(*args) - 1}
 			}
 		}
+	case DialectOracle:
+		if paramIndex >= 0 {
+			buf.WriteString(":" + strconv.Itoa(paramIndex+1))
+		} else {
+			buf.WriteString(":" + strconv.Itoa(len(*args)+1))
+			*args = append(*args, value)
+			if paramName != "" {
+				params[paramName] = []int{len(*args) - 1}
+			}
+		}
 	default:
 		buf.WriteString("?")
 		*args = append(*args, value)
@@ -287,13 +298,13 @@ func Sprintf(dialect string, query string, args []interface{}) (string, error) {
 		}
 		// does the current char mark the start of a new parameter name?
 		if (char == '$' && (dialect == DialectSQLite || dialect == DialectPostgres)) ||
-			(char == ':' && dialect == DialectSQLite) ||
+			(char == ':' && (dialect == DialectSQLite || dialect == DialectOracle)) ||
 			(char == '@' && (dialect == DialectSQLite || dialect == DialectSQLServer)) {
 			paramName = append(paramName, char)
 			continue
 		}
 		// is the current char the anonymous '?' parameter?
-		if char == '?' && dialect != DialectPostgres {
+		if char == '?' && dialect != DialectPostgres && dialect != DialectOracle {
 			// for sqlite, just because we encounter a '?' doesn't mean it
 			// is an anonymous param. sqlite also supports using '?' for
 			// ordinal params (e.g. ?1, ?2, ?3) or named params (?foo,
@@ -387,12 +398,11 @@ func Sprint(dialect string, v interface{}) (string, error) {
 	case nil:
 		return "NULL", nil
 	case bool:
-		if v {
-			return "TRUE", nil
-		} else {
-			return "FALSE", nil
-		}
+		return sprintBool(dialect, v), nil
 	case []byte:
+		if dialect == DialectOracle {
+			return `HEXTORAW('` + hex.EncodeToString(v) + `')`, nil
+		}
 		if dialect == DialectPostgres {
 			// https://www.postgresql.org/docs/current/datatype-binary.html
 			// (see 8.4.1. bytea Hex Format)
@@ -402,7 +412,7 @@ func Sprint(dialect string, v interface{}) (string, error) {
 	case string:
 		return `'` + EscapeQuote(v, '\'') + `'`, nil
 	case time.Time:
-		return `'` + v.Format(time.RFC3339Nano) + `'`, nil
+		return sprintTime(dialect, v), nil
 	case int:
 		return strconv.FormatInt(int64(v), 10), nil
 	case int8:
@@ -427,17 +437,20 @@ func Sprint(dialect string, v interface{}) (string, error) {
 		return strconv.FormatFloat(float64(v), 'g', -1, 64), nil
 	case float64:
 		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case UUIDValue, NullUUID:
+		if value := uuidArg(dialect, v); value != nil {
+			return Sprint(dialect, value)
+		}
+		return "NULL", nil
 	case sql.NamedArg:
 		return Sprint(dialect, v.Value)
+	case sql.Out:
+		return Sprint(dialect, v.Dest)
 	case sql.NullBool:
 		if !v.Valid {
 			return "NULL", nil
 		} else {
-			if v.Bool {
-				return "TRUE", nil
-			} else {
-				return "FALSE", nil
-			}
+			return sprintBool(dialect, v.Bool), nil
 		}
 	case sql.NullFloat64:
 		if !v.Valid {
@@ -467,7 +480,7 @@ func Sprint(dialect string, v interface{}) (string, error) {
 		if !v.Valid {
 			return "NULL", nil
 		} else {
-			return `'` + v.Time.Format(time.RFC3339Nano) + `'`, nil
+			return sprintTime(dialect, v.Time), nil
 		}
 	case driver.Valuer:
 		vv, err := v.Value()
@@ -480,17 +493,16 @@ func Sprint(dialect string, v interface{}) (string, error) {
 		case float64:
 			return strconv.FormatFloat(vv, 'g', -1, 64), nil
 		case bool:
-			if vv {
-				return "TRUE", nil
-			} else {
-				return "FALSE", nil
-			}
+			return sprintBool(dialect, vv), nil
 		case []byte:
+			if dialect == DialectOracle {
+				return `HEXTORAW('` + hex.EncodeToString(vv) + `')`, nil
+			}
 			return `x'` + hex.EncodeToString(vv) + `'`, nil
 		case string:
 			return `'` + EscapeQuote(vv, '\'') + `'`, nil
 		case time.Time:
-			return `'` + vv.Format(time.RFC3339Nano) + `'`, nil
+			return sprintTime(dialect, vv), nil
 		default:
 			return "", fmt.Errorf("unrecognized driver.Valuer type (must be one of int64, float64, bool, []byte, string, time.Time)")
 		}
@@ -517,6 +529,31 @@ func Sprint(dialect string, v interface{}) (string, error) {
 	return "", fmt.Errorf("could not convert %#v into its SQL representation", v)
 }
 
+// sprintBool returns the SQL representation of a bool. Oracle has no boolean
+// literals in SQL, booleans are conventionally stored as 1 or 0.
+func sprintBool(dialect string, b bool) string {
+	if dialect == DialectOracle {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// sprintTime returns the SQL representation of a time.Time. Oracle does not
+// implicitly convert RFC3339 strings into timestamps, so a TIMESTAMP literal
+// is used instead.
+func sprintTime(dialect string, t time.Time) string {
+	if dialect == DialectOracle {
+		return `TIMESTAMP '` + t.Format("2006-01-02 15:04:05.999999999 -07:00") + `'`
+	}
+	return `'` + t.Format(time.RFC3339Nano) + `'`
+}
+
 type customTable struct {
 	format string
 	values []interface{}
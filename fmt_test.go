@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/bokwoon95/sq/internal/testutil"
 )
@@ -358,6 +359,54 @@ func Test_Fprintf(t *testing.T) {
 		tt.wantParams = map[string][]int{"age": {1}, "email": {0}}
 		assert(t, tt)
 	})
+
+	t.Run("oracle anonymous", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.format = "SELECT {} FROM {} WHERE {} = {} AND {} <> {} AND {} IN ({})"
+		tt.values = []interface{}{USERS.NAME, USERS, USERS.AGE, 5, USERS.EMAIL, "bob@email.com", USERS.NAME, []string{"tom", "dick", "harry"}}
+		tt.wantQuery = "SELECT name FROM users WHERE age = :1 AND email <> :2 AND name IN (:3, :4, :5)"
+		tt.wantArgs = []interface{}{5, "bob@email.com", "tom", "dick", "harry"}
+		tt.wantParams = map[string][]int{}
+		assert(t, tt)
+	})
+
+	t.Run("oracle ordinal", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.format = "SELECT {} FROM {} WHERE {} = {5} AND {} <> {5} AND {1} IN ({6}) AND {4} IN ({6})"
+		tt.values = []interface{}{USERS.NAME, USERS, USERS.AGE, USERS.EMAIL, "bob@email.com", []string{"tom", "dick", "harry"}}
+		tt.wantQuery = "SELECT name FROM users WHERE age = :1 AND email <> :1 AND name IN (:2, :3, :4) AND email IN (:5, :6, :7)"
+		tt.wantArgs = []interface{}{"bob@email.com", "tom", "dick", "harry", "tom", "dick", "harry"}
+		tt.wantParams = map[string][]int{}
+		assert(t, tt)
+	})
+
+	t.Run("oracle Param", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.format = "SELECT {} FROM {} WHERE {3} = {age} AND {3} > {age} AND {4} <> {email}"
+		tt.values = []interface{}{USERS.NAME, USERS, USERS.AGE, USERS.EMAIL, Param("email", "bob@email.com"), Param("age", 5)}
+		tt.wantQuery = "SELECT name FROM users WHERE age = :1 AND age > :1 AND email <> :2"
+		tt.wantArgs = []interface{}{5, "bob@email.com"}
+		tt.wantParams = map[string][]int{"age": {0}, "email": {1}}
+		assert(t, tt)
+	})
+
+	t.Run("oracle sql.Named", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.format = "SELECT {} FROM {} WHERE {3} = {age} AND {3} > {age} AND {4} <> {email}"
+		tt.values = []interface{}{USERS.NAME, USERS, USERS.AGE, USERS.EMAIL, sql.Named("email", "bob@email.com"), sql.Named("age", 5)}
+		tt.wantQuery = "SELECT name FROM users WHERE age = :age AND age > :age AND email <> :email"
+		tt.wantArgs = []interface{}{sql.Named("age", 5), sql.Named("email", "bob@email.com")}
+		tt.wantParams = map[string][]int{"age": {0}, "email": {1}}
+		assert(t, tt)
+	})
 }
 
 func Test_Sprintf(t *testing.T) {
@@ -577,4 +626,49 @@ func Test_Sprintf(t *testing.T) {
 		tt.wantString = "SELECT name FROM users WHERE age = 5 AND age > 5 AND email <> 'bob@email.com'"
 		assert(t, tt)
 	})
+
+	t.Run("oracle", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.query = "SELECT name FROM users WHERE age = :1 AND email <> :2 AND name IN (:2, :3, :4, :1)"
+		tt.args = []interface{}{5, "tom", "dick", "harry"}
+		tt.wantString = "SELECT name FROM users WHERE age = 5 AND email <> 'tom' AND name IN ('tom', 'dick', 'harry', 5)"
+		assert(t, tt)
+	})
+
+	t.Run("oracle insideString", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.query = "SELECT name FROM users WHERE age = :1 AND email <> ':2 :2 :3 :4 ''bruh :1' AND name IN (:2, :3) :4"
+		tt.args = []interface{}{5, "tom", "dick", "harry"}
+		tt.wantString = "SELECT name FROM users WHERE age = 5 AND email <> ':2 :2 :3 :4 ''bruh :1' AND name IN ('tom', 'dick') 'harry'"
+		assert(t, tt)
+	})
+
+	t.Run("oracle mixing ordinal param and named param", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.query = "SELECT name FROM users WHERE age = :age AND age > :1 AND email <> :email"
+		tt.args = []interface{}{sql.Named("age", 5), sql.Named("email", "bob@email.com")}
+		tt.wantString = "SELECT name FROM users WHERE age = 5 AND age > 5 AND email <> 'bob@email.com'"
+		assert(t, tt)
+	})
+
+	t.Run("oracle literals", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectOracle
+		tt.query = "SELECT :1, :2, :3, :4"
+		tt.args = []interface{}{
+			true,
+			sql.NullBool{Valid: true, Bool: false},
+			[]byte{0xab, 0xcd},
+			time.Date(2021, 8, 9, 16, 0, 0, 0, time.UTC),
+		}
+		tt.wantString = "SELECT 1, 0, HEXTORAW('abcd'), TIMESTAMP '2021-08-09 16:00:00 +00:00'"
+		assert(t, tt)
+	})
 }
@@ -0,0 +1,103 @@
+package sq
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type GroupingType string
+
+const (
+	GroupingTypeRollup       GroupingType = "ROLLUP"
+	GroupingTypeCube         GroupingType = "CUBE"
+	GroupingTypeGroupingSets GroupingType = "GROUPING SETS"
+)
+
+// GroupingElement is a ROLLUP, CUBE or GROUPING SETS element that can be
+// passed to GroupBy alongside regular fields.
+type GroupingElement struct {
+	GroupingType GroupingType
+	Fields       Fields
+	Sets         []Fields
+}
+
+var _ Field = GroupingElement{}
+
+func Rollup(fields ...Field) GroupingElement {
+	return GroupingElement{GroupingType: GroupingTypeRollup, Fields: fields}
+}
+
+func Cube(fields ...Field) GroupingElement {
+	return GroupingElement{GroupingType: GroupingTypeCube, Fields: fields}
+}
+
+// GroupingSets creates a GROUPING SETS element. Each set is rendered as a
+// parenthesized list, an empty set renders as the grand total ().
+func GroupingSets(sets ...Fields) GroupingElement {
+	return GroupingElement{GroupingType: GroupingTypeGroupingSets, Sets: sets}
+}
+
+func (g GroupingElement) AppendSQLExclude(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, excludedTableQualifiers []string) error {
+	switch dialect {
+	case DialectSQLite:
+		return fmt.Errorf("sqlite does not support %s", g.GroupingType)
+	case DialectMySQL:
+		if g.GroupingType == GroupingTypeRollup {
+			return fmt.Errorf("mysql only supports ROLLUP as the sole GROUP BY element (rendered as WITH ROLLUP)")
+		}
+		return fmt.Errorf("mysql does not support %s", g.GroupingType)
+	}
+	var err error
+	switch g.GroupingType {
+	case GroupingTypeRollup, GroupingTypeCube:
+		buf.WriteString(string(g.GroupingType) + " (")
+		err = g.Fields.AppendSQLExclude(dialect, buf, args, params, env, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("%s: %w", g.GroupingType, err)
+		}
+		buf.WriteString(")")
+	case GroupingTypeGroupingSets:
+		buf.WriteString("GROUPING SETS (")
+		for i, set := range g.Sets {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("(")
+			err = set.AppendSQLExclude(dialect, buf, args, params, env, excludedTableQualifiers)
+			if err != nil {
+				return fmt.Errorf("GROUPING SETS set #%d: %w", i+1, err)
+			}
+			buf.WriteString(")")
+		}
+		buf.WriteString(")")
+	default:
+		return fmt.Errorf("unknown grouping type %q", g.GroupingType)
+	}
+	return nil
+}
+
+func (g GroupingElement) GetAlias() string { return "" }
+
+func (g GroupingElement) GetName() string { return "" }
+
+// Grouping returns the GROUPING() of the fields, which indicates whether each
+// field has been aggregated away by a ROLLUP, CUBE or GROUPING SETS.
+func Grouping(fields ...Field) NumberField {
+	return NumberFieldf("GROUPING({})", Fields(fields))
+}
+
+// appendGroupByFields writes the GROUP BY list. For mysql, a ROLLUP that is
+// the only GROUP BY element is written as the legacy WITH ROLLUP modifier.
+func appendGroupByFields(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, fields Fields) error {
+	if dialect == DialectMySQL && len(fields) == 1 {
+		if g, ok := fields[0].(GroupingElement); ok && g.GroupingType == GroupingTypeRollup {
+			err := g.Fields.AppendSQLExclude(dialect, buf, args, params, env, nil)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(" WITH ROLLUP")
+			return nil
+		}
+	}
+	return fields.AppendSQLExclude(dialect, buf, args, params, env, nil)
+}
@@ -0,0 +1,117 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_GroupingElement(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("postgres ROLLUP", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		FILM := xNEW_FILM("f")
+		tt.item = Postgres.
+			Select(FILM.RATING, FILM.RELEASE_YEAR, Grouping(FILM.RATING, FILM.RELEASE_YEAR), Count(FILM.FILM_ID)).
+			From(FILM).
+			GroupBy(FILM.LANGUAGE_ID, Rollup(FILM.RATING, FILM.RELEASE_YEAR))
+		tt.wantQuery = "SELECT f.rating, f.release_year, GROUPING(f.rating, f.release_year), COUNT(f.film_id)" +
+			" FROM film AS f" +
+			" GROUP BY f.language_id, ROLLUP (f.rating, f.release_year)"
+		assert(t, tt)
+	})
+
+	t.Run("postgres CUBE", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		FILM := xNEW_FILM("")
+		tt.item = Postgres.
+			Select(FILM.RATING, FILM.RELEASE_YEAR, Count(FILM.FILM_ID)).
+			From(FILM).
+			GroupBy(Cube(FILM.RATING, FILM.RELEASE_YEAR))
+		tt.wantQuery = "SELECT film.rating, film.release_year, COUNT(film.film_id)" +
+			" FROM film" +
+			" GROUP BY CUBE (film.rating, film.release_year)"
+		assert(t, tt)
+	})
+
+	t.Run("postgres GROUPING SETS", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		FILM := xNEW_FILM("")
+		tt.item = Postgres.
+			Select(FILM.RATING, FILM.RELEASE_YEAR, Count(FILM.FILM_ID)).
+			From(FILM).
+			GroupBy(GroupingSets(Fields{FILM.RATING, FILM.RELEASE_YEAR}, Fields{FILM.RATING}, Fields{}))
+		tt.wantQuery = "SELECT film.rating, film.release_year, COUNT(film.film_id)" +
+			" FROM film" +
+			" GROUP BY GROUPING SETS ((film.rating, film.release_year), (film.rating), ())"
+		assert(t, tt)
+	})
+
+	t.Run("mysql WITH ROLLUP", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		FILM := xNEW_FILM("f")
+		tt.item = MySQL.
+			Select(FILM.RATING, FILM.RELEASE_YEAR, Grouping(FILM.RATING), Count(FILM.FILM_ID)).
+			From(FILM).
+			GroupBy(Rollup(FILM.RATING, FILM.RELEASE_YEAR))
+		tt.wantQuery = "SELECT f.rating, f.release_year, GROUPING(f.rating), COUNT(f.film_id)" +
+			" FROM film AS f" +
+			" GROUP BY f.rating, f.release_year WITH ROLLUP"
+		assert(t, tt)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+		FILM := xNEW_FILM("")
+		tests := []struct {
+			description string
+			dialect     string
+			groupBy     Fields
+		}{
+			{"sqlite ROLLUP", DialectSQLite, Fields{Rollup(FILM.RATING)}},
+			{"sqlite CUBE", DialectSQLite, Fields{Cube(FILM.RATING)}},
+			{"sqlite GROUPING SETS", DialectSQLite, Fields{GroupingSets(Fields{FILM.RATING})}},
+			{"mysql CUBE", DialectMySQL, Fields{Cube(FILM.RATING)}},
+			{"mysql GROUPING SETS", DialectMySQL, Fields{GroupingSets(Fields{FILM.RATING})}},
+			{"mysql ROLLUP with other fields", DialectMySQL, Fields{FILM.LANGUAGE_ID, Rollup(FILM.RATING)}},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.description, func(t *testing.T) {
+				t.Parallel()
+				_, _, _, err := ToSQL("", SelectQuery{
+					Dialect:       tt.dialect,
+					SelectFields:  AliasFields{FILM.RATING},
+					FromTable:     FILM,
+					GroupByFields: tt.groupBy,
+				})
+				if err == nil {
+					t.Error(testutil.Callers(), "expected error but got nil")
+				}
+			})
+		}
+	})
+}
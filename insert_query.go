@@ -31,6 +31,7 @@ type InsertQuery struct {
 	ResolutionPredicate VariadicPredicate
 	// RETURNING
 	ReturningFields AliasFields
+	ReturningInto   []interface{}
 }
 
 var _ Query = (*InsertQuery)(nil)
@@ -55,6 +56,10 @@ func (q InsertQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("WITH: %w", err)
 		}
 	}
+	// MERGE (sqlserver and oracle upsert)
+	if (dialect == DialectSQLServer || dialect == DialectOracle) && (len(q.ConflictFields) > 0 || q.ConflictConstraint != "" || q.ConflictDoNothing || len(q.Resolution) > 0) {
+		return q.appendMerge(dialect, buf, args, params, env)
+	}
 	// INSERT INTO
 	if q.InsertIgnore {
 		if dialect != DialectMySQL {
@@ -72,10 +77,10 @@ func (q InsertQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 		return fmt.Errorf("INSERT INTO: %w", err)
 	}
 	if alias := q.IntoTable.GetAlias(); alias != "" {
-		if dialect == DialectMySQL {
-			return fmt.Errorf("mysql does not allow an alias for the INSERT table")
+		if dialect == DialectMySQL || dialect == DialectSQLServer {
+			return fmt.Errorf("%s does not allow an alias for the INSERT table", dialect)
 		}
-		buf.WriteString(" AS " + QuoteIdentifier(dialect, alias))
+		writeTableAlias(dialect, buf, alias)
 		excludedTableQualifiers = append(excludedTableQualifiers, alias)
 	} else {
 		name := q.IntoTable.GetName()
@@ -89,9 +94,20 @@ func (q InsertQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 		}
 		buf.WriteString(")")
 	}
+	// OUTPUT (sqlserver)
+	if len(q.ReturningFields) > 0 && dialect == DialectSQLServer {
+		buf.WriteString(" OUTPUT ")
+		err = appendOutputFields(dialect, buf, args, params, env, "INSERTED", q.ReturningFields, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("OUTPUT: %w", err)
+		}
+	}
 	// VALUES/SELECT
 	switch {
 	case len(q.RowValues) > 0:
+		if len(q.RowValues) > 1 && dialect == DialectOracle {
+			return fmt.Errorf("oracle does not support inserting multiple rows with VALUES, use INSERT ... SELECT instead")
+		}
 		buf.WriteString(" VALUES ")
 		err = q.RowValues.AppendSQL(dialect, buf, args, params, env)
 		if err != nil {
@@ -180,8 +196,16 @@ func (q InsertQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			}
 		}
 	}
+	// RETURNING ... INTO (oracle)
+	if len(q.ReturningFields) > 0 && dialect == DialectOracle {
+		err = appendReturningInto(dialect, buf, args, params, env, q.ReturningFields, q.ReturningInto, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("RETURNING: %w", err)
+		}
+		return nil
+	}
 	// RETURNING
-	if len(q.ReturningFields) > 0 {
+	if len(q.ReturningFields) > 0 && dialect != DialectSQLServer {
 		if dialect != DialectPostgres && dialect != DialectSQLite {
 			return fmt.Errorf("%s DELETE does not support RETURNING", dialect)
 		}
@@ -194,9 +218,167 @@ func (q InsertQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 	return nil
 }
 
+// appendMerge writes an upsert for sqlserver and oracle. Neither of them
+// have an ON CONFLICT clause, so the rows to be inserted are used as the
+// source of a MERGE statement aliased as EXCLUDED (so that AssignExcluded
+// works the same way as it does for postgres and sqlite), and the conflict
+// fields become the MERGE condition.
+func (q InsertQuery) appendMerge(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	var err error
+	if q.ConflictConstraint != "" {
+		return fmt.Errorf("%s does not support ON CONFLICT ON CONSTRAINT", dialect)
+	}
+	if len(q.ConflictFields) == 0 {
+		return fmt.Errorf("%s MERGE requires conflict fields", dialect)
+	}
+	if q.ConflictPredicate != nil {
+		return fmt.Errorf("%s MERGE does not support a conflict predicate", dialect)
+	}
+	if len(q.InsertColumns) == 0 {
+		return fmt.Errorf("%s MERGE requires the columns to be inserted", dialect)
+	}
+	if len(q.ReturningFields) > 0 && dialect == DialectOracle {
+		return fmt.Errorf("oracle MERGE does not support RETURNING")
+	}
+	// MERGE INTO
+	buf.WriteString("MERGE INTO ")
+	if q.IntoTable == nil {
+		return fmt.Errorf("no table provided to INSERT")
+	}
+	err = q.IntoTable.AppendSQL(dialect, buf, args, params, env)
+	if err != nil {
+		return fmt.Errorf("MERGE INTO: %w", err)
+	}
+	tableQualifier := q.IntoTable.GetAlias()
+	if tableQualifier != "" {
+		writeTableAlias(dialect, buf, tableQualifier)
+	} else {
+		tableQualifier = q.IntoTable.GetName()
+	}
+	excludedTableQualifiers := []string{tableQualifier}
+	columnNames := make([]string, len(q.InsertColumns))
+	for i, field := range q.InsertColumns {
+		if field == nil {
+			return fmt.Errorf("column #%d is nil", i+1)
+		}
+		columnNames[i] = QuoteIdentifier(dialect, field.GetName())
+	}
+	// USING
+	buf.WriteString(" USING (")
+	switch {
+	case len(q.RowValues) > 0 && dialect == DialectOracle:
+		// oracle has no VALUES table constructor, each row is SELECT-ed
+		// from DUAL instead.
+		for i, rowValue := range q.RowValues {
+			if i > 0 {
+				buf.WriteString(" UNION ALL ")
+			}
+			if len(rowValue) != len(columnNames) {
+				return fmt.Errorf("rowvalues #%d: expected %d values, got %d", i+1, len(columnNames), len(rowValue))
+			}
+			buf.WriteString("SELECT ")
+			for j, value := range rowValue {
+				if j > 0 {
+					buf.WriteString(", ")
+				}
+				err = BufferPrintValue(dialect, buf, args, params, env, nil, value, "")
+				if err != nil {
+					return fmt.Errorf("rowvalues #%d: rowvalue #%d: %w", i+1, j+1, err)
+				}
+				buf.WriteString(" " + columnNames[j])
+			}
+			buf.WriteString(" FROM DUAL")
+		}
+	case len(q.RowValues) > 0:
+		buf.WriteString("VALUES ")
+		err = q.RowValues.AppendSQL(dialect, buf, args, params, env)
+		if err != nil {
+			return fmt.Errorf("VALUES: %w", err)
+		}
+	case q.SelectQuery != nil:
+		err = q.SelectQuery.AppendSQL(dialect, buf, args, params, env)
+		if err != nil {
+			return fmt.Errorf("SELECT: %w", err)
+		}
+	default:
+		return fmt.Errorf("RowValues not provided and SelectQuery not provided to INSERT query")
+	}
+	if dialect == DialectOracle {
+		buf.WriteString(") EXCLUDED")
+	} else {
+		buf.WriteString(") AS EXCLUDED (" + strings.Join(columnNames, ", ") + ")")
+	}
+	// ON
+	buf.WriteString(" ON ")
+	if dialect == DialectOracle {
+		buf.WriteString("(")
+	}
+	for i, field := range q.ConflictFields {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		if field == nil {
+			return fmt.Errorf("conflict field #%d is nil", i+1)
+		}
+		name := QuoteIdentifier(dialect, field.GetName())
+		buf.WriteString(QuoteIdentifier(dialect, tableQualifier) + "." + name + " = EXCLUDED." + name)
+	}
+	if dialect == DialectOracle {
+		buf.WriteString(")")
+	}
+	// WHEN MATCHED
+	if len(q.Resolution) > 0 && !q.ConflictDoNothing {
+		buf.WriteString(" WHEN MATCHED")
+		if len(q.ResolutionPredicate.Predicates) > 0 && dialect == DialectSQLServer {
+			buf.WriteString(" AND ")
+			q.ResolutionPredicate.Toplevel = true
+			err = q.ResolutionPredicate.AppendSQLExclude(dialect, buf, args, params, env, nil)
+			if err != nil {
+				return fmt.Errorf("WHEN MATCHED AND: %w", err)
+			}
+		}
+		buf.WriteString(" THEN UPDATE SET ")
+		err = q.Resolution.AppendSQLExclude(dialect, buf, args, params, env, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("UPDATE SET: %w", err)
+		}
+		if len(q.ResolutionPredicate.Predicates) > 0 && dialect == DialectOracle {
+			buf.WriteString(" WHERE ")
+			q.ResolutionPredicate.Toplevel = true
+			err = q.ResolutionPredicate.AppendSQLExclude(dialect, buf, args, params, env, nil)
+			if err != nil {
+				return fmt.Errorf("UPDATE SET ... WHERE: %w", err)
+			}
+		}
+	}
+	// WHEN NOT MATCHED
+	buf.WriteString(" WHEN NOT MATCHED THEN INSERT (" + strings.Join(columnNames, ", ") + ") VALUES (")
+	for i, columnName := range columnNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("EXCLUDED." + columnName)
+	}
+	buf.WriteString(")")
+	if dialect == DialectOracle {
+		return nil
+	}
+	// OUTPUT
+	if len(q.ReturningFields) > 0 {
+		buf.WriteString(" OUTPUT ")
+		err = appendOutputFields(dialect, buf, args, params, env, "INSERTED", q.ReturningFields, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("OUTPUT: %w", err)
+		}
+	}
+	// a sqlserver MERGE statement must be terminated by a semicolon
+	buf.WriteString(";")
+	return nil
+}
+
 func (q InsertQuery) SetFetchableFields(fields []Field) (Query, error) {
 	switch q.Dialect {
-	case DialectPostgres, DialectSQLite:
+	case DialectPostgres, DialectSQLite, DialectSQLServer:
 		q.ReturningFields = fields
 		return q, nil
 	default:
@@ -206,7 +388,7 @@ func (q InsertQuery) SetFetchableFields(fields []Field) (Query, error) {
 
 func (q InsertQuery) GetFetchableFields() ([]Field, error) {
 	switch q.Dialect {
-	case DialectPostgres, DialectSQLite:
+	case DialectPostgres, DialectSQLite, DialectSQLServer:
 		return q.ReturningFields, nil
 	default:
 		return nil, fmt.Errorf("%s INSERT %w", q.Dialect, ErrNonFetchableQuery)
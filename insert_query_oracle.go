@@ -0,0 +1,110 @@
+package sq
+
+import "bytes"
+
+type OracleInsertQuery InsertQuery
+
+var _ Query = OracleInsertQuery{}
+
+func (q OracleInsertQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return InsertQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q OracleInsertQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return InsertQuery(q).SetFetchableFields(fields)
+}
+
+func (q OracleInsertQuery) GetFetchableFields() ([]Field, error) {
+	return InsertQuery(q).GetFetchableFields()
+}
+
+func (q OracleInsertQuery) GetDialect() string { return q.Dialect }
+
+func (d OracleQueryBuilder) InsertWith(ctes ...CTE) OracleInsertQuery {
+	return OracleInsertQuery{
+		Env:     d.env,
+		Dialect: DialectOracle,
+		CTEs:    ctes,
+	}
+}
+
+func (d OracleQueryBuilder) InsertInto(table SchemaTable) OracleInsertQuery {
+	return OracleInsertQuery{
+		Env:       d.env,
+		Dialect:   DialectOracle,
+		IntoTable: table,
+	}
+}
+
+func (q OracleInsertQuery) With(ctes ...CTE) OracleInsertQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q OracleInsertQuery) InsertInto(table SchemaTable) OracleInsertQuery {
+	q.IntoTable = table
+	return q
+}
+
+func (q OracleInsertQuery) Columns(fields ...Field) OracleInsertQuery {
+	q.InsertColumns = fields
+	return q
+}
+
+func (q OracleInsertQuery) Values(values ...interface{}) OracleInsertQuery {
+	q.RowValues = append(q.RowValues, values)
+	return q
+}
+
+func (q OracleInsertQuery) Valuesx(mapper func(*Column) error) OracleInsertQuery {
+	q.ColumnMapper = mapper
+	return q
+}
+
+func (q OracleInsertQuery) Select(query OracleSelectQuery) OracleInsertQuery {
+	selectQuery := SelectQuery(query)
+	q.SelectQuery = &selectQuery
+	return q
+}
+
+type OracleInsertConflict struct {
+	insertQuery *OracleInsertQuery
+}
+
+// OnConflict turns the INSERT into a MERGE statement that matches existing
+// rows on the given fields. The rows being inserted can be referred to as
+// EXCLUDED in DoUpdateSet, e.g. AssignExcluded(field).
+func (q OracleInsertQuery) OnConflict(fields ...Field) OracleInsertConflict {
+	var c OracleInsertConflict
+	q.ConflictFields = fields
+	c.insertQuery = &q
+	return c
+}
+
+func (c OracleInsertConflict) DoNothing() OracleInsertQuery {
+	q := c.insertQuery
+	q.ConflictDoNothing = true
+	return *q
+}
+
+func (c OracleInsertConflict) DoUpdateSet(assignments ...Assignment) OracleInsertQuery {
+	c.insertQuery.Resolution = assignments
+	return *c.insertQuery
+}
+
+func (q OracleInsertQuery) Where(predicates ...Predicate) OracleInsertQuery {
+	q.ResolutionPredicate.Predicates = append(q.ResolutionPredicate.Predicates, predicates...)
+	return q
+}
+
+func (q OracleInsertQuery) Returning(fields ...Field) OracleInsertQuery {
+	q.ReturningFields = append(q.ReturningFields, fields...)
+	return q
+}
+
+// Into provides the destinations that the RETURNING fields are written into.
+// Each destination should be a pointer, it is bound as an sql.Out argument.
+func (q OracleInsertQuery) Into(dests ...interface{}) OracleInsertQuery {
+	q.ReturningInto = append(q.ReturningInto, dests...)
+	return q
+}
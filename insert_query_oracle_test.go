@@ -0,0 +1,130 @@
+package sq
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_OracleInsertQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("INSERT with RETURNING INTO", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		var actorID int64
+		var lastUpdate sql.NullTime
+		tt.item = Oracle.
+			InsertInto(ACTOR).
+			Columns(ACTOR.FIRST_NAME, ACTOR.LAST_NAME).
+			Values("bob", "the builder").
+			Returning(ACTOR.ACTOR_ID, ACTOR.LAST_UPDATE).
+			Into(&actorID, sql.Out{Dest: &lastUpdate})
+		tt.wantQuery = "INSERT INTO actor a (first_name, last_name)" +
+			" VALUES (:1, :2)" +
+			" RETURNING actor_id, last_update INTO :3, :4"
+		tt.wantArgs = []interface{}{"bob", "the builder", sql.Out{Dest: &actorID}, sql.Out{Dest: &lastUpdate}}
+		assert(t, tt)
+	})
+
+	t.Run("multiple rows", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("")
+		_, _, _, err := ToSQL("", Oracle.
+			InsertInto(ACTOR).
+			Columns(ACTOR.FIRST_NAME, ACTOR.LAST_NAME).
+			Values("bob", "the builder").
+			Values("alice", "in wonderland"),
+		)
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+
+	t.Run("RETURNING without INTO", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("")
+		_, _, _, err := ToSQL("", Oracle.
+			InsertInto(ACTOR).
+			Columns(ACTOR.FIRST_NAME).
+			Values("bob").
+			Returning(ACTOR.ACTOR_ID),
+		)
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = Oracle.
+			InsertInto(ACTOR).
+			Valuesx(func(c *Column) error {
+				// bob
+				c.SetInt64(ACTOR.ACTOR_ID, 1)
+				c.SetString(ACTOR.FIRST_NAME, "bob")
+				c.SetString(ACTOR.LAST_NAME, "the builder")
+				// alice
+				c.SetInt64(ACTOR.ACTOR_ID, 2)
+				c.SetString(ACTOR.FIRST_NAME, "alice")
+				c.SetString(ACTOR.LAST_NAME, "in wonderland")
+				return nil
+			}).
+			OnConflict(ACTOR.ACTOR_ID).
+			DoUpdateSet(AssignExcluded(ACTOR.FIRST_NAME), AssignExcluded(ACTOR.LAST_NAME)).
+			Where(ACTOR.LAST_NAME.NeString(""))
+		tt.wantQuery = "MERGE INTO actor a" +
+			" USING (SELECT :1 actor_id, :2 first_name, :3 last_name FROM DUAL" +
+			" UNION ALL SELECT :4 actor_id, :5 first_name, :6 last_name FROM DUAL) EXCLUDED" +
+			" ON (a.actor_id = EXCLUDED.actor_id)" +
+			" WHEN MATCHED THEN UPDATE SET first_name = EXCLUDED.first_name, last_name = EXCLUDED.last_name" +
+			" WHERE a.last_name <> :7" +
+			" WHEN NOT MATCHED THEN INSERT (actor_id, first_name, last_name) VALUES (EXCLUDED.actor_id, EXCLUDED.first_name, EXCLUDED.last_name)"
+		tt.wantArgs = []interface{}{int64(1), "bob", "the builder", int64(2), "alice", "in wonderland", ""}
+		assert(t, tt)
+	})
+
+	t.Run("INSERT ignore duplicates from SELECT", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR1, ACTOR2 := xNEW_ACTOR(""), xNEW_ACTOR("a2")
+		tt.item = Oracle.
+			InsertInto(ACTOR1).
+			Columns(ACTOR1.ACTOR_ID, ACTOR1.FIRST_NAME).
+			Select(Oracle.
+				Select(ACTOR2.ACTOR_ID, ACTOR2.FIRST_NAME).
+				From(ACTOR2).
+				Where(ACTOR2.ACTOR_ID.In([]int64{1, 2})),
+			).
+			OnConflict(ACTOR1.ACTOR_ID).
+			DoNothing()
+		tt.wantQuery = "MERGE INTO actor" +
+			" USING (SELECT a2.actor_id, a2.first_name FROM actor a2 WHERE a2.actor_id IN (:1, :2)) EXCLUDED" +
+			" ON (actor.actor_id = EXCLUDED.actor_id)" +
+			" WHEN NOT MATCHED THEN INSERT (actor_id, first_name) VALUES (EXCLUDED.actor_id, EXCLUDED.first_name)"
+		tt.wantArgs = []interface{}{int64(1), int64(2)}
+		assert(t, tt)
+	})
+}
@@ -0,0 +1,103 @@
+package sq
+
+import "bytes"
+
+type SQLServerInsertQuery InsertQuery
+
+var _ Query = SQLServerInsertQuery{}
+
+func (q SQLServerInsertQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return InsertQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q SQLServerInsertQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return InsertQuery(q).SetFetchableFields(fields)
+}
+
+func (q SQLServerInsertQuery) GetFetchableFields() ([]Field, error) {
+	return InsertQuery(q).GetFetchableFields()
+}
+
+func (q SQLServerInsertQuery) GetDialect() string { return q.Dialect }
+
+func (d SQLServerQueryBuilder) InsertWith(ctes ...CTE) SQLServerInsertQuery {
+	return SQLServerInsertQuery{
+		Env:     d.env,
+		Dialect: DialectSQLServer,
+		CTEs:    ctes,
+	}
+}
+
+func (d SQLServerQueryBuilder) InsertInto(table SchemaTable) SQLServerInsertQuery {
+	return SQLServerInsertQuery{
+		Env:       d.env,
+		Dialect:   DialectSQLServer,
+		IntoTable: table,
+	}
+}
+
+func (q SQLServerInsertQuery) With(ctes ...CTE) SQLServerInsertQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q SQLServerInsertQuery) InsertInto(table SchemaTable) SQLServerInsertQuery {
+	q.IntoTable = table
+	return q
+}
+
+func (q SQLServerInsertQuery) Columns(fields ...Field) SQLServerInsertQuery {
+	q.InsertColumns = fields
+	return q
+}
+
+func (q SQLServerInsertQuery) Values(values ...interface{}) SQLServerInsertQuery {
+	q.RowValues = append(q.RowValues, values)
+	return q
+}
+
+func (q SQLServerInsertQuery) Valuesx(mapper func(*Column) error) SQLServerInsertQuery {
+	q.ColumnMapper = mapper
+	return q
+}
+
+func (q SQLServerInsertQuery) Select(query SQLServerSelectQuery) SQLServerInsertQuery {
+	selectQuery := SelectQuery(query)
+	q.SelectQuery = &selectQuery
+	return q
+}
+
+type SQLServerInsertConflict struct {
+	insertQuery *SQLServerInsertQuery
+}
+
+// OnConflict turns the INSERT into a MERGE statement that matches existing
+// rows on the given fields. The rows being inserted can be referred to as
+// EXCLUDED in DoUpdateSet, e.g. AssignExcluded(field).
+func (q SQLServerInsertQuery) OnConflict(fields ...Field) SQLServerInsertConflict {
+	var c SQLServerInsertConflict
+	q.ConflictFields = fields
+	c.insertQuery = &q
+	return c
+}
+
+func (c SQLServerInsertConflict) DoNothing() SQLServerInsertQuery {
+	q := c.insertQuery
+	q.ConflictDoNothing = true
+	return *q
+}
+
+func (c SQLServerInsertConflict) DoUpdateSet(assignments ...Assignment) SQLServerInsertQuery {
+	c.insertQuery.Resolution = assignments
+	return *c.insertQuery
+}
+
+func (q SQLServerInsertQuery) Where(predicates ...Predicate) SQLServerInsertQuery {
+	q.ResolutionPredicate.Predicates = append(q.ResolutionPredicate.Predicates, predicates...)
+	return q
+}
+
+func (q SQLServerInsertQuery) Output(fields ...Field) SQLServerInsertQuery {
+	q.ReturningFields = append(q.ReturningFields, fields...)
+	return q
+}
@@ -0,0 +1,157 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_SQLServerInsertQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		tt.item = SQLServer.
+			InsertInto(ACTOR).
+			InsertInto(ACTOR).
+			Columns(ACTOR.FIRST_NAME, ACTOR.LAST_NAME).
+			Values("bob", "the builder").
+			Values("alice", "in wonderland").
+			With(NewCTE("cte", []string{"n"}, Queryf("SELECT 1")))
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1)" +
+			" INSERT INTO actor (first_name, last_name)" +
+			" VALUES (@p1, @p2), (@p3, @p4)"
+		tt.wantArgs = []interface{}{"bob", "the builder", "alice", "in wonderland"}
+		assert(t, tt)
+	})
+
+	t.Run("INSERT with OUTPUT", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		tt.item = SQLServer.
+			InsertInto(ACTOR).
+			Valuesx(func(c *Column) error {
+				// bob
+				c.SetString(ACTOR.FIRST_NAME, "bob")
+				c.SetString(ACTOR.LAST_NAME, "the builder")
+				// alice
+				c.SetString(ACTOR.FIRST_NAME, "alice")
+				c.SetString(ACTOR.LAST_NAME, "in wonderland")
+				return nil
+			}).
+			Output(ACTOR.ACTOR_ID, ACTOR.LAST_UPDATE.As("updated_at"))
+		tt.wantQuery = "INSERT INTO actor (first_name, last_name)" +
+			" OUTPUT INSERTED.actor_id, INSERTED.last_update AS updated_at" +
+			" VALUES (@p1, @p2), (@p3, @p4)"
+		tt.wantArgs = []interface{}{"bob", "the builder", "alice", "in wonderland"}
+		assert(t, tt)
+	})
+
+	t.Run("INSERT ignore duplicates", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		tt.item = SQLServer.
+			InsertInto(ACTOR).
+			Valuesx(func(c *Column) error {
+				// bob
+				c.SetInt64(ACTOR.ACTOR_ID, 1)
+				c.SetString(ACTOR.FIRST_NAME, "bob")
+				// alice
+				c.SetInt64(ACTOR.ACTOR_ID, 2)
+				c.SetString(ACTOR.FIRST_NAME, "alice")
+				return nil
+			}).
+			OnConflict(ACTOR.ACTOR_ID).
+			DoNothing()
+		tt.wantQuery = "MERGE INTO actor" +
+			" USING (VALUES (@p1, @p2), (@p3, @p4)) AS EXCLUDED (actor_id, first_name)" +
+			" ON actor.actor_id = EXCLUDED.actor_id" +
+			" WHEN NOT MATCHED THEN INSERT (actor_id, first_name) VALUES (EXCLUDED.actor_id, EXCLUDED.first_name);"
+		tt.wantArgs = []interface{}{int64(1), "bob", int64(2), "alice"}
+		assert(t, tt)
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = SQLServer.
+			InsertWith(NewCTE("cte", []string{"n"}, Queryf("SELECT 1"))).
+			InsertInto(ACTOR).
+			Valuesx(func(c *Column) error {
+				// bob
+				c.SetInt64(ACTOR.ACTOR_ID, 1)
+				c.SetString(ACTOR.FIRST_NAME, "bob")
+				c.SetString(ACTOR.LAST_NAME, "the builder")
+				// alice
+				c.SetInt64(ACTOR.ACTOR_ID, 2)
+				c.SetString(ACTOR.FIRST_NAME, "alice")
+				c.SetString(ACTOR.LAST_NAME, "in wonderland")
+				return nil
+			}).
+			OnConflict(ACTOR.ACTOR_ID).
+			DoUpdateSet(AssignExcluded(ACTOR.FIRST_NAME), AssignExcluded(ACTOR.LAST_NAME)).
+			Where(ACTOR.LAST_NAME.NeString("")).
+			Output(ACTOR.ACTOR_ID)
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1)" +
+			" MERGE INTO actor AS a" +
+			" USING (VALUES (@p1, @p2, @p3), (@p4, @p5, @p6)) AS EXCLUDED (actor_id, first_name, last_name)" +
+			" ON a.actor_id = EXCLUDED.actor_id" +
+			" WHEN MATCHED AND a.last_name <> @p7 THEN UPDATE SET first_name = EXCLUDED.first_name, last_name = EXCLUDED.last_name" +
+			" WHEN NOT MATCHED THEN INSERT (actor_id, first_name, last_name) VALUES (EXCLUDED.actor_id, EXCLUDED.first_name, EXCLUDED.last_name)" +
+			" OUTPUT INSERTED.actor_id;"
+		tt.wantArgs = []interface{}{int64(1), "bob", "the builder", int64(2), "alice", "in wonderland", ""}
+		assert(t, tt)
+	})
+
+	t.Run("INSERT from SELECT", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR1, ACTOR2 := xNEW_ACTOR(""), xNEW_ACTOR("a2")
+		tt.item = SQLServer.
+			InsertInto(ACTOR1).
+			Columns(ACTOR1.FIRST_NAME, ACTOR1.LAST_NAME).
+			Select(SQLServer.
+				Select(ACTOR2.FIRST_NAME, ACTOR2.LAST_NAME).
+				From(ACTOR2).
+				Where(ACTOR2.ACTOR_ID.In([]int64{1, 2})),
+			)
+		tt.wantQuery = "INSERT INTO actor (first_name, last_name)" +
+			" SELECT a2.first_name, a2.last_name" +
+			" FROM actor AS a2" +
+			" WHERE a2.actor_id IN (@p1, @p2)"
+		tt.wantArgs = []interface{}{int64(1), int64(2)}
+		assert(t, tt)
+	})
+
+	t.Run("INSERT table alias", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("a")
+		_, _, _, err := ToSQL("", SQLServer.InsertInto(ACTOR).Columns(ACTOR.FIRST_NAME).Values("bob"))
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+}
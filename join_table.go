@@ -92,8 +92,7 @@ func (join JoinTable) AppendSQL(dialect string, buf *bytes.Buffer, args *[]inter
 		return err
 	}
 	if tableAlias := join.Table.GetAlias(); tableAlias != "" {
-		buf.WriteString(" AS ")
-		buf.WriteString(QuoteIdentifier(dialect, tableAlias))
+		writeTableAlias(dialect, buf, tableAlias)
 	}
 	if len(join.OnPredicate.Predicates) > 0 {
 		buf.WriteString(" ON ")
@@ -1,6 +1,13 @@
 package sq
 
-import "bytes"
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type JSONField struct {
 	info FieldInfo
@@ -15,6 +22,13 @@ func NewJSONField(fieldName string, tableInfo TableInfo) JSONField {
 	}}
 }
 
+func JSONFieldf(format string, values ...interface{}) JSONField {
+	return JSONField{info: FieldInfo{
+		Formats: [][2]string{{"default", format}},
+		Values:  values,
+	}}
+}
+
 var _ Field = JSONField{}
 
 func (f JSONField) AppendSQLExclude(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, excludedTableQualifiers []string) error {
@@ -53,3 +67,255 @@ func (f JSONField) NullsFirst() JSONField {
 	f.info.NullsFirst.Bool = true
 	return f
 }
+
+func (f JSONField) IsNull() Predicate { return IsNull(f) }
+
+func (f JSONField) IsNotNull() Predicate { return IsNotNull(f) }
+
+func (f JSONField) Set(val interface{}) Assignment { return Assign(f, val) }
+
+// SetJSON assigns the JSON encoding of val to the field.
+func (f JSONField) SetJSON(val interface{}) Assignment { return Assign(f, JSONValue(val)) }
+
+// Get extracts the JSON value at path. The path is a sequence of object keys
+// and array indices e.g. "$.address.lines[0]", the leading "$." is optional.
+func (f JSONField) Get(path string) JSONField {
+	return JSONFieldf("{}", jsonExtract{field: f, path: path})
+}
+
+// GetText extracts the value at path as unquoted text.
+func (f JSONField) GetText(path string) StringField {
+	return StringFieldf("{}", jsonExtract{field: f, path: path, asText: true})
+}
+
+// GetNumber extracts the value at path as a number.
+func (f JSONField) GetNumber(path string) NumberField {
+	return NumberFieldf("{}", jsonExtract{field: f, path: path, asNumber: true})
+}
+
+// Contains checks if the field contains the JSON encoding of val at the top
+// level. It is not supported by sqlite.
+func (f JSONField) Contains(val interface{}) Predicate {
+	return Predicatef("{}", jsonPredicate{field: f, operator: "@>", value: JSONValue(val)})
+}
+
+// HasKey checks if the field is an object with the top level key.
+func (f JSONField) HasKey(key string) Predicate {
+	return Predicatef("{}", jsonPredicate{field: f, operator: "?", value: key})
+}
+
+type jsonValue struct {
+	value interface{}
+}
+
+// JSONValue wraps val so that it is passed to the database as its JSON
+// encoding. json.RawMessage values are passed through as-is.
+func JSONValue(val interface{}) driver.Valuer {
+	return jsonValue{value: val}
+}
+
+func (v jsonValue) Value() (driver.Value, error) {
+	switch value := v.value.(type) {
+	case json.RawMessage:
+		return string(value), nil
+	case nil:
+		return "null", nil
+	}
+	b, err := json.Marshal(v.value)
+	if err != nil {
+		return nil, fmt.Errorf("sq: JSON encoding %#v: %w", v.value, err)
+	}
+	return string(b), nil
+}
+
+type jsonPathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func parseJSONPath(path string) ([]jsonPathElement, error) {
+	if path == "$" {
+		return nil, nil
+	}
+	remainder := strings.TrimPrefix(path, "$")
+	remainder = strings.TrimPrefix(remainder, ".")
+	var elements []jsonPathElement
+	for remainder != "" {
+		if remainder[0] == '[' {
+			end := strings.IndexByte(remainder, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSON path %q has an unclosed [", path)
+			}
+			index, err := strconv.Atoi(remainder[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("JSON path %q has an invalid array index %q", path, remainder[1:end])
+			}
+			elements = append(elements, jsonPathElement{index: index, isIndex: true})
+			remainder = strings.TrimPrefix(remainder[end+1:], ".")
+			continue
+		}
+		end := strings.IndexAny(remainder, ".[")
+		if end < 0 {
+			end = len(remainder)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("JSON path %q has an empty key", path)
+		}
+		elements = append(elements, jsonPathElement{key: remainder[:end]})
+		remainder = remainder[end:]
+		if strings.HasPrefix(remainder, ".") {
+			remainder = remainder[1:]
+			if remainder == "" {
+				return nil, fmt.Errorf("JSON path %q has an empty key", path)
+			}
+		}
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("JSON path %q is empty", path)
+	}
+	return elements, nil
+}
+
+// jsonPathString returns the SQL/JSON path used by mysql and sqlite.
+func jsonPathString(elements []jsonPathElement) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, element := range elements {
+		if element.isIndex {
+			b.WriteString("[" + strconv.Itoa(element.index) + "]")
+			continue
+		}
+		isIdentifier := true
+		for i, char := range element.key {
+			if char != '_' && char != '$' && !('a' <= char && char <= 'z') && !('A' <= char && char <= 'Z') && !(i > 0 && '0' <= char && char <= '9') {
+				isIdentifier = false
+				break
+			}
+		}
+		if isIdentifier {
+			b.WriteString("." + element.key)
+		} else {
+			b.WriteString(`."` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(element.key) + `"`)
+		}
+	}
+	return b.String()
+}
+
+// jsonPathArray returns the text array literal used by the postgres #> and
+// #>> operators.
+func jsonPathArray(elements []jsonPathElement) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, element := range elements {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		if element.isIndex {
+			b.WriteString(strconv.Itoa(element.index))
+		} else {
+			b.WriteString(`"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(element.key) + `"`)
+		}
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+type jsonExtract struct {
+	field    JSONField
+	path     string
+	asText   bool
+	asNumber bool
+}
+
+func (e jsonExtract) AppendSQLExclude(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, excludedTableQualifiers []string) error {
+	elements, err := parseJSONPath(e.path)
+	if err != nil {
+		return err
+	}
+	if len(elements) == 0 {
+		return fmt.Errorf("JSON path %q does not select anything", e.path)
+	}
+	switch dialect {
+	case DialectPostgres:
+		var format string
+		var value interface{}
+		if len(elements) == 1 && !elements[0].isIndex {
+			format, value = "{} -> {}", elements[0].key
+			if e.asText || e.asNumber {
+				format = "{} ->> {}"
+			}
+		} else {
+			format, value = "{} #> {}", jsonPathArray(elements)
+			if e.asText || e.asNumber {
+				format = "{} #>> {}"
+			}
+		}
+		if e.asNumber {
+			format = "CAST(" + format + " AS NUMERIC)"
+		}
+		return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, format, []interface{}{e.field, value})
+	case DialectMySQL:
+		path := jsonPathString(elements)
+		if !e.asText {
+			return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, "JSON_EXTRACT({}, {})", []interface{}{e.field, path})
+		}
+		// The ->> operator only accepts a column on the left and a string
+		// literal on the right, everything else has to be spelled out.
+		if len(e.field.info.Formats) > 0 {
+			return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, "JSON_UNQUOTE(JSON_EXTRACT({}, {}))", []interface{}{e.field, path})
+		}
+		err = e.field.AppendSQLExclude(dialect, buf, args, params, env, excludedTableQualifiers)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(" ->> '" + strings.ReplaceAll(path, "'", "''") + "'")
+		return nil
+	case DialectSQLite:
+		return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, "json_extract({}, {})", []interface{}{e.field, jsonPathString(elements)})
+	default:
+		return fmt.Errorf("%s does not support JSON path extraction", dialect)
+	}
+}
+
+type jsonPredicate struct {
+	field    JSONField
+	operator string
+	value    interface{}
+}
+
+func (p jsonPredicate) AppendSQLExclude(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, excludedTableQualifiers []string) error {
+	switch p.operator {
+	case "@>":
+		switch dialect {
+		case DialectPostgres:
+			return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, "{} @> {}", []interface{}{p.field, p.value})
+		case DialectMySQL:
+			return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, "JSON_CONTAINS({}, {})", []interface{}{p.field, p.value})
+		default:
+			return fmt.Errorf("%s does not support JSON containment", dialect)
+		}
+	case "?":
+		key, _ := p.value.(string)
+		path := jsonPathString([]jsonPathElement{{key: key}})
+		switch dialect {
+		case DialectPostgres:
+			// Write the ? operator directly, BufferPrintf would treat it as
+			// an anonymous parameter.
+			err := p.field.AppendSQLExclude(dialect, buf, args, params, env, excludedTableQualifiers)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(" ? ")
+			return BufferPrintValue(dialect, buf, args, params, env, excludedTableQualifiers, key, "")
+		case DialectMySQL:
+			return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, "JSON_CONTAINS_PATH({}, 'one', {})", []interface{}{p.field, path})
+		case DialectSQLite:
+			return BufferPrintf(dialect, buf, args, params, env, excludedTableQualifiers, "json_type({}, {}) IS NOT NULL", []interface{}{p.field, path})
+		default:
+			return fmt.Errorf("%s does not support JSON key lookup", dialect)
+		}
+	default:
+		return fmt.Errorf("unknown JSON operator %q", p.operator)
+	}
+}
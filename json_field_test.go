@@ -70,4 +70,124 @@ func Test_JSONField(t *testing.T) {
 		tt.wantArgs = []interface{}{}
 		assert(t, tt)
 	})
+
+	t.Run("postgres Get", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		tt.item = NewJSONField("data", TableInfo{TableName: "tbl"}).Get("name")
+		tt.wantQuery = "tbl.data -> $1"
+		tt.wantArgs = []interface{}{"name"}
+		assert(t, tt)
+	})
+
+	t.Run("postgres Get nested GetText", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		tt.item = NewJSONField("data", TableInfo{TableName: "tbl"}).Get("address").GetText("$.lines[0]")
+		tt.wantQuery = "tbl.data -> $1 #>> $2"
+		tt.wantArgs = []interface{}{"address", `{"lines",0}`}
+		assert(t, tt)
+	})
+
+	t.Run("postgres GetNumber", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		tt.item = NewJSONField("data", TableInfo{TableName: "tbl"}).GetNumber("age").GtInt(18)
+		tt.wantQuery = "CAST(tbl.data ->> $1 AS NUMERIC) > $2"
+		tt.wantArgs = []interface{}{"age", 18}
+		assert(t, tt)
+	})
+
+	t.Run("postgres Contains and HasKey", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		data := NewJSONField("data", TableInfo{TableName: "tbl"})
+		tt.item = And(data.Contains(map[string]int{"a": 1}), data.HasKey("b"))
+		tt.wantQuery = "(tbl.data @> $1 AND tbl.data ? $2)"
+		tt.wantArgs = []interface{}{JSONValue(map[string]int{"a": 1}), "b"}
+		assert(t, tt)
+	})
+
+	t.Run("mysql Get GetText", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectMySQL
+		data := NewJSONField("data", TableInfo{TableName: "tbl"})
+		tt.item = Fields{data.Get("address.lines[0]"), data.GetText("first name"), data.Get("address").GetText("city")}
+		tt.wantQuery = "JSON_EXTRACT(tbl.data, ?)" +
+			", tbl.data ->> '$.\"first name\"'" +
+			", JSON_UNQUOTE(JSON_EXTRACT(JSON_EXTRACT(tbl.data, ?), ?))"
+		tt.wantArgs = []interface{}{"$.address.lines[0]", "$.address", "$.city"}
+		assert(t, tt)
+	})
+
+	t.Run("mysql Contains and HasKey", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectMySQL
+		data := NewJSONField("data", TableInfo{TableName: "tbl"})
+		tt.item = And(data.Contains([]int{1, 2}), data.HasKey("b"))
+		tt.wantQuery = "(JSON_CONTAINS(tbl.data, ?) AND JSON_CONTAINS_PATH(tbl.data, 'one', ?))"
+		tt.wantArgs = []interface{}{JSONValue([]int{1, 2}), "$.b"}
+		assert(t, tt)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectSQLite
+		data := NewJSONField("data", TableInfo{TableName: "tbl"})
+		tt.item = And(data.GetText("name").EqString("bob"), data.HasKey("b"))
+		tt.wantQuery = "(json_extract(tbl.data, $1) = $2 AND json_type(tbl.data, $3) IS NOT NULL)"
+		tt.wantArgs = []interface{}{"$.name", "bob", "$.b"}
+		assert(t, tt)
+	})
+
+	t.Run("SetJSON", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		tt.item = NewJSONField("data", TableInfo{TableName: "tbl"}).SetJSON([]string{"a"})
+		tt.wantQuery = "tbl.data = $1"
+		tt.wantArgs = []interface{}{JSONValue([]string{"a"})}
+		assert(t, tt)
+		value, err := JSONValue([]string{"a"}).Value()
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(value, `["a"]`); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		data := NewJSONField("data", TableInfo{TableName: "tbl"})
+		tests := []struct {
+			description string
+			dialect     string
+			item        SQLExcludeAppender
+		}{
+			{"sqlite Contains", DialectSQLite, data.Contains(1)},
+			{"sqlserver Get", DialectSQLServer, data.Get("a")},
+			{"empty path", DialectPostgres, data.Get("$")},
+			{"bad index", DialectPostgres, data.Get("a[x]")},
+			{"unclosed index", DialectPostgres, data.Get("a[0")},
+			{"empty key", DialectPostgres, data.Get("a..b")},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.description, func(t *testing.T) {
+				t.Parallel()
+				_, _, _, err := ToSQLExclude(tt.dialect, tt.item, nil)
+				if err == nil {
+					t.Error(testutil.Callers(), "expected error but got nil")
+				}
+			})
+		}
+	})
 }
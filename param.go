@@ -38,6 +38,7 @@ func (param NamedParam) AppendSQLExclude(dialect string, buf *bytes.Buffer, args
 	if param.Name == "" {
 		return fmt.Errorf("Param name cannot be empty")
 	}
+	param.Value = uuidArg(dialect, param.Value)
 	// TODO: what happens if you next Params? Param("a", Param("b", Param("c", 11))). Need to add a test for it.
 	if v, ok := param.Value.(SQLExcludeAppender); ok && v != nil {
 		return v.AppendSQLExclude(dialect, buf, args, params, nil, excludedTableQualifiers)
@@ -68,6 +69,15 @@ func (param NamedParam) AppendSQLExclude(dialect string, buf *bytes.Buffer, args
 			buf.WriteString("@p" + strconv.Itoa(len(*args)+1))
 			*args = append(*args, param.Value)
 		}
+	case DialectOracle:
+		if len(indices) > 0 {
+			(*args)[indices[0]] = param.Value
+			buf.WriteString(":" + strconv.Itoa(indices[0]+1))
+		} else {
+			params[param.Name] = append(indices, len(*args))
+			buf.WriteString(":" + strconv.Itoa(len(*args)+1))
+			*args = append(*args, param.Value)
+		}
 	default:
 		params[param.Name] = append(indices, len(*args))
 		buf.WriteString("?")
@@ -322,3 +322,28 @@ func (r *Row) NullTime(field TimeField) sql.NullTime {
 	r.index++
 	return *nulltime
 }
+
+/* UUID */
+
+// UUID returns the [16]byte value of the UUIDField.
+func (r *Row) UUID(field UUIDField) [16]byte {
+	return r.NullUUID(field).UUID
+}
+
+// UUIDValid returns a bool value indicating if the UUIDField is non-NULL.
+func (r *Row) UUIDValid(field UUIDField) bool {
+	return r.NullUUID(field).Valid
+}
+
+// NullUUID returns the NullUUID value of the UUIDField.
+func (r *Row) NullUUID(field UUIDField) NullUUID {
+	if !r.active {
+		var nulluuid NullUUID
+		r.fields = append(r.fields, field)
+		r.dest = append(r.dest, &nulluuid)
+		return nulluuid
+	}
+	nulluuid := r.dest[r.index].(*NullUUID)
+	r.index++
+	return *nulluuid
+}
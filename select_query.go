@@ -30,8 +30,28 @@ type SelectQuery struct {
 	RowLimit sql.NullInt64
 	// OFFSET
 	RowOffset sql.NullInt64
+	// FOR UPDATE | FOR SHARE
+	LockStrength   LockStrength
+	LockOfTables   []Table
+	LockWaitPolicy LockWaitPolicy
 }
 
+type LockStrength string
+
+const (
+	LockForUpdate      LockStrength = "FOR UPDATE"
+	LockForNoKeyUpdate LockStrength = "FOR NO KEY UPDATE"
+	LockForShare       LockStrength = "FOR SHARE"
+	LockForKeyShare    LockStrength = "FOR KEY SHARE"
+)
+
+type LockWaitPolicy string
+
+const (
+	LockNoWait     LockWaitPolicy = "NOWAIT"
+	LockSkipLocked LockWaitPolicy = "SKIP LOCKED"
+)
+
 var _ Query = SelectQuery{}
 
 func (q SelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
@@ -64,6 +84,13 @@ func (q SelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 	} else if q.Distinct {
 		buf.WriteString("DISTINCT ")
 	}
+	// TOP (sqlserver)
+	if dialect == DialectSQLServer && q.RowLimit.Valid && !q.RowOffset.Valid {
+		err = BufferPrintf(dialect, buf, args, params, env, nil, "TOP ({}) ", []interface{}{q.RowLimit.Int64})
+		if err != nil {
+			return fmt.Errorf("TOP: %w", err)
+		}
+	}
 	if len(q.SelectFields) == 0 {
 		return fmt.Errorf("no fields SELECT-ed")
 	}
@@ -79,7 +106,7 @@ func (q SelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("FROM: %w", err)
 		}
 		if tableAlias := q.FromTable.GetAlias(); tableAlias != "" {
-			buf.WriteString(" AS " + QuoteIdentifier(dialect, tableAlias))
+			writeTableAlias(dialect, buf, tableAlias)
 		}
 	}
 	// JOIN
@@ -130,7 +157,7 @@ func (q SelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 	// GROUP BY
 	if len(q.GroupByFields) > 0 {
 		buf.WriteString(" GROUP BY ")
-		err = q.GroupByFields.AppendSQLExclude(dialect, buf, args, params, env, nil)
+		err = appendGroupByFields(dialect, buf, args, params, env, q.GroupByFields)
 		if err != nil {
 			return fmt.Errorf("GROUP BY: %w", err)
 		}
@@ -152,6 +179,52 @@ func (q SelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("ORDER BY: %w", err)
 		}
 	}
+	// OFFSET ... FETCH NEXT (sqlserver)
+	if dialect == DialectSQLServer {
+		if q.LockStrength != "" {
+			return fmt.Errorf("%s does not support SELECT %s", dialect, q.LockStrength)
+		}
+		if !q.RowOffset.Valid {
+			return nil
+		}
+		if len(q.OrderByFields) == 0 {
+			return fmt.Errorf("sqlserver OFFSET requires an ORDER BY clause")
+		}
+		err = BufferPrintf(dialect, buf, args, params, env, nil, " OFFSET {} ROWS", []interface{}{q.RowOffset.Int64})
+		if err != nil {
+			return fmt.Errorf("OFFSET: %w", err)
+		}
+		if q.RowLimit.Valid {
+			err = BufferPrintf(dialect, buf, args, params, env, nil, " FETCH NEXT {} ROWS ONLY", []interface{}{q.RowLimit.Int64})
+			if err != nil {
+				return fmt.Errorf("FETCH NEXT: %w", err)
+			}
+		}
+		return nil
+	}
+	// OFFSET ... FETCH (oracle)
+	if dialect == DialectOracle {
+		if q.LockStrength != "" {
+			return fmt.Errorf("%s does not support SELECT %s", dialect, q.LockStrength)
+		}
+		if q.RowOffset.Valid {
+			err = BufferPrintf(dialect, buf, args, params, env, nil, " OFFSET {} ROWS", []interface{}{q.RowOffset.Int64})
+			if err != nil {
+				return fmt.Errorf("OFFSET: %w", err)
+			}
+		}
+		if q.RowLimit.Valid {
+			format := " FETCH FIRST {} ROWS ONLY"
+			if q.RowOffset.Valid {
+				format = " FETCH NEXT {} ROWS ONLY"
+			}
+			err = BufferPrintf(dialect, buf, args, params, env, nil, format, []interface{}{q.RowLimit.Int64})
+			if err != nil {
+				return fmt.Errorf("FETCH: %w", err)
+			}
+		}
+		return nil
+	}
 	// LIMIT
 	if q.RowLimit.Valid {
 		err = BufferPrintf(dialect, buf, args, params, env, nil, " LIMIT {}", []interface{}{q.RowLimit.Int64})
@@ -166,6 +239,57 @@ func (q SelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("OFFSET: %w", err)
 		}
 	}
+	// FOR UPDATE | FOR SHARE
+	if q.LockStrength != "" {
+		err = q.appendLockingClause(dialect, buf)
+		if err != nil {
+			return fmt.Errorf("%s: %w", q.LockStrength, err)
+		}
+	} else if len(q.LockOfTables) > 0 || q.LockWaitPolicy != "" {
+		return fmt.Errorf("OF, NOWAIT and SKIP LOCKED require a FOR UPDATE or FOR SHARE clause")
+	}
+	return nil
+}
+
+func (q SelectQuery) appendLockingClause(dialect string, buf *bytes.Buffer) error {
+	switch dialect {
+	case DialectPostgres:
+	case DialectMySQL:
+		if q.LockStrength != LockForUpdate && q.LockStrength != LockForShare {
+			return fmt.Errorf("mysql does not support %s", q.LockStrength)
+		}
+	default:
+		return fmt.Errorf("%s does not support row locking clauses", dialect)
+	}
+	switch q.LockStrength {
+	case LockForUpdate, LockForNoKeyUpdate, LockForShare, LockForKeyShare:
+	default:
+		return fmt.Errorf("unknown lock strength %q", q.LockStrength)
+	}
+	buf.WriteString(" " + string(q.LockStrength))
+	if len(q.LockOfTables) > 0 {
+		buf.WriteString(" OF ")
+		for i, table := range q.LockOfTables {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if table == nil {
+				return fmt.Errorf("OF table #%d is nil", i+1)
+			}
+			if alias := table.GetAlias(); alias != "" {
+				buf.WriteString(QuoteIdentifier(dialect, alias))
+			} else {
+				buf.WriteString(QuoteIdentifier(dialect, table.GetName()))
+			}
+		}
+	}
+	switch q.LockWaitPolicy {
+	case "":
+	case LockNoWait, LockSkipLocked:
+		buf.WriteString(" " + string(q.LockWaitPolicy))
+	default:
+		return fmt.Errorf("unknown lock wait policy %q", q.LockWaitPolicy)
+	}
 	return nil
 }
 
@@ -143,3 +143,28 @@ func (q MySQLSelectQuery) Offset(offset int64) MySQLSelectQuery {
 	q.RowOffset.Int64 = offset
 	return q
 }
+
+func (q MySQLSelectQuery) ForUpdate() MySQLSelectQuery {
+	q.LockStrength = LockForUpdate
+	return q
+}
+
+func (q MySQLSelectQuery) ForShare() MySQLSelectQuery {
+	q.LockStrength = LockForShare
+	return q
+}
+
+func (q MySQLSelectQuery) Of(tables ...Table) MySQLSelectQuery {
+	q.LockOfTables = append(q.LockOfTables, tables...)
+	return q
+}
+
+func (q MySQLSelectQuery) NoWait() MySQLSelectQuery {
+	q.LockWaitPolicy = LockNoWait
+	return q
+}
+
+func (q MySQLSelectQuery) SkipLocked() MySQLSelectQuery {
+	q.LockWaitPolicy = LockSkipLocked
+	return q
+}
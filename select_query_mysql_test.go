@@ -102,6 +102,37 @@ func Test_MySQLSelectQuery(t *testing.T) {
 		tt.wantArgs = []interface{}{int64(10), int64(20)}
 		assert(t, tt)
 	})
+
+	t.Run("FOR UPDATE", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = MySQL.
+			Select(ACTOR.ACTOR_ID).
+			From(ACTOR).
+			Where(ACTOR.FIRST_NAME.EqString("bob")).
+			ForUpdate().
+			Of(ACTOR).
+			NoWait()
+		tt.wantQuery = "SELECT a.actor_id FROM actor AS a WHERE a.first_name = ? FOR UPDATE OF a NOWAIT"
+		tt.wantArgs = []interface{}{"bob"}
+		assert(t, tt)
+	})
+
+	t.Run("FOR SHARE SKIP LOCKED", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		tt.item = MySQL.
+			Select(ACTOR.ACTOR_ID).
+			From(ACTOR).
+			Limit(1).
+			ForShare().
+			SkipLocked()
+		tt.wantQuery = "SELECT actor.actor_id FROM actor LIMIT ? FOR SHARE SKIP LOCKED"
+		tt.wantArgs = []interface{}{int64(1)}
+		assert(t, tt)
+	})
 }
 
 func Test_MySQLTestSuite(t *testing.T) {
@@ -0,0 +1,147 @@
+package sq
+
+import "bytes"
+
+type OracleSelectQuery SelectQuery
+
+var _ Query = OracleSelectQuery{}
+
+func (q OracleSelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return SelectQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q OracleSelectQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return SelectQuery(q).SetFetchableFields(fields)
+}
+
+func (q OracleSelectQuery) GetFetchableFields() ([]Field, error) {
+	return SelectQuery(q).GetFetchableFields()
+}
+
+func (q OracleSelectQuery) GetDialect() string { return q.Dialect }
+
+func (d OracleQueryBuilder) From(table Table) OracleSelectQuery {
+	return OracleSelectQuery{
+		Env:       d.env,
+		Dialect:   DialectOracle,
+		FromTable: table,
+	}
+}
+
+func (d OracleQueryBuilder) SelectWith(ctes ...CTE) OracleSelectQuery {
+	return OracleSelectQuery{
+		Env:     d.env,
+		Dialect: DialectOracle,
+		CTEs:    ctes,
+	}
+}
+
+func (d OracleQueryBuilder) Select(fields ...Field) OracleSelectQuery {
+	return OracleSelectQuery{
+		Env:          d.env,
+		Dialect:      DialectOracle,
+		SelectFields: fields,
+	}
+}
+
+func (d OracleQueryBuilder) SelectOne() OracleSelectQuery {
+	return OracleSelectQuery{
+		Env:          d.env,
+		Dialect:      DialectOracle,
+		SelectFields: AliasFields{Literal("1")},
+	}
+}
+
+func (d OracleQueryBuilder) SelectDistinct(fields ...Field) OracleSelectQuery {
+	return OracleSelectQuery{
+		Env:          d.env,
+		Dialect:      DialectOracle,
+		Distinct:     true,
+		SelectFields: fields,
+	}
+}
+
+func (q OracleSelectQuery) With(ctes ...CTE) OracleSelectQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q OracleSelectQuery) Select(fields ...Field) OracleSelectQuery {
+	q.SelectFields = append(q.SelectFields, fields...)
+	return q
+}
+
+func (q OracleSelectQuery) SelectDistinct(fields ...Field) OracleSelectQuery {
+	q.Distinct = true
+	q.SelectFields = fields
+	return q
+}
+
+func (q OracleSelectQuery) From(table Table) OracleSelectQuery {
+	q.FromTable = table
+	return q
+}
+
+func (q OracleSelectQuery) Join(table Table, predicates ...Predicate) OracleSelectQuery {
+	q.JoinTables = append(q.JoinTables, Join(table, predicates...))
+	return q
+}
+
+func (q OracleSelectQuery) LeftJoin(table Table, predicates ...Predicate) OracleSelectQuery {
+	q.JoinTables = append(q.JoinTables, LeftJoin(table, predicates...))
+	return q
+}
+
+func (q OracleSelectQuery) RightJoin(table Table, predicates ...Predicate) OracleSelectQuery {
+	q.JoinTables = append(q.JoinTables, RightJoin(table, predicates...))
+	return q
+}
+
+func (q OracleSelectQuery) FullJoin(table Table, predicates ...Predicate) OracleSelectQuery {
+	q.JoinTables = append(q.JoinTables, FullJoin(table, predicates...))
+	return q
+}
+
+func (q OracleSelectQuery) CrossJoin(table Table) OracleSelectQuery {
+	q.JoinTables = append(q.JoinTables, CrossJoin(table))
+	return q
+}
+
+func (q OracleSelectQuery) CustomJoin(joinType JoinType, table Table, predicates ...Predicate) OracleSelectQuery {
+	q.JoinTables = append(q.JoinTables, CustomJoin(joinType, table, predicates...))
+	return q
+}
+
+func (q OracleSelectQuery) Where(predicates ...Predicate) OracleSelectQuery {
+	q.WherePredicate.Predicates = append(q.WherePredicate.Predicates, predicates...)
+	return q
+}
+
+func (q OracleSelectQuery) GroupBy(fields ...Field) OracleSelectQuery {
+	q.GroupByFields = append(q.GroupByFields, fields...)
+	return q
+}
+
+func (q OracleSelectQuery) Having(predicates ...Predicate) OracleSelectQuery {
+	q.HavingPredicate.Predicates = append(q.HavingPredicate.Predicates, predicates...)
+	return q
+}
+
+func (q OracleSelectQuery) OrderBy(fields ...Field) OracleSelectQuery {
+	q.OrderByFields = append(q.OrderByFields, fields...)
+	return q
+}
+
+func (q OracleSelectQuery) Offset(offset int64) OracleSelectQuery {
+	q.RowOffset.Valid = true
+	q.RowOffset.Int64 = offset
+	return q
+}
+
+// FetchNext limits the number of rows returned. It is rendered as FETCH
+// FIRST n ROWS ONLY, or FETCH NEXT n ROWS ONLY if an Offset is present.
+func (q OracleSelectQuery) FetchNext(limit int64) OracleSelectQuery {
+	q.RowLimit.Valid = true
+	q.RowLimit.Int64 = limit
+	return q
+}
@@ -0,0 +1,96 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_OracleSelectQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("joins", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = Oracle.
+			SelectOne().
+			From(ACTOR).
+			With(NewCTE("cte", []string{"n"}, Queryf("SELECT 1 FROM DUAL"))).
+			Join(ACTOR, Eq(1, 1)).
+			LeftJoin(ACTOR, Eq(1, 1)).
+			RightJoin(ACTOR, Eq(1, 1)).
+			FullJoin(ACTOR, Eq(1, 1)).
+			CrossJoin(ACTOR).
+			CustomJoin("CROSS APPLY", ACTOR)
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1 FROM DUAL)" +
+			" SELECT 1" +
+			" FROM actor a" +
+			" JOIN actor a ON :1 = :2" +
+			" LEFT JOIN actor a ON :3 = :4" +
+			" RIGHT JOIN actor a ON :5 = :6" +
+			" FULL JOIN actor a ON :7 = :8" +
+			" CROSS JOIN actor a" +
+			" CROSS APPLY actor a"
+		tt.wantArgs = []interface{}{1, 1, 1, 1, 1, 1, 1, 1}
+		assert(t, tt)
+	})
+
+	t.Run("FETCH FIRST", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = Oracle.
+			SelectDistinct(ACTOR.FIRST_NAME, ACTOR.LAST_NAME).
+			From(ACTOR).
+			Where(ACTOR.ACTOR_ID.GtInt(10)).
+			OrderBy(ACTOR.LAST_NAME).
+			FetchNext(5)
+		tt.wantQuery = "SELECT DISTINCT a.first_name, a.last_name" +
+			" FROM actor a" +
+			" WHERE a.actor_id > :1" +
+			" ORDER BY a.last_name" +
+			" FETCH FIRST :2 ROWS ONLY"
+		tt.wantArgs = []interface{}{10, int64(5)}
+		assert(t, tt)
+	})
+
+	t.Run("OFFSET FETCH NEXT", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = Oracle.
+			From(ACTOR).
+			Select(ACTOR.ACTOR_ID, ACTOR.FIRST_NAME, ACTOR.LAST_NAME).
+			Where(Eq(ACTOR.FIRST_NAME, Param("name", "bob")), Eq(ACTOR.LAST_NAME, Param("name", "bob"))).
+			OrderBy(ACTOR.LAST_NAME).
+			Offset(10).
+			FetchNext(20)
+		tt.wantQuery = "SELECT a.actor_id, a.first_name, a.last_name" +
+			" FROM actor a" +
+			" WHERE a.first_name = :1 AND a.last_name = :1" +
+			" ORDER BY a.last_name" +
+			" OFFSET :2 ROWS" +
+			" FETCH NEXT :3 ROWS ONLY"
+		tt.wantArgs = []interface{}{"bob", int64(10), int64(20)}
+		assert(t, tt)
+	})
+}
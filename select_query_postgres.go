@@ -148,3 +148,38 @@ func (q PostgresSelectQuery) Offset(offset int64) PostgresSelectQuery {
 	q.RowOffset.Int64 = offset
 	return q
 }
+
+func (q PostgresSelectQuery) ForUpdate() PostgresSelectQuery {
+	q.LockStrength = LockForUpdate
+	return q
+}
+
+func (q PostgresSelectQuery) ForNoKeyUpdate() PostgresSelectQuery {
+	q.LockStrength = LockForNoKeyUpdate
+	return q
+}
+
+func (q PostgresSelectQuery) ForShare() PostgresSelectQuery {
+	q.LockStrength = LockForShare
+	return q
+}
+
+func (q PostgresSelectQuery) ForKeyShare() PostgresSelectQuery {
+	q.LockStrength = LockForKeyShare
+	return q
+}
+
+func (q PostgresSelectQuery) Of(tables ...Table) PostgresSelectQuery {
+	q.LockOfTables = append(q.LockOfTables, tables...)
+	return q
+}
+
+func (q PostgresSelectQuery) NoWait() PostgresSelectQuery {
+	q.LockWaitPolicy = LockNoWait
+	return q
+}
+
+func (q PostgresSelectQuery) SkipLocked() PostgresSelectQuery {
+	q.LockWaitPolicy = LockSkipLocked
+	return q
+}
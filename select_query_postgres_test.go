@@ -91,6 +91,40 @@ func Test_PostgresSelectQuery(t *testing.T) {
 		tt.wantArgs = []interface{}{int64(10), int64(20)}
 		assert(t, tt)
 	})
+
+	t.Run("FOR UPDATE", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR, FILM_ACTOR := xNEW_ACTOR("a"), xNEW_FILM_ACTOR("")
+		tt.item = Postgres.
+			Select(ACTOR.ACTOR_ID).
+			From(ACTOR).
+			Join(FILM_ACTOR, FILM_ACTOR.ACTOR_ID.Eq(ACTOR.ACTOR_ID)).
+			Limit(10).
+			ForNoKeyUpdate().
+			Of(ACTOR, FILM_ACTOR).
+			SkipLocked()
+		tt.wantQuery = "SELECT a.actor_id" +
+			" FROM actor AS a" +
+			" JOIN film_actor ON film_actor.actor_id = a.actor_id" +
+			" LIMIT $1" +
+			" FOR NO KEY UPDATE OF a, film_actor SKIP LOCKED"
+		tt.wantArgs = []interface{}{int64(10)}
+		assert(t, tt)
+	})
+
+	t.Run("FOR KEY SHARE", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		tt.item = Postgres.
+			Select(ACTOR.ACTOR_ID).
+			From(ACTOR).
+			ForKeyShare().
+			NoWait()
+		tt.wantQuery = "SELECT actor.actor_id FROM actor FOR KEY SHARE NOWAIT"
+		assert(t, tt)
+	})
 }
 
 func Test_PostgresTestSuite(t *testing.T) {
@@ -0,0 +1,153 @@
+package sq
+
+import "bytes"
+
+type SQLServerSelectQuery SelectQuery
+
+var _ Query = SQLServerSelectQuery{}
+
+func (q SQLServerSelectQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return SelectQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q SQLServerSelectQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return SelectQuery(q).SetFetchableFields(fields)
+}
+
+func (q SQLServerSelectQuery) GetFetchableFields() ([]Field, error) {
+	return SelectQuery(q).GetFetchableFields()
+}
+
+func (q SQLServerSelectQuery) GetDialect() string { return q.Dialect }
+
+func (d SQLServerQueryBuilder) From(table Table) SQLServerSelectQuery {
+	return SQLServerSelectQuery{
+		Env:       d.env,
+		Dialect:   DialectSQLServer,
+		FromTable: table,
+	}
+}
+
+func (d SQLServerQueryBuilder) SelectWith(ctes ...CTE) SQLServerSelectQuery {
+	return SQLServerSelectQuery{
+		Env:     d.env,
+		Dialect: DialectSQLServer,
+		CTEs:    ctes,
+	}
+}
+
+func (d SQLServerQueryBuilder) Select(fields ...Field) SQLServerSelectQuery {
+	return SQLServerSelectQuery{
+		Env:          d.env,
+		Dialect:      DialectSQLServer,
+		SelectFields: fields,
+	}
+}
+
+func (d SQLServerQueryBuilder) SelectOne() SQLServerSelectQuery {
+	return SQLServerSelectQuery{
+		Env:          d.env,
+		Dialect:      DialectSQLServer,
+		SelectFields: AliasFields{Literal("1")},
+	}
+}
+
+func (d SQLServerQueryBuilder) SelectDistinct(fields ...Field) SQLServerSelectQuery {
+	return SQLServerSelectQuery{
+		Env:          d.env,
+		Dialect:      DialectSQLServer,
+		Distinct:     true,
+		SelectFields: fields,
+	}
+}
+
+func (q SQLServerSelectQuery) With(ctes ...CTE) SQLServerSelectQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q SQLServerSelectQuery) Select(fields ...Field) SQLServerSelectQuery {
+	q.SelectFields = append(q.SelectFields, fields...)
+	return q
+}
+
+func (q SQLServerSelectQuery) SelectDistinct(fields ...Field) SQLServerSelectQuery {
+	q.Distinct = true
+	q.SelectFields = fields
+	return q
+}
+
+func (q SQLServerSelectQuery) From(table Table) SQLServerSelectQuery {
+	q.FromTable = table
+	return q
+}
+
+func (q SQLServerSelectQuery) Join(table Table, predicates ...Predicate) SQLServerSelectQuery {
+	q.JoinTables = append(q.JoinTables, Join(table, predicates...))
+	return q
+}
+
+func (q SQLServerSelectQuery) LeftJoin(table Table, predicates ...Predicate) SQLServerSelectQuery {
+	q.JoinTables = append(q.JoinTables, LeftJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerSelectQuery) RightJoin(table Table, predicates ...Predicate) SQLServerSelectQuery {
+	q.JoinTables = append(q.JoinTables, RightJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerSelectQuery) FullJoin(table Table, predicates ...Predicate) SQLServerSelectQuery {
+	q.JoinTables = append(q.JoinTables, FullJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerSelectQuery) CrossJoin(table Table) SQLServerSelectQuery {
+	q.JoinTables = append(q.JoinTables, CrossJoin(table))
+	return q
+}
+
+func (q SQLServerSelectQuery) CustomJoin(joinType JoinType, table Table, predicates ...Predicate) SQLServerSelectQuery {
+	q.JoinTables = append(q.JoinTables, CustomJoin(joinType, table, predicates...))
+	return q
+}
+
+func (q SQLServerSelectQuery) Where(predicates ...Predicate) SQLServerSelectQuery {
+	q.WherePredicate.Predicates = append(q.WherePredicate.Predicates, predicates...)
+	return q
+}
+
+func (q SQLServerSelectQuery) GroupBy(fields ...Field) SQLServerSelectQuery {
+	q.GroupByFields = append(q.GroupByFields, fields...)
+	return q
+}
+
+func (q SQLServerSelectQuery) Having(predicates ...Predicate) SQLServerSelectQuery {
+	q.HavingPredicate.Predicates = append(q.HavingPredicate.Predicates, predicates...)
+	return q
+}
+
+func (q SQLServerSelectQuery) OrderBy(fields ...Field) SQLServerSelectQuery {
+	q.OrderByFields = append(q.OrderByFields, fields...)
+	return q
+}
+
+// Top limits the number of rows returned. If an Offset is also provided, the
+// limit is rendered as OFFSET ... FETCH NEXT instead of SELECT TOP.
+func (q SQLServerSelectQuery) Top(limit int64) SQLServerSelectQuery {
+	q.RowLimit.Valid = true
+	q.RowLimit.Int64 = limit
+	return q
+}
+
+func (q SQLServerSelectQuery) Offset(offset int64) SQLServerSelectQuery {
+	q.RowOffset.Valid = true
+	q.RowOffset.Int64 = offset
+	return q
+}
+
+func (q SQLServerSelectQuery) FetchNext(limit int64) SQLServerSelectQuery {
+	q.RowLimit.Valid = true
+	q.RowLimit.Int64 = limit
+	return q
+}
@@ -0,0 +1,132 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_SQLServerSelectQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("filler", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = SQLServer.
+			SelectDistinct(ACTOR.ACTOR_ID).
+			SelectDistinct(ACTOR.ACTOR_ID).
+			From(ACTOR)
+		tt.wantQuery = "SELECT DISTINCT a.actor_id FROM actor AS a"
+		assert(t, tt)
+	})
+
+	t.Run("joins", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = SQLServer.
+			SelectOne().
+			From(ACTOR).
+			With(NewCTE("cte", []string{"n"}, Queryf("SELECT 1"))).
+			Join(ACTOR, Eq(1, 1)).
+			LeftJoin(ACTOR, Eq(1, 1)).
+			RightJoin(ACTOR, Eq(1, 1)).
+			FullJoin(ACTOR, Eq(1, 1)).
+			CrossJoin(ACTOR).
+			CustomJoin("CROSS APPLY", ACTOR)
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1)" +
+			" SELECT 1" +
+			" FROM actor AS a" +
+			" JOIN actor AS a ON @p1 = @p2" +
+			" LEFT JOIN actor AS a ON @p3 = @p4" +
+			" RIGHT JOIN actor AS a ON @p5 = @p6" +
+			" FULL JOIN actor AS a ON @p7 = @p8" +
+			" CROSS JOIN actor AS a" +
+			" CROSS APPLY actor AS a"
+		tt.wantArgs = []interface{}{1, 1, 1, 1, 1, 1, 1, 1}
+		assert(t, tt)
+	})
+
+	t.Run("TOP", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = SQLServer.
+			SelectDistinct(ACTOR.FIRST_NAME, ACTOR.LAST_NAME).
+			From(ACTOR).
+			Where(ACTOR.ACTOR_ID.GtInt(10)).
+			OrderBy(ACTOR.LAST_NAME).
+			Top(5)
+		tt.wantQuery = "SELECT DISTINCT TOP (@p1) a.first_name, a.last_name" +
+			" FROM actor AS a" +
+			" WHERE a.actor_id > @p2" +
+			" ORDER BY a.last_name"
+		tt.wantArgs = []interface{}{int64(5), 10}
+		assert(t, tt)
+	})
+
+	t.Run("OFFSET FETCH NEXT", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = SQLServer.
+			From(ACTOR).
+			Select(ACTOR.ACTOR_ID, ACTOR.FIRST_NAME, ACTOR.LAST_NAME).
+			GroupBy(ACTOR.FIRST_NAME).
+			Having(ACTOR.FIRST_NAME.IsNotNull()).
+			OrderBy(ACTOR.LAST_NAME).
+			Offset(10).
+			FetchNext(10)
+		tt.wantQuery = "SELECT a.actor_id, a.first_name, a.last_name" +
+			" FROM actor AS a" +
+			" GROUP BY a.first_name" +
+			" HAVING a.first_name IS NOT NULL" +
+			" ORDER BY a.last_name" +
+			" OFFSET @p1 ROWS" +
+			" FETCH NEXT @p2 ROWS ONLY"
+		tt.wantArgs = []interface{}{int64(10), int64(10)}
+		assert(t, tt)
+	})
+
+	t.Run("OFFSET without ORDER BY", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("a")
+		_, _, _, err := ToSQL("", SQLServer.Select(ACTOR.ACTOR_ID).From(ACTOR).Offset(10))
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+
+	t.Run("DISTINCT ON", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("a")
+		_, _, _, err := ToSQL("", SelectQuery{
+			Dialect:          DialectSQLServer,
+			SelectFields:     AliasFields{ACTOR.ACTOR_ID},
+			DistinctOnFields: Fields{ACTOR.FIRST_NAME},
+			FromTable:        ACTOR,
+		})
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+}
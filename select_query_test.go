@@ -175,4 +175,34 @@ func Test_SelectQuery(t *testing.T) {
 			t.Error(testutil.Callers(), diff)
 		}
 	})
+
+	t.Run("locking clause errors", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("")
+		tests := []struct {
+			description string
+			dialect     string
+			q           SelectQuery
+		}{
+			{"sqlite FOR UPDATE", DialectSQLite, SelectQuery{LockStrength: LockForUpdate}},
+			{"sqlserver FOR UPDATE", DialectSQLServer, SelectQuery{LockStrength: LockForUpdate}},
+			{"mysql FOR NO KEY UPDATE", DialectMySQL, SelectQuery{LockStrength: LockForNoKeyUpdate}},
+			{"mysql FOR KEY SHARE", DialectMySQL, SelectQuery{LockStrength: LockForKeyShare}},
+			{"SKIP LOCKED without FOR UPDATE", DialectPostgres, SelectQuery{LockWaitPolicy: LockSkipLocked}},
+			{"OF without FOR UPDATE", DialectPostgres, SelectQuery{LockOfTables: []Table{ACTOR}}},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.description, func(t *testing.T) {
+				t.Parallel()
+				tt.q.Dialect = tt.dialect
+				tt.q.SelectFields = AliasFields{ACTOR.ACTOR_ID}
+				tt.q.FromTable = ACTOR
+				_, _, _, err := ToSQL("", tt.q)
+				if err == nil {
+					t.Error(testutil.Callers(), "expected error but got nil")
+				}
+			})
+		}
+	})
 }
@@ -183,10 +183,12 @@ func explodeSlice(dialect string, buf *bytes.Buffer, args *[]interface{}, params
 			buf.WriteString("$" + strconv.Itoa(len(*args)+1))
 		case DialectSQLServer:
 			buf.WriteString("@p" + strconv.Itoa(len(*args)+1))
+		case DialectOracle:
+			buf.WriteString(":" + strconv.Itoa(len(*args)+1))
 		default:
 			buf.WriteString("?")
 		}
-		*args = append(*args, v)
+		*args = append(*args, uuidArg(dialect, v))
 	}
 	return nil
 }
@@ -250,6 +252,17 @@ func QuoteIdentifier(dialect string, identifier string) string {
 	}
 }
 
+// writeTableAlias writes the alias of a table (in the FROM, JOIN, UPDATE or
+// DELETE clauses). Oracle does not accept the AS keyword before a table
+// alias.
+func writeTableAlias(dialect string, buf *bytes.Buffer, alias string) {
+	if dialect == DialectOracle {
+		buf.WriteString(" " + QuoteIdentifier(dialect, alias))
+		return
+	}
+	buf.WriteString(" AS " + QuoteIdentifier(dialect, alias))
+}
+
 func caller(skip int) (file string, line int, function string) {
 	/* https://talks.godoc.org/github.com/davecheney/go-1.9-release-party/presentation.slide#20
 	 * "Code that queries a single caller at a specific depth should use Caller
@@ -0,0 +1,131 @@
+package sq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+var savepointCount uint64
+
+// WithTx runs fn inside a transaction. The transaction is committed if fn
+// returns nil, and rolled back if fn returns an error, panics or exits early
+// through runtime.Goexit (as t.FailNow does).
+//
+// If db is already a transaction (i.e. WithTx is nested inside another
+// WithTx), a savepoint is created instead and fn's changes are rolled back to
+// that savepoint on failure. The savepoint is released either way, and an
+// error from rolling back to or releasing it is returned. The SAVEPOINT,
+// RELEASE SAVEPOINT and ROLLBACK TO SAVEPOINT statements used are understood
+// by SQLite, Postgres and MySQL. opts is ignored for savepoints.
+//
+// If db is a LoggerDB, the DB passed to fn will log its queries using the same
+// Logger. Only LoggerDBs returned by Log and VerboseLog can be unwrapped, any
+// other LoggerDB must be a *sql.Tx or implement BeginTx itself.
+func WithTx(ctx context.Context, db DB, opts *sql.TxOptions, fn func(tx DB) error) (err error) {
+	if db == nil {
+		return errors.New("sq: db is nil")
+	}
+	if fn == nil {
+		return errors.New("sq: cannot call WithTx without a function")
+	}
+	var logger Logger
+	innerDB := db
+	switch db := db.(type) {
+	case loggerDB:
+		logger, innerDB = db.Logger, db.DB
+	case Logger:
+		logger = db
+		if _, ok := db.(txBeginner); !ok {
+			return fmt.Errorf("sq: %T is neither a transaction nor able to begin one (wrap the DB with sq.Log or implement BeginTx)", db)
+		}
+	}
+	switch innerDB := innerDB.(type) {
+	case *sql.Tx:
+		return withSavepoint(ctx, db, fn)
+	case txBeginner:
+		var sqlTx *sql.Tx
+		sqlTx, err = innerDB.BeginTx(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("sq: begin transaction: %w", err)
+		}
+		var tx DB = sqlTx
+		if logger != nil {
+			tx = loggerDB{Logger: logger, DB: sqlTx}
+		}
+		var completed bool
+		defer func() {
+			if r := recover(); r != nil {
+				sqlTx.Rollback()
+				panic(r)
+			}
+			if !completed || err != nil {
+				sqlTx.Rollback()
+				return
+			}
+			err = sqlTx.Commit()
+			if err != nil {
+				err = fmt.Errorf("sq: commit transaction: %w", err)
+			}
+		}()
+		err = fn(tx)
+		completed = true
+		return err
+	default:
+		return fmt.Errorf("sq: %T is neither a transaction nor able to begin one", innerDB)
+	}
+}
+
+func withSavepoint(ctx context.Context, tx DB, fn func(tx DB) error) (err error) {
+	savepoint := "sq_savepoint_" + strconv.FormatUint(atomic.AddUint64(&savepointCount, 1), 10)
+	_, err = tx.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if err != nil {
+		return fmt.Errorf("sq: create savepoint: %w", err)
+	}
+	// ROLLBACK TO SAVEPOINT leaves the savepoint open, so it is released
+	// afterwards.
+	rollbackToSavepoint := func() error {
+		_, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		if err != nil {
+			return fmt.Errorf("sq: rollback to savepoint: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+		if err != nil {
+			return fmt.Errorf("sq: release savepoint: %w", err)
+		}
+		return nil
+	}
+	var completed bool
+	defer func() {
+		if r := recover(); r != nil {
+			if rollbackErr := rollbackToSavepoint(); rollbackErr != nil {
+				panic(fmt.Errorf("%v (%w)", r, rollbackErr))
+			}
+			panic(r)
+		}
+		if !completed {
+			err = rollbackToSavepoint()
+			return
+		}
+		if err != nil {
+			if rollbackErr := rollbackToSavepoint(); rollbackErr != nil {
+				err = fmt.Errorf("%w (%s)", err, rollbackErr.Error())
+			}
+			return
+		}
+		_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+		if err != nil {
+			err = fmt.Errorf("sq: release savepoint: %w", err)
+		}
+	}()
+	err = fn(tx)
+	completed = true
+	return err
+}
@@ -0,0 +1,182 @@
+package sq
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func TestSQLiteSakilaTx(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+	ctx := context.Background()
+	ACTOR := xNEW_ACTOR("")
+	actorExists := func(db DB, lastName string) bool {
+		exists, err := FetchExists(db, SQLite.From(ACTOR).Where(ACTOR.LAST_NAME.EqString(lastName)))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		return exists
+	}
+	insertActor := func(db DB, lastName string) {
+		_, _, err := Exec(db, SQLite.InsertInto(ACTOR).Columns(ACTOR.FIRST_NAME, ACTOR.LAST_NAME).Values("WITHTX", lastName))
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+	}
+	errRollback := errors.New("rollback")
+	err := WithTx(ctx, Log(sqliteDB), nil, func(tx DB) error {
+		insertActor(tx, "OUTER")
+		err := WithTx(ctx, tx, nil, func(tx DB) error {
+			insertActor(tx, "INNER")
+			if !actorExists(tx, "INNER") {
+				t.Fatal(testutil.Callers(), "inner actor not visible inside savepoint")
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf(testutil.Callers()+"expected errRollback, got %v", err)
+		}
+		if actorExists(tx, "INNER") {
+			t.Fatal(testutil.Callers(), "inner actor was not rolled back to the savepoint")
+		}
+		if !actorExists(tx, "OUTER") {
+			t.Fatal(testutil.Callers(), "outer actor was rolled back together with the savepoint")
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf(testutil.Callers()+"expected errRollback, got %v", err)
+	}
+	if actorExists(sqliteDB, "OUTER") {
+		t.Fatal(testutil.Callers(), "outer actor was not rolled back")
+	}
+}
+
+type customLoggerDB struct {
+	Logger
+	DB
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "tx.sqlite3"))
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE item (name TEXT)")
+	if err != nil {
+		t.Fatal(testutil.Callers(), err)
+	}
+	insertItem := func(db DB, name string) error {
+		_, err := db.ExecContext(ctx, "INSERT INTO item (name) VALUES (?)", name)
+		return err
+	}
+	itemExists := func(db DB, name string) bool {
+		rows, err := db.QueryContext(ctx, "SELECT 1 FROM item WHERE name = ?", name)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		defer rows.Close()
+		return rows.Next()
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		err := WithTx(ctx, db, nil, func(tx DB) error {
+			return insertItem(tx, "commit")
+		})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if !itemExists(db, "commit") {
+			t.Error(testutil.Callers(), "item was not committed")
+		}
+	})
+
+	t.Run("goexit rolls back", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			WithTx(ctx, db, nil, func(tx DB) error {
+				if err := insertItem(tx, "goexit"); err != nil {
+					return err
+				}
+				runtime.Goexit()
+				return nil
+			})
+		}()
+		<-done
+		if itemExists(db, "goexit") {
+			t.Error(testutil.Callers(), "transaction was committed after runtime.Goexit")
+		}
+	})
+
+	t.Run("savepoint goexit rolls back", func(t *testing.T) {
+		err := WithTx(ctx, db, nil, func(tx DB) error {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				WithTx(ctx, tx, nil, func(tx DB) error {
+					if err := insertItem(tx, "savepoint goexit"); err != nil {
+						return err
+					}
+					runtime.Goexit()
+					return nil
+				})
+			}()
+			<-done
+			if itemExists(tx, "savepoint goexit") {
+				t.Error(testutil.Callers(), "savepoint was released after runtime.Goexit")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+	})
+
+	t.Run("failed savepoint is released", func(t *testing.T) {
+		errRollback := errors.New("rollback")
+		err := WithTx(ctx, db, nil, func(tx DB) error {
+			err := WithTx(ctx, tx, nil, func(tx DB) error {
+				if err := insertItem(tx, "savepoint error"); err != nil {
+					return err
+				}
+				return errRollback
+			})
+			if !errors.Is(err, errRollback) {
+				t.Fatalf(testutil.Callers()+" expected errRollback, got %v", err)
+			}
+			if itemExists(tx, "savepoint error") {
+				t.Error(testutil.Callers(), "savepoint was not rolled back")
+			}
+			savepoint := "sq_savepoint_" + strconv.FormatUint(atomic.LoadUint64(&savepointCount), 10)
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+			if err == nil {
+				t.Error(testutil.Callers(), "savepoint was still open after it was rolled back")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+	})
+
+	t.Run("custom LoggerDB", func(t *testing.T) {
+		err := WithTx(ctx, customLoggerDB{Logger: defaultLogger, DB: db}, nil, func(tx DB) error {
+			return nil
+		})
+		if err == nil {
+			t.Error(testutil.Callers(), "expected an error for a LoggerDB that cannot be unwrapped")
+		}
+	})
+}
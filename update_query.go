@@ -29,6 +29,7 @@ type UpdateQuery struct {
 	RowOffset sql.NullInt64
 	// RETURNING
 	ReturningFields AliasFields
+	ReturningInto   []interface{}
 }
 
 var _ Query = UpdateQuery{}
@@ -59,12 +60,29 @@ func (q UpdateQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 	if q.UpdateTable == nil {
 		return fmt.Errorf("no table provided to UPDATE")
 	}
-	err = q.UpdateTable.AppendSQL(dialect, buf, args, params, env)
-	if err != nil {
-		return fmt.Errorf("UPDATE: %w", err)
+	// TOP (sqlserver)
+	if q.RowLimit.Valid && dialect == DialectSQLServer {
+		err = BufferPrintf(dialect, buf, args, params, env, nil, "TOP ({}) ", []interface{}{q.RowLimit.Int64})
+		if err != nil {
+			return fmt.Errorf("TOP: %w", err)
+		}
+	}
+	// sqlserver does not allow an alias after UPDATE, the aliased table has
+	// to be declared in the FROM clause instead.
+	alias := q.UpdateTable.GetAlias()
+	sqlServerAlias := dialect == DialectSQLServer && alias != ""
+	if sqlServerAlias {
+		buf.WriteString(QuoteIdentifier(dialect, alias))
+	} else {
+		err = q.UpdateTable.AppendSQL(dialect, buf, args, params, env)
+		if err != nil {
+			return fmt.Errorf("UPDATE: %w", err)
+		}
 	}
-	if alias := q.UpdateTable.GetAlias(); alias != "" {
-		buf.WriteString(" AS " + QuoteIdentifier(dialect, alias))
+	if alias != "" {
+		if !sqlServerAlias {
+			writeTableAlias(dialect, buf, alias)
+		}
 		excludedTableQualifiers = append(excludedTableQualifiers, alias)
 	} else {
 		name := q.UpdateTable.GetName()
@@ -81,24 +99,46 @@ func (q UpdateQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("SET: %w", err)
 		}
 	}
+	// OUTPUT (sqlserver)
+	if len(q.ReturningFields) > 0 && dialect == DialectSQLServer {
+		buf.WriteString(" OUTPUT ")
+		err = appendOutputFields(dialect, buf, args, params, env, "INSERTED", q.ReturningFields, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("OUTPUT: %w", err)
+		}
+	}
 	// FROM
+	if sqlServerAlias {
+		buf.WriteString(" FROM ")
+		err = q.UpdateTable.AppendSQL(dialect, buf, args, params, env)
+		if err != nil {
+			return fmt.Errorf("FROM: %w", err)
+		}
+		writeTableAlias(dialect, buf, alias)
+		if q.FromTable != nil {
+			buf.WriteString(",")
+		}
+	}
 	if q.FromTable != nil {
-		if dialect == DialectMySQL {
-			return fmt.Errorf("mysql UPDATE does not support FROM")
+		if dialect == DialectMySQL || dialect == DialectOracle {
+			return fmt.Errorf("%s UPDATE does not support FROM", dialect)
 		}
-		buf.WriteString(" FROM ")
+		if !sqlServerAlias {
+			buf.WriteString(" FROM")
+		}
+		buf.WriteString(" ")
 		err = q.FromTable.AppendSQL(dialect, buf, args, params, env)
 		if err != nil {
 			return fmt.Errorf("FROM: %w", err)
 		}
 		alias := q.FromTable.GetAlias()
 		if alias != "" {
-			buf.WriteString(" AS " + QuoteIdentifier(dialect, alias))
+			writeTableAlias(dialect, buf, alias)
 		}
 	}
 	// JOIN
 	if len(q.JoinTables) > 0 {
-		if q.FromTable == nil && dialect != DialectMySQL {
+		if q.FromTable == nil && dialect != DialectMySQL && !sqlServerAlias {
 			return fmt.Errorf("%s can't JOIN without a FROM table", dialect)
 		}
 		buf.WriteString(" ")
@@ -166,7 +206,7 @@ func (q UpdateQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 		}
 	}
 	// LIMIT
-	if q.RowLimit.Valid {
+	if q.RowLimit.Valid && dialect != DialectSQLServer {
 		if dialect != DialectMySQL && dialect != DialectSQLite {
 			return fmt.Errorf("%s UPDATE does not support LIMIT", dialect)
 		}
@@ -185,8 +225,16 @@ func (q UpdateQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 			return fmt.Errorf("OFFSET: %w", err)
 		}
 	}
+	// RETURNING ... INTO (oracle)
+	if len(q.ReturningFields) > 0 && dialect == DialectOracle {
+		err = appendReturningInto(dialect, buf, args, params, env, q.ReturningFields, q.ReturningInto, excludedTableQualifiers)
+		if err != nil {
+			return fmt.Errorf("RETURNING: %w", err)
+		}
+		return nil
+	}
 	// RETURNING
-	if len(q.ReturningFields) > 0 {
+	if len(q.ReturningFields) > 0 && dialect != DialectSQLServer {
 		if dialect != DialectPostgres && dialect != DialectSQLite {
 			return fmt.Errorf("%s UPDATE does not support RETURNING", dialect)
 		}
@@ -201,7 +249,7 @@ func (q UpdateQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interf
 
 func (q UpdateQuery) SetFetchableFields(fields []Field) (Query, error) {
 	switch q.Dialect {
-	case DialectPostgres, DialectSQLite:
+	case DialectPostgres, DialectSQLite, DialectSQLServer:
 		q.ReturningFields = fields
 		return q, nil
 	default:
@@ -211,7 +259,7 @@ func (q UpdateQuery) SetFetchableFields(fields []Field) (Query, error) {
 
 func (q UpdateQuery) GetFetchableFields() ([]Field, error) {
 	switch q.Dialect {
-	case DialectPostgres, DialectSQLite:
+	case DialectPostgres, DialectSQLite, DialectSQLServer:
 		return q.ReturningFields, nil
 	default:
 		return nil, fmt.Errorf("%s UPDATE %w", q.Dialect, ErrNonFetchableQuery)
@@ -0,0 +1,74 @@
+package sq
+
+import "bytes"
+
+type OracleUpdateQuery UpdateQuery
+
+var _ Query = OracleUpdateQuery{}
+
+func (q OracleUpdateQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return UpdateQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q OracleUpdateQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return UpdateQuery(q).SetFetchableFields(fields)
+}
+
+func (q OracleUpdateQuery) GetFetchableFields() ([]Field, error) {
+	return UpdateQuery(q).GetFetchableFields()
+}
+
+func (q OracleUpdateQuery) GetDialect() string { return q.Dialect }
+
+func (d OracleQueryBuilder) UpdateWith(ctes ...CTE) OracleUpdateQuery {
+	return OracleUpdateQuery{
+		Env:     d.env,
+		Dialect: DialectOracle,
+		CTEs:    ctes,
+	}
+}
+
+func (d OracleQueryBuilder) Update(table SchemaTable) OracleUpdateQuery {
+	return OracleUpdateQuery{
+		Env:         d.env,
+		Dialect:     DialectOracle,
+		UpdateTable: table,
+	}
+}
+
+func (q OracleUpdateQuery) With(ctes ...CTE) OracleUpdateQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q OracleUpdateQuery) Update(table SchemaTable) OracleUpdateQuery {
+	q.UpdateTable = table
+	return q
+}
+
+func (q OracleUpdateQuery) Set(assignments ...Assignment) OracleUpdateQuery {
+	q.Assignments = append(q.Assignments, assignments...)
+	return q
+}
+
+func (q OracleUpdateQuery) Setx(mapper func(*Column) error) OracleUpdateQuery {
+	q.ColumnMapper = mapper
+	return q
+}
+
+func (q OracleUpdateQuery) Where(predicates ...Predicate) OracleUpdateQuery {
+	q.WherePredicate.Predicates = append(q.WherePredicate.Predicates, predicates...)
+	return q
+}
+
+func (q OracleUpdateQuery) Returning(fields ...Field) OracleUpdateQuery {
+	q.ReturningFields = append(q.ReturningFields, fields...)
+	return q
+}
+
+// Into provides the destinations that the RETURNING fields are written into.
+// Each destination should be a pointer, it is bound as an sql.Out argument.
+func (q OracleUpdateQuery) Into(dests ...interface{}) OracleUpdateQuery {
+	q.ReturningInto = append(q.ReturningInto, dests...)
+	return q
+}
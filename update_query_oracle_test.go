@@ -0,0 +1,68 @@
+package sq
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_OracleUpdateQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		var lastUpdate sql.NullTime
+		tt.item = Oracle.
+			UpdateWith(NewCTE("cte", []string{"n"}, Queryf("SELECT 1 FROM DUAL"))).
+			Update(ACTOR).
+			Setx(func(c *Column) error {
+				c.SetString(ACTOR.FIRST_NAME, "bob")
+				return nil
+			}).
+			Where(ACTOR.ACTOR_ID.EqInt64(1)).
+			Returning(ACTOR.LAST_UPDATE).
+			Into(&lastUpdate)
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1 FROM DUAL)" +
+			" UPDATE actor a" +
+			" SET first_name = :1" +
+			" WHERE a.actor_id = :2" +
+			" RETURNING last_update INTO :3"
+		tt.wantArgs = []interface{}{"bob", int64(1), sql.Out{Dest: &lastUpdate}}
+		assert(t, tt)
+	})
+
+	t.Run("UPDATE FROM", func(t *testing.T) {
+		t.Parallel()
+		ACTOR := xNEW_ACTOR("")
+		_, _, _, err := ToSQL("", UpdateQuery{
+			Dialect:     DialectOracle,
+			UpdateTable: ACTOR,
+			Assignments: Assignments{ACTOR.FIRST_NAME.SetString("bob")},
+			FromTable:   ACTOR,
+		})
+		if err == nil {
+			t.Error(testutil.Callers(), "expected error but got nil")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package sq
+
+import "bytes"
+
+type SQLServerUpdateQuery UpdateQuery
+
+var _ Query = SQLServerUpdateQuery{}
+
+func (q SQLServerUpdateQuery) AppendSQL(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}) error {
+	return UpdateQuery(q).AppendSQL(dialect, buf, args, params, env)
+}
+
+func (q SQLServerUpdateQuery) SetFetchableFields(fields []Field) (Query, error) {
+	return UpdateQuery(q).SetFetchableFields(fields)
+}
+
+func (q SQLServerUpdateQuery) GetFetchableFields() ([]Field, error) {
+	return UpdateQuery(q).GetFetchableFields()
+}
+
+func (q SQLServerUpdateQuery) GetDialect() string { return q.Dialect }
+
+func (d SQLServerQueryBuilder) UpdateWith(ctes ...CTE) SQLServerUpdateQuery {
+	return SQLServerUpdateQuery{
+		Env:     d.env,
+		Dialect: DialectSQLServer,
+		CTEs:    ctes,
+	}
+}
+
+func (d SQLServerQueryBuilder) Update(table SchemaTable) SQLServerUpdateQuery {
+	return SQLServerUpdateQuery{
+		Env:         d.env,
+		Dialect:     DialectSQLServer,
+		UpdateTable: table,
+	}
+}
+
+func (q SQLServerUpdateQuery) With(ctes ...CTE) SQLServerUpdateQuery {
+	q.CTEs = append(q.CTEs, ctes...)
+	return q
+}
+
+func (q SQLServerUpdateQuery) Update(table SchemaTable) SQLServerUpdateQuery {
+	q.UpdateTable = table
+	return q
+}
+
+func (q SQLServerUpdateQuery) Set(assignments ...Assignment) SQLServerUpdateQuery {
+	q.Assignments = append(q.Assignments, assignments...)
+	return q
+}
+
+func (q SQLServerUpdateQuery) Setx(mapper func(*Column) error) SQLServerUpdateQuery {
+	q.ColumnMapper = mapper
+	return q
+}
+
+func (q SQLServerUpdateQuery) From(table Table) SQLServerUpdateQuery {
+	q.FromTable = table
+	return q
+}
+
+func (q SQLServerUpdateQuery) Join(table Table, predicates ...Predicate) SQLServerUpdateQuery {
+	q.JoinTables = append(q.JoinTables, Join(table, predicates...))
+	return q
+}
+
+func (q SQLServerUpdateQuery) LeftJoin(table Table, predicates ...Predicate) SQLServerUpdateQuery {
+	q.JoinTables = append(q.JoinTables, LeftJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerUpdateQuery) RightJoin(table Table, predicates ...Predicate) SQLServerUpdateQuery {
+	q.JoinTables = append(q.JoinTables, RightJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerUpdateQuery) FullJoin(table Table, predicates ...Predicate) SQLServerUpdateQuery {
+	q.JoinTables = append(q.JoinTables, FullJoin(table, predicates...))
+	return q
+}
+
+func (q SQLServerUpdateQuery) CrossJoin(table Table) SQLServerUpdateQuery {
+	q.JoinTables = append(q.JoinTables, CrossJoin(table))
+	return q
+}
+
+func (q SQLServerUpdateQuery) CustomJoin(joinType JoinType, table Table, predicates ...Predicate) SQLServerUpdateQuery {
+	q.JoinTables = append(q.JoinTables, CustomJoin(joinType, table, predicates...))
+	return q
+}
+
+func (q SQLServerUpdateQuery) Where(predicates ...Predicate) SQLServerUpdateQuery {
+	q.WherePredicate.Predicates = append(q.WherePredicate.Predicates, predicates...)
+	return q
+}
+
+func (q SQLServerUpdateQuery) Top(limit int64) SQLServerUpdateQuery {
+	q.RowLimit.Valid = true
+	q.RowLimit.Int64 = limit
+	return q
+}
+
+func (q SQLServerUpdateQuery) Output(fields ...Field) SQLServerUpdateQuery {
+	q.ReturningFields = append(q.ReturningFields, fields...)
+	return q
+}
@@ -0,0 +1,99 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+func Test_SQLServerUpdateQuery(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      Query
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQL(tt.dialect, tt.item)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("joins", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("a")
+		tt.item = SQLServer.
+			Update(ACTOR).
+			With(NewCTE("cte", []string{"n"}, Queryf("SELECT 1"))).
+			Set(ACTOR.ACTOR_ID.SetInt64(1)).
+			Join(ACTOR, Eq(1, 1)).
+			LeftJoin(ACTOR, Eq(1, 1)).
+			RightJoin(ACTOR, Eq(1, 1)).
+			FullJoin(ACTOR, Eq(1, 1)).
+			CrossJoin(ACTOR).
+			CustomJoin("CROSS APPLY", ACTOR).
+			Where(ACTOR.ACTOR_ID.EqInt64(1))
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1)" +
+			" UPDATE a" +
+			" SET actor_id = @p1" +
+			" FROM actor AS a" +
+			" JOIN actor AS a ON @p2 = @p3" +
+			" LEFT JOIN actor AS a ON @p4 = @p5" +
+			" RIGHT JOIN actor AS a ON @p6 = @p7" +
+			" FULL JOIN actor AS a ON @p8 = @p9" +
+			" CROSS JOIN actor AS a" +
+			" CROSS APPLY actor AS a" +
+			" WHERE a.actor_id = @p10"
+		tt.wantArgs = []interface{}{int64(1), 1, 1, 1, 1, 1, 1, 1, 1, int64(1)}
+		assert(t, tt)
+	})
+
+	t.Run("UPDATE FROM", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		FILM, LANGUAGE := xNEW_FILM(""), xNEW_LANGUAGE("l")
+		tt.item = SQLServer.
+			Update(FILM).
+			Set(FILM.TITLE.SetString("")).
+			From(LANGUAGE).
+			Where(LANGUAGE.LANGUAGE_ID.Eq(FILM.LANGUAGE_ID), LANGUAGE.NAME.EqString("English"))
+		tt.wantQuery = "UPDATE film" +
+			" SET title = @p1" +
+			" FROM language AS l" +
+			" WHERE l.language_id = film.language_id AND l.name = @p2"
+		tt.wantArgs = []interface{}{"", "English"}
+		assert(t, tt)
+	})
+
+	t.Run("TOP with OUTPUT", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		ACTOR := xNEW_ACTOR("")
+		tt.item = SQLServer.
+			UpdateWith(NewCTE("cte", []string{"n"}, Queryf("SELECT 1"))).
+			Update(ACTOR).
+			Setx(func(c *Column) error {
+				c.SetString(ACTOR.FIRST_NAME, "bob")
+				return nil
+			}).
+			Where(ACTOR.ACTOR_ID.EqInt64(1)).
+			Top(1).
+			Output(ACTOR.ACTOR_ID, ACTOR.FIRST_NAME)
+		tt.wantQuery = "WITH cte (n) AS (SELECT 1)" +
+			" UPDATE TOP (@p1) actor" +
+			" SET first_name = @p2" +
+			" OUTPUT INSERTED.actor_id, INSERTED.first_name" +
+			" WHERE actor.actor_id = @p3"
+		tt.wantArgs = []interface{}{int64(1), "bob", int64(1)}
+		assert(t, tt)
+	})
+}
@@ -1,9 +1,16 @@
 package sq
 
-import "bytes"
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
 
 type UUIDField struct {
-	info FieldInfo
+	info       FieldInfo
+	sqliteBlob bool
 }
 
 func NewUUIDField(fieldName string, tableInfo TableInfo) UUIDField {
@@ -15,6 +22,13 @@ func NewUUIDField(fieldName string, tableInfo TableInfo) UUIDField {
 	}}
 }
 
+func UUIDFieldf(format string, values ...interface{}) UUIDField {
+	return UUIDField{info: FieldInfo{
+		Formats: [][2]string{{"default", format}},
+		Values:  values,
+	}}
+}
+
 var _ Field = (*UUIDField)(nil)
 
 func (f UUIDField) AppendSQLExclude(dialect string, buf *bytes.Buffer, args *[]interface{}, params map[string][]int, env map[string]interface{}, excludedTableQualifiers []string) error {
@@ -29,3 +43,193 @@ func (f UUIDField) As(alias string) UUIDField {
 	f.info.FieldAlias = alias
 	return f
 }
+
+func (f UUIDField) Asc() UUIDField {
+	f.info.Descending.Valid = true
+	f.info.Descending.Bool = false
+	return f
+}
+
+func (f UUIDField) Desc() UUIDField {
+	f.info.Descending.Valid = true
+	f.info.Descending.Bool = true
+	return f
+}
+
+func (f UUIDField) NullsLast() UUIDField {
+	f.info.NullsFirst.Valid = true
+	f.info.NullsFirst.Bool = false
+	return f
+}
+
+func (f UUIDField) NullsFirst() UUIDField {
+	f.info.NullsFirst.Valid = true
+	f.info.NullsFirst.Bool = true
+	return f
+}
+
+// SQLiteBlob returns a UUIDField whose UUID values are written as 16 byte
+// blobs in sqlite, for columns that store UUIDs as BLOB instead of TEXT.
+func (f UUIDField) SQLiteBlob() UUIDField {
+	f.sqliteBlob = true
+	return f
+}
+
+func (f UUIDField) IsNull() Predicate { return IsNull(f) }
+
+func (f UUIDField) IsNotNull() Predicate { return IsNotNull(f) }
+
+func (f UUIDField) In(v interface{}) Predicate { return In(f, f.uuidValue(v)) }
+
+func (f UUIDField) Eq(field UUIDField) Predicate { return Eq(f, field) }
+
+func (f UUIDField) Ne(field UUIDField) Predicate { return Ne(f, field) }
+
+func (f UUIDField) EqUUID(val [16]byte) Predicate { return Eq(f, f.uuidValue(val)) }
+
+func (f UUIDField) NeUUID(val [16]byte) Predicate { return Ne(f, f.uuidValue(val)) }
+
+func (f UUIDField) Set(val interface{}) Assignment { return Assign(f, f.uuidValue(val)) }
+
+func (f UUIDField) SetUUID(val [16]byte) Assignment { return Assign(f, f.uuidValue(val)) }
+
+// uuidValue wraps [16]byte values (and slices of them) in a UUIDValue with
+// the field's sqlite storage format. Other values are returned unchanged.
+func (f UUIDField) uuidValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case [16]byte:
+		return UUIDValue{UUID: v, SQLiteBlob: f.sqliteBlob}
+	case [][16]byte:
+		values := make([]UUIDValue, len(v))
+		for i, uuid := range v {
+			values[i] = UUIDValue{UUID: uuid, SQLiteBlob: f.sqliteBlob}
+		}
+		return values
+	case NullUUID:
+		if !v.Valid {
+			return nil
+		}
+		return UUIDValue{UUID: v.UUID, SQLiteBlob: f.sqliteBlob}
+	}
+	return v
+}
+
+// UUIDValue is a UUID query argument. It is passed to the database in the
+// representation each dialect expects for its UUID column type: raw bytes for
+// mysql's BINARY(16) and oracle's RAW(16), the canonical string everywhere
+// else. Sqlite UUIDs are stored as text unless SQLiteBlob is set.
+//
+// Plain [16]byte arguments are passed through as they are, only the UUIDField
+// methods and UUIDValue (or NullUUID) arguments get converted.
+type UUIDValue struct {
+	UUID       [16]byte
+	SQLiteBlob bool
+}
+
+// NullUUID represents a UUID that may be NULL. It can be scanned from a
+// postgres uuid, a mysql BINARY(16) or a sqlite text or blob column.
+type NullUUID struct {
+	UUID  [16]byte
+	Valid bool
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullUUID) Scan(value interface{}) error {
+	var err error
+	switch value := value.(type) {
+	case nil:
+		n.UUID, n.Valid = [16]byte{}, false
+		return nil
+	case [16]byte:
+		n.UUID = value
+	case []byte:
+		if len(value) == 16 {
+			copy(n.UUID[:], value)
+		} else {
+			n.UUID, err = ParseUUID(string(value))
+		}
+	case string:
+		n.UUID, err = ParseUUID(value)
+	default:
+		return fmt.Errorf("sq: cannot scan %T into a UUID", value)
+	}
+	if err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements the driver.Valuer interface. The UUID is returned in its
+// canonical string form, use it with a dialect-aware query builder to get
+// the dialect specific representation instead.
+func (n NullUUID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return UUIDString(n.UUID), nil
+}
+
+// ParseUUID parses the canonical 36 character form of a UUID. The 32
+// character form without hyphens and the braced form are also accepted.
+func ParseUUID(s string) ([16]byte, error) {
+	var uuid [16]byte
+	str := strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	if len(str) == 36 {
+		if str[8] != '-' || str[13] != '-' || str[18] != '-' || str[23] != '-' {
+			return uuid, fmt.Errorf("sq: invalid UUID %q", s)
+		}
+		str = str[:8] + str[9:13] + str[14:18] + str[19:23] + str[24:]
+	}
+	if len(str) != 32 {
+		return uuid, fmt.Errorf("sq: invalid UUID %q", s)
+	}
+	_, err := hex.Decode(uuid[:], []byte(str))
+	if err != nil {
+		return uuid, fmt.Errorf("sq: invalid UUID %q: %w", s, err)
+	}
+	return uuid, nil
+}
+
+// UUIDString returns the canonical 36 character form of a UUID.
+func UUIDString(uuid [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf[:])
+}
+
+// uuidArg converts UUIDValue and NullUUID values into the representation of
+// the dialect, see UUIDValue. Other values are returned unchanged.
+func uuidArg(dialect string, value interface{}) interface{} {
+	var uuid UUIDValue
+	switch v := value.(type) {
+	case UUIDValue:
+		uuid = v
+	case NullUUID:
+		if !v.Valid {
+			return nil
+		}
+		uuid.UUID = v.UUID
+	default:
+		return value
+	}
+	switch dialect {
+	case DialectMySQL, DialectOracle:
+		return uuid.UUID[:]
+	case DialectSQLite:
+		if uuid.SQLiteBlob {
+			return uuid.UUID[:]
+		}
+		return UUIDString(uuid.UUID)
+	default:
+		return UUIDString(uuid.UUID)
+	}
+}
@@ -0,0 +1,192 @@
+package sq
+
+import (
+	"testing"
+
+	"github.com/bokwoon95/sq/internal/testutil"
+)
+
+var testUUID = [16]byte{0xa0, 0xee, 0xbc, 0x99, 0x9c, 0x0b, 0x4e, 0xf8, 0xbb, 0x6d, 0x6b, 0xb9, 0xbd, 0x38, 0x0a, 0x11}
+
+const testUUIDString = "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+
+func Test_UUIDField(t *testing.T) {
+	type TT struct {
+		dialect   string
+		item      SQLExcludeAppender
+		wantQuery string
+		wantArgs  []interface{}
+	}
+
+	assert := func(t *testing.T, tt TT) {
+		gotQuery, gotArgs, _, err := ToSQLExclude(tt.dialect, tt.item, nil)
+		if err != nil {
+			t.Fatal(testutil.Callers(), err)
+		}
+		if diff := testutil.Diff(gotQuery, tt.wantQuery); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+		if diff := testutil.Diff(gotArgs, tt.wantArgs); diff != "" {
+			t.Error(testutil.Callers(), diff)
+		}
+	}
+
+	t.Run("UUIDField DESC NULLS FIRST", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.item = NewUUIDField("field", TableInfo{TableName: "tbl"}).Desc().NullsFirst()
+		tt.wantQuery = "tbl.field DESC NULLS FIRST"
+		assert(t, tt)
+	})
+
+	t.Run("postgres EqUUID", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		tt.item = NewUUIDField("field", TableInfo{TableName: "tbl"}).EqUUID(testUUID)
+		tt.wantQuery = "tbl.field = $1"
+		tt.wantArgs = []interface{}{testUUIDString}
+		assert(t, tt)
+	})
+
+	t.Run("mysql In", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectMySQL
+		tt.item = NewUUIDField("field", TableInfo{TableName: "tbl"}).In([][16]byte{testUUID, {}})
+		tt.wantQuery = "tbl.field IN (?, ?)"
+		tt.wantArgs = []interface{}{testUUID[:], make([]byte, 16)}
+		assert(t, tt)
+	})
+
+	t.Run("sqlite SetUUID", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectSQLite
+		tt.item = NewUUIDField("field", TableInfo{TableName: "tbl"}).SetUUID(testUUID)
+		tt.wantQuery = "tbl.field = $1"
+		tt.wantArgs = []interface{}{testUUIDString}
+		assert(t, tt)
+	})
+
+	t.Run("sqlite SQLiteBlob EqUUID", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectSQLite
+		tt.item = NewUUIDField("field", TableInfo{TableName: "tbl"}).SQLiteBlob().EqUUID(testUUID)
+		tt.wantQuery = "tbl.field = $1"
+		tt.wantArgs = []interface{}{testUUID[:]}
+		assert(t, tt)
+	})
+
+	t.Run("[16]byte is not a UUID", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		tt.item = Predicatef("digest = {}", testUUID)
+		tt.wantQuery = "digest = $1"
+		tt.wantArgs = []interface{}{testUUID}
+		assert(t, tt)
+	})
+
+	t.Run("Param NullUUID", func(t *testing.T) {
+		t.Parallel()
+		var tt TT
+		tt.dialect = DialectPostgres
+		tt.item = NewUUIDField("field", TableInfo{TableName: "tbl"}).Set(Param("id", NullUUID{}))
+		tt.wantQuery = "tbl.field = $1"
+		tt.wantArgs = []interface{}{nil}
+		assert(t, tt)
+	})
+}
+
+func Test_NullUUID(t *testing.T) {
+	tests := []struct {
+		description string
+		src         interface{}
+		want        NullUUID
+	}{
+		{"nil", nil, NullUUID{}},
+		{"postgres pgx", testUUID, NullUUID{UUID: testUUID, Valid: true}},
+		{"postgres lib/pq", []byte(testUUIDString), NullUUID{UUID: testUUID, Valid: true}},
+		{"mysql BINARY(16)", testUUID[:], NullUUID{UUID: testUUID, Valid: true}},
+		{"sqlite text", testUUIDString, NullUUID{UUID: testUUID, Valid: true}},
+		{"no hyphens", "a0eebc999c0b4ef8bb6d6bb9bd380a11", NullUUID{UUID: testUUID, Valid: true}},
+		{"braces", "{" + testUUIDString + "}", NullUUID{UUID: testUUID, Valid: true}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.description, func(t *testing.T) {
+			t.Parallel()
+			var got NullUUID
+			err := got.Scan(tt.src)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(got, tt.want); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, src := range []interface{}{"abc", "a0eebc99x9c0b-4ef8-bb6d-6bb9bd380a11", "z0eebc999c0b4ef8bb6d6bb9bd380a11", 123} {
+			var got NullUUID
+			if err := got.Scan(src); err == nil {
+				t.Errorf(testutil.Callers()+" %v: expected error but got nil", src)
+			}
+		}
+	})
+
+	t.Run("Sprint", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			dialect string
+			value   interface{}
+			want    string
+		}{
+			{DialectPostgres, UUIDValue{UUID: testUUID}, `'` + testUUIDString + `'`},
+			{DialectSQLite, UUIDValue{UUID: testUUID}, `'` + testUUIDString + `'`},
+			{DialectSQLite, UUIDValue{UUID: testUUID, SQLiteBlob: true}, `x'a0eebc999c0b4ef8bb6d6bb9bd380a11'`},
+			{DialectMySQL, UUIDValue{UUID: testUUID}, `x'a0eebc999c0b4ef8bb6d6bb9bd380a11'`},
+			{DialectOracle, NullUUID{UUID: testUUID, Valid: true}, `HEXTORAW('a0eebc999c0b4ef8bb6d6bb9bd380a11')`},
+			{DialectPostgres, NullUUID{}, `NULL`},
+		}
+		for _, tt := range tests {
+			got, err := Sprint(tt.dialect, tt.value)
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			if diff := testutil.Diff(got, tt.want); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		}
+	})
+}
+
+func TestSQLiteUUID(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+	for _, format := range []string{"'" + testUUIDString + "'", "x'a0eebc999c0b4ef8bb6d6bb9bd380a11'", "NULL"} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+			field := UUIDFieldf(format)
+			got, err := FetchOne(sqliteDB, SQLite.Select(), func(row *Row) NullUUID {
+				return row.NullUUID(field)
+			})
+			if err != nil {
+				t.Fatal(testutil.Callers(), err)
+			}
+			want := NullUUID{UUID: testUUID, Valid: true}
+			if format == "NULL" {
+				want = NullUUID{}
+			}
+			if diff := testutil.Diff(got, want); diff != "" {
+				t.Error(testutil.Callers(), diff)
+			}
+		})
+	}
+}